@@ -0,0 +1,531 @@
+// Command apigen generates api models and gin server interface from the OpenAPI spec.
+// It supports only the subset of OpenAPI 3.0 used by pkg/api/api.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+var methods = []string{"get", "post", "put", "patch", "delete"}
+
+type Spec struct {
+	Paths      map[string]map[string]*Operation `yaml:"paths"`
+	Components struct {
+		Schemas         map[string]*Schema         `yaml:"schemas"`
+		Parameters      map[string]*Parameter      `yaml:"parameters"`
+		SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+	} `yaml:"components"`
+}
+
+type SecurityScheme struct {
+	Type   string `yaml:"type"`
+	In     string `yaml:"in"`
+	Name   string `yaml:"name"`
+	GoName string `yaml:"x-go-name"`
+}
+
+type Operation struct {
+	OperationID string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary"`
+	Security    []map[string][]string `yaml:"security"`
+	Parameters  []*Parameter          `yaml:"parameters"`
+	GoParams    map[string]string     `yaml:"x-go-params"`
+}
+
+type Parameter struct {
+	Ref       string  `yaml:"$ref"`
+	Name      string  `yaml:"name"`
+	In        string  `yaml:"in"`
+	Required  bool    `yaml:"required"`
+	Schema    *Schema `yaml:"schema"`
+	GoName    string  `yaml:"x-go-name"`
+	GoBinding string  `yaml:"x-go-binding"`
+	Wildcard  bool    `yaml:"x-go-wildcard"`
+}
+
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Description          string             `yaml:"description"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Required             []string           `yaml:"required"`
+	Items                *Schema            `yaml:"items"`
+	Minimum              *int               `yaml:"minimum"`
+	MinItems             *int               `yaml:"minItems"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+	AdditionalProperties *Additional        `yaml:"additionalProperties"`
+	SkipOptionalPointer  bool               `yaml:"x-go-type-skip-optional-pointer"`
+	OmitEmpty            bool               `yaml:"x-omitempty"`
+}
+
+// Additional is either a boolean or the schema of values of the map
+type Additional struct {
+	Schema *Schema
+}
+
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	var allowed bool
+	if node.Decode(&allowed) == nil {
+		return nil
+	}
+	a.Schema = &Schema{}
+	return node.Decode(a.Schema)
+}
+
+type Field struct {
+	Name string
+	Type string
+	Tags string
+}
+
+type Struct struct {
+	Name        string
+	Description string
+	Fields      []Field
+}
+
+type Alias struct {
+	Name        string
+	Description string
+	Type        string
+}
+
+type Route struct {
+	Method    string
+	Path      string
+	Handler   string
+	Summary   string
+	Security  string
+	GoPattern string
+}
+
+func main() {
+	specPath := flag.String("spec", "api.yaml", "path to OpenAPI spec")
+	modelsPath := flag.String("models", "", "output file for models")
+	modelsPkg := flag.String("models-package", "api", "package of models file")
+	serverPath := flag.String("server", "", "output file for server interface")
+	serverPkg := flag.String("server-package", "handlers", "package of server file")
+	flag.Parse()
+
+	if err := run(*specPath, *modelsPath, *modelsPkg, *serverPath, *serverPkg); err != nil {
+		fmt.Fprintln(os.Stderr, "apigen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, modelsPath, modelsPkg, serverPath, serverPkg string) error {
+	raw, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	spec := &Spec{}
+	if err = yaml.Unmarshal(raw, spec); err != nil {
+		return err
+	}
+	if modelsPath != "" {
+		if err = generate(modelsPath, modelsTemplate, modelsPkg, spec.models); err != nil {
+			return err
+		}
+	}
+	if serverPath != "" {
+		if err = generate(serverPath, serverTemplate, serverPkg, spec.routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generate(path string, tmpl *template.Template, pkg string, data func() (any, error)) error {
+	d, err := data()
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, map[string]any{"Package": pkg, "Data": d}); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %w\n%s", path, err, buf.String())
+	}
+	return os.WriteFile(path, src, 0644)
+}
+
+func (s *Spec) models() (any, error) {
+	var aliases []Alias
+	var structs []Struct
+	for _, name := range sortedKeys(s.Components.Schemas) {
+		schema := s.Components.Schemas[name]
+		if schema.Type == "object" && len(schema.Properties) == 0 {
+			aliases = append(aliases, Alias{Name: name, Description: schema.Description, Type: "map[string]interface{}"})
+			continue
+		}
+		st, err := s.schemaStruct(name, schema)
+		if err != nil {
+			return nil, err
+		}
+		structs = append(structs, st)
+	}
+	params, err := s.paramStructs()
+	if err != nil {
+		return nil, err
+	}
+	structs = append(structs, params...)
+	structs = append(structs, s.securityStructs()...)
+	usesTime := false
+	for _, st := range structs {
+		for _, f := range st.Fields {
+			usesTime = usesTime || strings.Contains(f.Type, "time.Time")
+		}
+	}
+	return map[string]any{"Aliases": aliases, "Structs": structs, "UsesTime": usesTime}, nil
+}
+
+func (s *Spec) schemaStruct(name string, schema *Schema) (Struct, error) {
+	st := Struct{Name: name, Description: schema.Description}
+	required := make(map[string]bool)
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+	for _, prop := range sortedKeys(schema.Properties) {
+		ps := schema.Properties[prop]
+		typ, err := s.goType(ps)
+		if err != nil {
+			return st, fmt.Errorf("%s.%s: %w", name, prop, err)
+		}
+		if !ps.SkipOptionalPointer {
+			typ = "*" + typ
+		}
+		tags := fmt.Sprintf(`json:"%s"`, prop)
+		if ps.OmitEmpty {
+			tags = fmt.Sprintf(`json:"%s,omitempty"`, prop)
+		}
+		if binding := bindingTag(s.resolve(ps), required[prop]); binding != "" {
+			tags += fmt.Sprintf(` binding:"%s"`, binding)
+		}
+		st.Fields = append(st.Fields, Field{Name: goName(prop), Type: typ, Tags: tags})
+	}
+	return st, nil
+}
+
+// paramStructs groups operation parameters by location. Structs with the same name must be identical
+func (s *Spec) paramStructs() ([]Struct, error) {
+	byName := make(map[string]Struct)
+	for _, op := range s.operations() {
+		groups := make(map[string][]Field)
+		for _, p := range op.Parameters {
+			param, err := s.parameter(p)
+			if err != nil {
+				return nil, err
+			}
+			field, err := s.paramField(param)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", op.OperationID, param.Name, err)
+			}
+			groups[param.In] = append(groups[param.In], field)
+		}
+		for in, fields := range groups {
+			name := op.GoParams[in]
+			if name == "" {
+				name = op.OperationID + goName(in) + "Params"
+			}
+			st := Struct{Name: name, Fields: fields}
+			if prev, ok := byName[name]; ok && fmt.Sprint(prev) != fmt.Sprint(st) {
+				return nil, fmt.Errorf("params struct %s is declared differently in %s", name, op.OperationID)
+			}
+			byName[name] = st
+		}
+	}
+	var result []Struct
+	for _, name := range sortedKeys(byName) {
+		result = append(result, byName[name])
+	}
+	return result, nil
+}
+
+// securityStructs generates header structs for api key schemes with x-go-name
+func (s *Spec) securityStructs() []Struct {
+	byName := make(map[string]Struct)
+	for _, name := range sortedKeys(s.Components.SecuritySchemes) {
+		scheme := s.Components.SecuritySchemes[name]
+		if scheme.GoName == "" || scheme.Type != "apiKey" || scheme.In != "header" {
+			continue
+		}
+		byName[scheme.GoName] = Struct{Name: scheme.GoName, Fields: []Field{{
+			Name: goName(scheme.Name),
+			Type: "string",
+			Tags: fmt.Sprintf(`header:"%s" binding:"required"`, scheme.Name),
+		}}}
+	}
+	var result []Struct
+	for _, name := range sortedKeys(byName) {
+		result = append(result, byName[name])
+	}
+	return result
+}
+
+func (s *Spec) paramField(p *Parameter) (Field, error) {
+	typ, err := s.goType(p.Schema)
+	if err != nil {
+		return Field{}, err
+	}
+	tagName := map[string]string{"path": "uri", "query": "form", "header": "header"}[p.In]
+	if tagName == "" {
+		return Field{}, fmt.Errorf("unsupported parameter location %q", p.In)
+	}
+	// zero value can't be distinguished from missing one, so pointer is used when zero is valid
+	valueType := p.Required && (typ == "string" || (p.Schema.Minimum != nil && *p.Schema.Minimum > 0))
+	if !valueType {
+		typ = "*" + typ
+	}
+	binding := p.GoBinding
+	if binding == "" {
+		binding = bindingTag(p.Schema, p.Required)
+	}
+	tags := fmt.Sprintf(`%s:"%s"`, tagName, p.Name)
+	if binding != "" {
+		tags += fmt.Sprintf(` binding:"%s"`, binding)
+	}
+	name := p.GoName
+	if name == "" {
+		name = goName(p.Name)
+	}
+	return Field{Name: name, Type: typ, Tags: tags}, nil
+}
+
+func (s *Spec) routes() (any, error) {
+	var routes []Route
+	for _, path := range sortedKeys(s.Paths) {
+		for _, method := range methods {
+			op, ok := s.Paths[path][method]
+			if !ok {
+				continue
+			}
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s: missing operationId", method, path)
+			}
+			route := Route{
+				Method:  "http.Method" + strings.ToUpper(method[:1]) + method[1:],
+				Path:    path,
+				Handler: op.OperationID,
+				Summary: op.Summary,
+			}
+			if len(op.Security) > 0 {
+				for scheme := range op.Security[0] {
+					route.Security = scheme
+				}
+			}
+			pattern, err := s.ginPath(path, op)
+			if err != nil {
+				return nil, err
+			}
+			route.GoPattern = pattern
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+// ginPath converts {param} to :param, or to *param for wildcard parameters
+func (s *Spec) ginPath(path string, op *Operation) (string, error) {
+	for _, p := range op.Parameters {
+		param, err := s.parameter(p)
+		if err != nil {
+			return "", err
+		}
+		if param.In != "path" {
+			continue
+		}
+		prefix := ":"
+		if param.Wildcard {
+			prefix = "*"
+		}
+		path = strings.ReplaceAll(path, "{"+param.Name+"}", prefix+param.Name)
+	}
+	if strings.ContainsAny(path, "{}") {
+		return "", fmt.Errorf("%s: undeclared path parameter", path)
+	}
+	return path, nil
+}
+
+func (s *Spec) operations() []*Operation {
+	var result []*Operation
+	for _, path := range sortedKeys(s.Paths) {
+		for _, method := range methods {
+			if op, ok := s.Paths[path][method]; ok {
+				result = append(result, op)
+			}
+		}
+	}
+	return result
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+	param, ok := s.Components.Parameters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %s", p.Ref)
+	}
+	return param, nil
+}
+
+func (s *Spec) resolve(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+	return s.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+}
+
+func (s *Spec) goType(schema *Schema) (string, error) {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if _, ok := s.Components.Schemas[name]; !ok {
+			return "", fmt.Errorf("unknown schema %s", schema.Ref)
+		}
+		return name, nil
+	}
+	switch schema.Type {
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "string":
+		if schema.Format == "date-time" {
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "array":
+		if schema.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		item, err := s.goType(schema.Items)
+		return "[]" + item, err
+	case "object":
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			value, err := s.goType(schema.AdditionalProperties.Schema)
+			return "map[string]" + value, err
+		}
+		return "map[string]interface{}", nil
+	case "":
+		// schema without type accepts any json value
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported type %q", schema.Type)
+}
+
+func bindingTag(schema *Schema, required bool) string {
+	var rules []string
+	if schema.Minimum != nil {
+		rules = append(rules, fmt.Sprintf("gte=%d", *schema.Minimum))
+	}
+	if schema.MinItems != nil {
+		rules = append(rules, fmt.Sprintf("gte=%d", *schema.MinItems))
+	}
+	if schema.MinLength != nil {
+		rules = append(rules, fmt.Sprintf("min=%d", *schema.MinLength))
+	}
+	if schema.MaxLength != nil {
+		rules = append(rules, fmt.Sprintf("max=%d", *schema.MaxLength))
+	}
+	// item rules are applied to every element
+	itemRules := schema.Items != nil && schema.Items.Minimum != nil
+	switch {
+	case required:
+		rules = append([]string{"required"}, rules...)
+	case len(rules) > 0 || itemRules:
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	if itemRules {
+		rules = append(rules, "dive", fmt.Sprintf("gte=%d", *schema.Items.Minimum))
+	}
+	return strings.Join(rules, ",")
+}
+
+// goName converts snake_case to CamelCase keeping Id suffixes as in the rest of the code
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var modelsTemplate = template.Must(template.New("models").Parse(`// Code generated by apigen from api.yaml. DO NOT EDIT.
+
+package {{.Package}}
+{{if .Data.UsesTime}}
+import "time"
+{{end}}
+{{- range .Data.Aliases}}
+{{if .Description}}// {{.Name}} {{.Description}}
+{{end}}type {{.Name}} = {{.Type}}
+{{end}}
+{{- range .Data.Structs}}
+{{if .Description}}// {{.Name}} {{.Description}}
+{{end}}type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`{{.Tags}}`" + `
+{{- end}}
+}
+{{end}}`))
+
+var serverTemplate = template.Must(template.New("server").Parse(`// Code generated by apigen from api.yaml. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ServerInterface represents all routes described in api.yaml
+type ServerInterface interface {
+{{- range .Data}}
+	// {{.Handler}} {{.Summary}}
+	// ({{.Path}})
+	{{.Handler}}(c *gin.Context)
+{{- end}}
+}
+
+// RegisterHandlers registers routes from api.yaml.
+// Security maps security scheme names to middlewares which are run before the handler
+func RegisterHandlers(router gin.IRoutes, si ServerInterface, security map[string][]gin.HandlerFunc) {
+{{- range .Data}}
+	router.Handle({{.Method}}, "{{.GoPattern}}", withMiddlewares(security[{{printf "%q" .Security}}], si.{{.Handler}})...)
+{{- end}}
+}
+
+func withMiddlewares(middlewares []gin.HandlerFunc, handler gin.HandlerFunc) []gin.HandlerFunc {
+	result := make([]gin.HandlerFunc, 0, len(middlewares)+1)
+	result = append(result, middlewares...)
+	return append(result, handler)
+}
+`))
@@ -13,6 +13,12 @@ type HandlerGetter interface {
 	GetHandler() http.Handler
 }
 
+// StreamCloser is implemented by handlers keeping streams open, the server waits only for requests which finish
+// on their own, so streams are closed first
+type StreamCloser interface {
+	CloseStreams()
+}
+
 type HTTPServerApp struct {
 	httpConfig    *config.HTTPConfig
 	server        *http.Server
@@ -58,6 +64,9 @@ func (g *HTTPServerApp) Stop(ctx context.Context) {
 	op := "ginapp.stop"
 	log := g.logger.With(utils.Text(op))
 	log.Info("Shutting down server...")
+	if closer, ok := g.handlerGetter.(StreamCloser); ok {
+		closer.CloseStreams()
+	}
 	if g.server == nil {
 		log.Info("nothing to shutdown")
 		return
@@ -4,6 +4,7 @@ import (
 	"BannerFlow/internal/app/ginapp"
 	"BannerFlow/internal/auth"
 	"BannerFlow/internal/config"
+	"BannerFlow/internal/domain/identity"
 	"BannerFlow/internal/handlers"
 	"BannerFlow/internal/repo/cache"
 	"BannerFlow/internal/repo/db"
@@ -31,7 +32,6 @@ type StoppableService interface {
 
 type SSO interface {
 	handlers.Authenticator
-	handlers.Authorizer
 	handlers.TokenGenerator
 }
 
@@ -58,7 +58,14 @@ func (p *Provider) Server() HTTPServer {
 func (p *Provider) HandlerGetter() ginapp.HandlerGetter {
 	if p.handlerGetter == nil {
 		// TODO add auth
-		p.handlerGetter = handlers.New(p.Service(), p.SSO(), p.SSO(), p.SSO(), p.logger)
+		builder := handlers.New(p.Service(), p.SSO(), p.SSO(), p.logger)
+		builder.SetHeartbeat(p.cfg.GinCfg.Stream.Heartbeat)
+		if validation := p.cfg.GinCfg.Validation; validation.Requests {
+			if err := builder.EnableValidation(validation.Responses); err != nil {
+				panic(err)
+			}
+		}
+		p.handlerGetter = builder
 	}
 	return p.handlerGetter
 }
@@ -86,7 +93,13 @@ func (p *Provider) Db() banner.Database {
 
 func (p *Provider) SSO() SSO {
 	if p.sso == nil {
-		p.sso = auth.NewAuth()
+		sso := auth.NewAuth()
+		if p.cfg.AuthCfg != nil {
+			for _, key := range p.cfg.AuthCfg.APIKeys {
+				sso.AddAPIKey(key.Key, identity.Identity{Actor: key.Subject, IsAdmin: key.Admin, Roles: key.Roles, Tenant: key.Tenant})
+			}
+		}
+		p.sso = sso
 	}
 	return p.sso
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"BannerFlow/internal/domain/identity"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"sync"
@@ -13,40 +14,66 @@ var jwtKey = []byte("my_secret_key")
 
 // Claims структура, включает стандартные jwt.Claims и пользовательские поля
 type Claims struct {
-	IsAdmin bool `json:"is_admin"`
+	IsAdmin bool     `json:"is_admin"`
+	Roles   []string `json:"roles,omitempty"`
+	Tenant  string   `json:"tenant,omitempty"`
 	jwt.StandardClaims
 }
 
 type Auth struct {
 	tokens map[string]bool
-	mu     sync.RWMutex
+	// keys are api keys with identities of their owners
+	keys map[string]identity.Identity
+	mu   sync.RWMutex
 }
 
 func NewAuth() *Auth {
-	return &Auth{tokens: make(map[string]bool)}
+	return &Auth{tokens: make(map[string]bool), keys: make(map[string]identity.Identity)}
 }
 
-func (a *Auth) Authenticate(token string) error {
-	_, err := validateJWT(token)
-	if err != nil {
-		return err
+// AddAPIKey принимает ключ key как учетные данные id
+func (a *Auth) AddAPIKey(key string, id identity.Identity) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys[key] = id
+}
+
+// AuthenticateKey возвращает владельца api ключа
+func (a *Auth) AuthenticateKey(key string) (*identity.Identity, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	id, ok := a.keys[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown api key")
 	}
-	return nil
+	return &id, nil
 }
 
-func (a *Auth) IsAdmin(token string) bool {
-	claims, _ := validateJWT(token)
-	if claims.IsAdmin {
-		return true
+func (a *Auth) Authenticate(token string) (*identity.Identity, error) {
+	claims, err := validateJWT(token)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return &identity.Identity{Actor: claims.Subject, IsAdmin: claims.IsAdmin, Roles: claims.Roles, Tenant: claims.Tenant}, nil
 }
 
 func (a *Auth) GenerateToken(isAdmin bool) (string, error) {
+	subject := "user"
+	if isAdmin {
+		subject = "admin"
+	}
+	return a.IssueToken(&identity.Identity{Actor: subject, IsAdmin: isAdmin})
+}
+
+// IssueToken генерирует токен пользователя id с его ролями и тенантом
+func (a *Auth) IssueToken(id *identity.Identity) (string, error) {
 	expirationTime := time.Now().Add(1 * Expiration)
 	claims := &Claims{
-		IsAdmin: isAdmin,
+		IsAdmin: id.IsAdmin,
+		Roles:   id.Roles,
+		Tenant:  id.Tenant,
 		StandardClaims: jwt.StandardClaims{
+			Subject:   id.Actor,
 			ExpiresAt: expirationTime.Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
@@ -21,11 +21,85 @@ type Config struct {
 	RedisCfg    *RedisConfig    `yaml:"redis" env-required:"true"`
 	CacheCfg    *CacheConfig    `yaml:"cache" env-required:"true"`
 	ServiceCfg  *ServiceConfig  `yaml:"service"`
+	AuthCfg     *AuthConfig     `yaml:"auth"`
 	InitTimeout time.Duration   `yaml:"init_timeout" env-default:"5s"`
 }
 
 type ServiceConfig struct {
-	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+	Timeout    time.Duration    `yaml:"timeout" env-default:"5s"`
+	History    HistoryConfig    `yaml:"history"`
+	Trash      TrashConfig      `yaml:"trash"`
+	BulkDelete BulkDeleteConfig `yaml:"bulk_delete"`
+	Approval   ApprovalConfig   `yaml:"approval"`
+	Tenants    TenantsConfig    `yaml:"tenants"`
+	Events     EventsConfig     `yaml:"events"`
+	Webhooks   WebhooksConfig   `yaml:"webhooks"`
+}
+
+// HistoryConfig sets retention of banner versions, zero max_versions and max_age keep every version
+type HistoryConfig struct {
+	MaxVersions   int           `yaml:"max_versions" env-default:"0"`
+	MaxAge        time.Duration `yaml:"max_age" env-default:"0"`
+	PruneInterval time.Duration `yaml:"prune_interval" env-default:"1h"`
+}
+
+// TrashConfig sets how long deleted banners can be restored, zero retention keeps them forever
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// BulkDeleteConfig requires the token of the preview to delete more banners than confirm_threshold at once,
+// zero threshold deletes any number without it
+type BulkDeleteConfig struct {
+	ConfirmThreshold int `yaml:"confirm_threshold" env-default:"100"`
+}
+
+// ApprovalConfig makes changes of banners wait for approval of a reviewer before users get them,
+// without it changes are applied at once
+type ApprovalConfig struct {
+	Required bool `yaml:"required" env-default:"false"`
+}
+
+// TenantsConfig limits banners kept by every tenant, max_banners applies to tenants missing in quotas.
+// Zero limit allows any number of banners
+type TenantsConfig struct {
+	MaxBanners int            `yaml:"max_banners" env-default:"0"`
+	Quotas     map[string]int `yaml:"quotas"`
+}
+
+// EventsConfig sets how long banner events are kept, streams can be resumed only from events kept
+type EventsConfig struct {
+	Retention     time.Duration `yaml:"retention" env-default:"24h"`
+	PruneInterval time.Duration `yaml:"prune_interval" env-default:"1h"`
+}
+
+// WebhooksConfig sets how deliveries of banner events are sent. Failed deliveries are retried with backoff
+// doubling from min_backoff up to max_backoff, after max_attempts they are dead letters.
+// Delivered ones are kept for retention, dead letters are kept until retried
+type WebhooksConfig struct {
+	PollInterval  time.Duration `yaml:"poll_interval" env-default:"1s"`
+	BatchSize     int           `yaml:"batch_size" env-default:"100"`
+	Timeout       time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts   int           `yaml:"max_attempts" env-default:"8"`
+	MinBackoff    time.Duration `yaml:"min_backoff" env-default:"10s"`
+	MaxBackoff    time.Duration `yaml:"max_backoff" env-default:"1h"`
+	Retention     time.Duration `yaml:"retention" env-default:"168h"`
+	PruneInterval time.Duration `yaml:"prune_interval" env-default:"1h"`
+}
+
+// AuthConfig lists api keys accepted in the X-API-Key header instead of tokens
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+}
+
+// APIKeyConfig describes who performs requests made with the key
+type APIKeyConfig struct {
+	Key     string   `yaml:"key"`
+	Subject string   `yaml:"subject"`
+	Tenant  string   `yaml:"tenant"`
+	Admin   bool     `yaml:"admin"`
+	Roles   []string `yaml:"roles"`
 }
 
 type PostgresConfig struct {
@@ -44,7 +118,20 @@ type RedisConfig struct {
 }
 
 type HTTPConfig struct {
-	Address string `yaml:"address" env-default:":8080"`
+	Address    string           `yaml:"address" env-default:":8080"`
+	Validation ValidationConfig `yaml:"validation"`
+	Stream     StreamConfig     `yaml:"stream"`
+}
+
+// ValidationConfig enables validation against api.yaml. Responses are validated only with requests
+type ValidationConfig struct {
+	Requests  bool `yaml:"requests" env-default:"false"`
+	Responses bool `yaml:"responses" env-default:"false"`
+}
+
+// StreamConfig sets how often comments are sent to idle event streams, so proxies keep them open
+type StreamConfig struct {
+	Heartbeat time.Duration `yaml:"heartbeat" env-default:"15s"`
 }
 
 func MustLoad() *Config {
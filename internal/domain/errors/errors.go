@@ -3,6 +3,7 @@ package e
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -11,10 +12,48 @@ var (
 	ErrorAuthenticationFailed = errors.New("authentication failed")
 	ErrorNoPermission         = errors.New("error no permission")
 	ErrorNotFound             = errors.New("banner not found")
+	ErrorPreconditionFailed   = errors.New("banner was changed, entity tag does not match")
+	ErrorRolledBack           = errors.New("operation was rolled back, another operation of the batch failed")
+	ErrorStaleConfirmation    = errors.New("banners to delete changed since the preview, confirm_token does not match")
+	ErrorQuotaExceeded        = errors.New("quota of banners of the tenant is exceeded")
 
 	ErrorFailedToConnect = fmt.Errorf("%w: failed to connect", ErrorInternal)
 	ErrorConflict        = fmt.Errorf("%w: banner already exists", ErrorBadRequest)
 	ErrorInRequestBody   = fmt.Errorf("%w: error in request body", ErrorBadRequest)
 	ErrorInParam         = fmt.Errorf("%w: error in param", ErrorBadRequest)
 	ErrorNoToken         = fmt.Errorf("%w: error no token", ErrorAuthenticationFailed)
+	ErrorInvalidSchema   = fmt.Errorf("%w: invalid json schema", ErrorBadRequest)
+	ErrorNameConflict    = fmt.Errorf("%w: name or id is already taken", ErrorBadRequest)
+	ErrorInvalidState    = fmt.Errorf("%w: action is not allowed in the current state of the banner", ErrorBadRequest)
+	ErrorReviewRequired  = fmt.Errorf("%w: changes of banners require review", ErrorBadRequest)
+	ErrorTagInUse        = fmt.Errorf("%w: tag is used by banners", ErrorBadRequest)
 )
+
+var ErrorValidation = fmt.Errorf("%w: validation failed", ErrorBadRequest)
+
+// FieldViolation describes a single invalid field, Field is a path like body.tag_ids[0]
+type FieldViolation struct {
+	Field   string
+	Message string
+}
+
+// ValidationError is a bad request error carrying every invalid field
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func NewValidationError(violations ...FieldViolation) *ValidationError {
+	return &ValidationError{Violations: violations}
+}
+
+func (v *ValidationError) Error() string {
+	msgs := make([]string, 0, len(v.Violations))
+	for _, violation := range v.Violations {
+		msgs = append(msgs, violation.Field+": "+violation.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrorValidation.Error(), strings.Join(msgs, "; "))
+}
+
+func (v *ValidationError) Unwrap() error {
+	return ErrorValidation
+}
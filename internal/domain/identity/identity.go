@@ -0,0 +1,37 @@
+package identity
+
+import (
+	"context"
+	"slices"
+)
+
+// RoleReviewer approves and rejects changes of banners made by other users
+const RoleReviewer = "reviewer"
+
+// Identity describes who performs the request
+type Identity struct {
+	// Actor is recorded in banner history, empty for anonymous requests
+	Actor   string
+	IsAdmin bool
+	Roles   []string
+	// Tenant owns every banner, feature and tag the request sees, empty for the default tenant
+	Tenant string
+}
+
+func (i *Identity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+type ctxKey struct{}
+
+func With(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// From returns identity of the request, anonymous one if it is missing
+func From(ctx context.Context) *Identity {
+	if id, ok := ctx.Value(ctxKey{}).(*Identity); ok && id != nil {
+		return id
+	}
+	return &Identity{}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type ListSort string
+
+const (
+	SortById      ListSort = "id"
+	SortByCreated ListSort = "created"
+	SortByUpdated ListSort = "updated"
+	// SortByRank orders results of the search by relevance, it is not a sort of the listing
+	SortByRank ListSort = "rank"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListCursor is the position after the last banner of the page. Time or Rank is the sort value of the banner,
+// Id breaks ties between banners with the same value
+type ListCursor struct {
+	Sort ListSort  `json:"s"`
+	Desc bool      `json:"d,omitempty"`
+	Time time.Time `json:"t"`
+	Rank float32   `json:"r,omitempty"`
+	Id   int       `json:"i"`
+}
+
+// CursorAfter is the cursor of the page following the banner
+func CursorAfter(banner *BannerExt, sort ListSort, desc bool) *ListCursor {
+	cursor := &ListCursor{Sort: sort, Desc: desc, Id: banner.BannerId}
+	switch sort {
+	case SortByCreated:
+		cursor.Time = banner.CreatedAt
+	case SortByUpdated:
+		cursor.Time = banner.UpdatedAt
+	}
+	return cursor
+}
+
+// CursorAfterHit is the cursor of the search page following the hit
+func CursorAfterHit(hit *SearchHit, sort ListSort) *ListCursor {
+	cursor := &ListCursor{Sort: sort, Id: hit.BannerId}
+	if sort == SortByRank {
+		cursor.Rank = hit.Rank
+	}
+	return cursor
+}
+
+// EncodeCursor makes the opaque token returned to clients
+func EncodeCursor(cursor *ListCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses the token made by EncodeCursor
+func DecodeCursor(token string) (*ListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	cursor := &ListCursor{}
+	if err = json.Unmarshal(data, cursor); err != nil || cursor.Id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	switch cursor.Sort {
+	case SortById, SortByCreated, SortByUpdated, SortByRank:
+		return cursor, nil
+	}
+	return nil, ErrInvalidCursor
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	banner := &BannerExt{BannerId: 7, CreatedAt: time.Date(2024, 4, 1, 10, 0, 0, 123456000, time.UTC)}
+	for _, sort := range []ListSort{SortById, SortByCreated, SortByUpdated} {
+		cursor := CursorAfter(banner, sort, true)
+		decoded, err := DecodeCursor(EncodeCursor(cursor))
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	}
+
+	for _, token := range []string{"", "not base64!", "e30", EncodeCursor(&ListCursor{Sort: "name", Id: 1})} {
+		_, err := DecodeCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// BulkDeleteFilter selects live banners of the bulk delete. FeatureId and TagIds are combined, so with both
+// of them banners of the feature having any of the tags are deleted. FeatureId equal to ZeroValue, empty TagIds
+// and zero UpdatedBefore do not filter
+type BulkDeleteFilter struct {
+	FeatureId     int
+	TagIds        []int
+	InactiveOnly  bool
+	UpdatedBefore time.Time
+}
+
+// BulkDeleteOptions selects banners of the bulk delete. DryRun only previews them, ConfirmToken is the token
+// of the preview, it is required when more banners than the threshold are deleted
+type BulkDeleteOptions struct {
+	BulkDeleteFilter
+	DryRun       bool
+	ConfirmToken string
+}
+
+// DeletePreview lists banners the bulk delete removes. Token confirms the deletion of exactly these banners,
+// Job is the scheduled deletion, it is nil for a dry run
+type DeletePreview struct {
+	Ids             []int
+	Token           string
+	ConfirmRequired bool
+	Job             *DeleteJob
+}
+
+type DeleteJobStatus string
+
+const (
+	DeleteJobPending DeleteJobStatus = "pending"
+	DeleteJobDone    DeleteJobStatus = "done"
+	DeleteJobFailed  DeleteJobStatus = "failed"
+)
+
+// DeleteJob is the scheduled bulk delete of banners of the tenant. Ids are the banners found by the filter
+// when the job was scheduled, only they are deleted. RemovedIds are the ones of them moved to the trash
+// by the job, banners deleted meanwhile are missing there. They are known once the job is done
+type DeleteJob struct {
+	Id         int
+	Tenant     string
+	Filter     BulkDeleteFilter
+	Ids        []int
+	Status     DeleteJobStatus
+	RemovedIds []int
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
+// DeleteToken is the token of the preview. It depends on the filter and the found banners only, so a deletion
+// is confirmed while the same banners match the filter and needs a new preview once they change
+func DeleteToken(filter *BulkDeleteFilter, ids []int) string {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	tagIds := slices.Clone(filter.TagIds)
+	slices.Sort(tagIds)
+	normalized := *filter
+	normalized.TagIds = slices.Compact(tagIds)
+	// the struct is encoded in the order of fields, equal filters are encoded equally
+	data, _ := json.Marshal(struct {
+		Filter BulkDeleteFilter
+		Ids    []int
+	}{normalized, sorted})
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
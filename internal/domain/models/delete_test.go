@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteToken(t *testing.T) {
+	byFeature := &BulkDeleteFilter{FeatureId: 1}
+	token := DeleteToken(byFeature, []int{3, 1, 2})
+	assert.Equal(t, token, DeleteToken(byFeature, []int{1, 2, 3}), "order of banners does not matter")
+	assert.NotEqual(t, token, DeleteToken(byFeature, []int{1, 2}))
+	assert.NotEqual(t, token, DeleteToken(&BulkDeleteFilter{TagIds: []int{1}}, []int{1, 2, 3}))
+	assert.NotEqual(t, DeleteToken(byFeature, []int{12}), DeleteToken(byFeature, []int{1, 2}))
+
+	byTags := &BulkDeleteFilter{FeatureId: 1, TagIds: []int{5, 4, 5}}
+	assert.Equal(t, DeleteToken(byTags, []int{1}), DeleteToken(&BulkDeleteFilter{FeatureId: 1, TagIds: []int{4, 5}}, []int{1}),
+		"order and repeats of tags do not matter")
+	assert.Equal(t, []int{5, 4, 5}, byTags.TagIds, "filter is not changed")
+	assert.NotEqual(t, DeleteToken(byTags, []int{1}), DeleteToken(&BulkDeleteFilter{FeatureId: 1, TagIds: []int{4, 5}, InactiveOnly: true}, []int{1}))
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NotEqual(t, DeleteToken(byFeature, []int{1}), DeleteToken(&BulkDeleteFilter{FeatureId: 1, UpdatedBefore: before}, []int{1}))
+}
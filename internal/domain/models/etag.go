@@ -0,0 +1,57 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const AnyETag = "*"
+
+// ETag is the entity tag of the banner state. Activity is kept apart from the banner row,
+// so it is a part of the tag along with the revision
+func ETag(revision int, isActive bool) string {
+	state := "active"
+	if !isActive {
+		state = "inactive"
+	}
+	return fmt.Sprintf(`"%d-%s"`, revision, state)
+}
+
+// ContentETag is the entity tag of the content shown to users. It depends on the content only,
+// so the same tag is produced for cached and stored banners
+func ContentETag(content map[string]any) string {
+	// map keys are sorted by encoding/json, equal contents are encoded equally
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+}
+
+// MatchETag reports whether If-Match or If-None-Match header value lists the tag.
+// Weak comparison ignores W/ prefix and is used for If-None-Match, If-Match never matches weak tags
+func MatchETag(header, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == AnyETag {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = candidate[2:]
+		}
+		if candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"3-active"`, ETag(3, true))
+	assert.NotEqual(t, ETag(3, true), ETag(3, false))
+	assert.NotEqual(t, ETag(3, true), ETag(4, true))
+
+	content := map[string]any{"title": "Sale", "tags": []any{1.0, 2.0}}
+	same := map[string]any{"tags": []any{1.0, 2.0}, "title": "Sale"}
+	assert.Equal(t, ContentETag(content), ContentETag(same))
+	assert.NotEqual(t, ContentETag(content), ContentETag(map[string]any{"title": "Sale"}))
+}
+
+func TestMatchETag(t *testing.T) {
+	etag := ETag(3, true)
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		want   bool
+	}{
+		{name: "equal", header: etag, want: true},
+		{name: "different", header: ETag(2, true), want: false},
+		{name: "any", header: AnyETag, want: true},
+		{name: "list", header: `"1-active", ` + etag, want: true},
+		{name: "empty", header: "", want: false},
+		{name: "weak in strong comparison", header: "W/" + etag, want: false},
+		{name: "weak in weak comparison", header: "W/" + etag, weak: true, want: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, MatchETag(test.header, etag, test.weak))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// BannerEvent is a change of what users get from the banner: its content, keys, activity, publication,
+// targeting or translations. Ids grow with every event
+type BannerEvent struct {
+	Id        int64
+	Tenant    string
+	BannerId  int
+	FeatureId int
+	TagIds    []int
+	CreatedAt time.Time
+}
+
+// Affects reports whether users of the tenant having any of the tags may get another banner of the feature after the event
+func (ev *BannerEvent) Affects(tenant string, featureId int, tagIds []int) bool {
+	return ev.Tenant == tenant && ev.FeatureId == featureId && slices.ContainsFunc(ev.TagIds, func(id int) bool {
+		return slices.Contains(tagIds, id)
+	})
+}
+
+// BannerWatchOptions watches the banner the user gets, LastEventId resumes the watch after the event
+// the client got last, zero starts it with the current banner
+type BannerWatchOptions struct {
+	BannerUserOptions
+	LastEventId int64
+}
+
+// BannerChange is the banner the user gets after the event, nil Banner means there is none
+type BannerChange struct {
+	EventId int64
+	Banner  *UserBanner
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannerEventAffects(t *testing.T) {
+	event := &BannerEvent{Tenant: "acme", FeatureId: 1, TagIds: []int{2, 3}}
+	assert.True(t, event.Affects("acme", 1, []int{5, 3}))
+	assert.False(t, event.Affects("acme", 1, []int{4}), "users of other tags get the same banner")
+	assert.False(t, event.Affects("acme", 2, []int{2}))
+	assert.False(t, event.Affects("", 1, []int{2}), "banners of other tenants are never given")
+}
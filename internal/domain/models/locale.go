@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is the locale of the content of the banner itself, it ends every fallback chain
+const DefaultLocale = ""
+
+var ErrInvalidLocale = errors.New("locale must be a language tag such as ru or ru-RU")
+
+// Translation is the content of the banner in the locale
+type Translation struct {
+	BannerId  int
+	Locale    string
+	Content   map[string]any
+	UpdatedAt time.Time
+}
+
+// MissingTranslations lists locales the banner has no translation to in the requested order
+type MissingTranslations struct {
+	BannerId  int
+	FeatureId int
+	Locales   []string
+}
+
+// NormalizeLocale converts the language tag to its usual form: ru_ru and RU-ru become ru-RU, zh-hant-tw becomes zh-Hant-TW
+func NormalizeLocale(locale string) (string, error) {
+	subtags := strings.FieldsFunc(locale, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(subtags) == 0 || len(subtags) != strings.Count(locale, "-")+strings.Count(locale, "_")+1 {
+		return "", ErrInvalidLocale
+	}
+	for i, subtag := range subtags {
+		if len(subtag) > 8 || strings.IndexFunc(subtag, func(r rune) bool {
+			return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+		}) >= 0 {
+			return "", ErrInvalidLocale
+		}
+		subtag = strings.ToLower(subtag)
+		switch {
+		case i == 0:
+			if len(subtag) < 2 || len(subtag) > 3 || strings.ContainsAny(subtag, "0123456789") {
+				return "", ErrInvalidLocale
+			}
+		case len(subtag) == 2:
+			subtag = strings.ToUpper(subtag)
+		case len(subtag) == 4:
+			subtag = strings.ToUpper(subtag[:1]) + subtag[1:]
+		}
+		subtags[i] = subtag
+	}
+	return strings.Join(subtags, "-"), nil
+}
+
+// LocaleChain returns locales the content is looked up in: the normalized locale and its prefixes,
+// so ru-RU falls back to ru and then to DefaultLocale
+func LocaleChain(locale string) []string {
+	chain := []string{}
+	for locale != DefaultLocale {
+		chain = append(chain, locale)
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return append(chain, DefaultLocale)
+}
+
+// PreferredLocale returns the valid locale of Accept-Language header with the highest weight, the first one
+// among equal weights. Wildcard and locales with zero weight are skipped, DefaultLocale is returned if there are none
+func PreferredLocale(header string) string {
+	type weighted struct {
+		locale string
+		weight float64
+	}
+	var locales []weighted
+	for _, item := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		locale, err := NormalizeLocale(strings.TrimSpace(tag))
+		if err != nil {
+			continue
+		}
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if weight > 0 {
+			locales = append(locales, weighted{locale: locale, weight: weight})
+		}
+	}
+	if len(locales) == 0 {
+		return DefaultLocale
+	}
+	slices.SortStableFunc(locales, func(a, b weighted) int {
+		switch {
+		case a.weight > b.weight:
+			return -1
+		case a.weight < b.weight:
+			return 1
+		}
+		return 0
+	})
+	return locales[0].locale
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	for locale, want := range map[string]string{"ru": "ru", "RU_ru": "ru-RU", "zh-hant-tw": "zh-Hant-TW", "es-419": "es-419"} {
+		got, err := NormalizeLocale(locale)
+		assert.NoError(t, err, locale)
+		assert.Equal(t, want, got)
+	}
+	for _, locale := range []string{"", "r", "ru-", "ru--RU", "12", "ru RU", "ru-abcdefghi"} {
+		_, err := NormalizeLocale(locale)
+		assert.ErrorIs(t, err, ErrInvalidLocale, locale)
+	}
+}
+
+func TestLocaleChain(t *testing.T) {
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh", DefaultLocale}, LocaleChain("zh-Hant-TW"))
+	assert.Equal(t, []string{"ru", DefaultLocale}, LocaleChain("ru"))
+	assert.Equal(t, []string{DefaultLocale}, LocaleChain(DefaultLocale))
+}
+
+func TestPreferredLocale(t *testing.T) {
+	assert.Equal(t, "de-DE", PreferredLocale("en;q=0.8, de_de, ru;q=0.9"))
+	assert.Equal(t, "en", PreferredLocale("en, ru"), "the first one wins among equal weights")
+	assert.Equal(t, "ru", PreferredLocale("*, en;q=0, ru;q=0.1"))
+	assert.Equal(t, DefaultLocale, PreferredLocale(""))
+	assert.Equal(t, DefaultLocale, PreferredLocale("*, en;q=x"))
+}
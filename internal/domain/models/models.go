@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/patch"
+	"fmt"
+	"time"
+)
 
 const (
 	ZeroBit     = 0
@@ -16,15 +21,49 @@ type BannerIdentOptions struct {
 	TagId     int
 }
 
+// BannerUserOptions looks up the banner of the feature by every tag of Attributes, TagId is the first of them
 type BannerUserOptions struct {
 	BannerIdentOptions
 	UseLastRevision bool
+	Attributes      UserAttributes
+}
+
+// BannersUserOptions looks up banners of every feature of FeatureIds for the same user, see BannerUserOptions
+type BannersUserOptions struct {
+	FeatureIds      []int
+	UseLastRevision bool
+	Attributes      UserAttributes
 }
 
+// BannerListOptions filters banners, zero times and nil IsActive are not used. After continues the listing
+// from the cursor, so it must have the same Sort and Desc. WithTotal counts every banner matching the filters.
+// FeatureIds lists banners of any of the features
 type BannerListOptions struct {
 	BannerIdentOptions
-	Limit  int
-	Offset int
+	FeatureIds    []int
+	FeatureName   string
+	TagName       string
+	TagIds        []int
+	IsActive      *bool
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	Sort          ListSort
+	Desc          bool
+	After         *ListCursor
+	WithTotal     bool
+	// Deleted lists the trash instead of live banners
+	Deleted bool
+	Limit   int
+	Offset  int
+}
+
+// BannerPage is a page of the listing. Next is nil on the last page, Total is ZeroValue unless requested
+type BannerPage struct {
+	Banners []BannerExt
+	Next    *ListCursor
+	Total   int
 }
 
 type UserBanner struct {
@@ -37,9 +76,39 @@ type BaseBanner struct {
 	TagIds    []int
 }
 
+// HistoryBanner is a previous state of the banner. Actor made it and CreatedAt is when it was made,
+// IsActive is the state of the banner at the moment it was replaced
 type HistoryBanner struct {
 	BaseBanner
-	Version int
+	Version   int
+	Actor     string
+	IsActive  bool
+	CreatedAt time.Time
+}
+
+// BannerDiff describes changes between two versions of the banner, ZeroValue version is the current state
+type BannerDiff struct {
+	From    int
+	To      int
+	Patch   []patch.Operation
+	Summary []string
+}
+
+// ContentPatch is a partial edit of banner content, either RFC 7396 merge patch or RFC 6902 operations
+type ContentPatch struct {
+	Merge      any
+	Operations []patch.Operation
+}
+
+type HistoryListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// HistoryRetention limits stored versions of each banner, zero values mean no limit
+type HistoryRetention struct {
+	MaxVersions int
+	MaxAge      time.Duration
 }
 
 type Banner struct {
@@ -52,9 +121,132 @@ type UpdateBanner struct {
 	Flags int
 }
 
+// BannerExt is the stored banner, Revision grows with every change of its content, tags or feature
 type BannerExt struct {
 	BannerId int
 	Banner
+	Revision  int
 	UpdatedAt time.Time
 	CreatedAt time.Time
+	// DeletedAt is set for banners of the trash
+	DeletedAt *time.Time
+	State     BannerState
+	// Live banners are given to users, drafts which were never published and archived banners are not
+	Live bool
+}
+
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+// BatchOperation is a single change of the batch. Banner is used by create and update,
+// create requires every field of it to be set in Flags
+type BatchOperation struct {
+	Op      BatchOp
+	Id      int
+	IfMatch string
+	Banner  UpdateBanner
+}
+
+// Batch is applied in one transaction when Atomic is set, otherwise every operation is applied on its own
+type Batch struct {
+	Atomic     bool
+	Operations []BatchOperation
+}
+
+// BatchResult is the outcome of the operation with the same index, Id is the created, updated or deleted banner
+type BatchResult struct {
+	Op  BatchOp
+	Id  int
+	Err error
+}
+
+type ImportMode string
+
+const (
+	ImportUpsert         ImportMode = "upsert"
+	ImportSkipExisting   ImportMode = "skip_existing"
+	ImportFailOnConflict ImportMode = "fail_on_conflict"
+)
+
+// ImportRecord is a banner read from the import file, Line is its position in the file.
+// Existing banners are matched by feature and tags, so ids of another installation may be imported
+type ImportRecord struct {
+	Line int
+	Banner
+}
+
+// ImportOptions sets how records matching existing banners are handled. Dry run imports every record
+// and rolls back, so the report includes errors found by the database. MaxCreated limits created banners,
+// ZeroValue does not limit them
+type ImportOptions struct {
+	Mode       ImportMode
+	DryRun     bool
+	MaxCreated int
+}
+
+// RecordError is a rejected record of the import
+type RecordError struct {
+	Line       int
+	Violations []e.FieldViolation
+}
+
+func (r *RecordError) Error() string {
+	return fmt.Sprintf("line %d: %s", r.Line, e.NewValidationError(r.Violations...).Error())
+}
+
+// ImportReport is the result of the import. Nothing is imported when Failed is not zero,
+// Errors lists at most first few rejected records
+type ImportReport struct {
+	Mode    ImportMode
+	DryRun  bool
+	Applied bool
+	Total   int
+	Created int
+	Updated int
+	Skipped int
+	Failed  int
+	Errors  []RecordError
+}
+
+type FeatureSchema struct {
+	FeatureId int
+	Schema    map[string]any
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type InvalidBanner struct {
+	BannerId   int
+	Violations []e.FieldViolation
+}
+
+// SchemaReport is the result of validation of existing banners against feature schema
+type SchemaReport struct {
+	FeatureId int
+	DryRun    bool
+	Checked   int
+	Invalid   []InvalidBanner
+}
+
+// Registry is a kind of registered banner attribute, the value is the name of its table
+type Registry string
+
+const (
+	Features Registry = "features"
+	Tags     Registry = "tags"
+)
+
+// RegistryEntry is a registered feature or tag. ZeroValue Id means it is assigned by the database
+type RegistryEntry struct {
+	Id          int
+	Name        string
+	Description string
+	Owner       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// BannerState is the state of the banner in the approval workflow. Draft, in review and approved are states
+// of the pending revision, published and archived banners have none
+type BannerState string
+
+const (
+	StateDraft     BannerState = "draft"
+	StateInReview  BannerState = "in_review"
+	StateApproved  BannerState = "approved"
+	StatePublished BannerState = "published"
+	StateArchived  BannerState = "archived"
+)
+
+type ReviewAction string
+
+const (
+	ActionSubmit  ReviewAction = "submit"
+	ActionApprove ReviewAction = "approve"
+	ActionReject  ReviewAction = "reject"
+	ActionPublish ReviewAction = "publish"
+	ActionArchive ReviewAction = "archive"
+)
+
+// PendingRevision is the change of the banner waiting to replace its current state. Author made the last edit,
+// Reviewer and Comment are left by the last approval or rejection. A banner without pending changes
+// is described by a revision of its current state, which is published or archived
+type PendingRevision struct {
+	BannerId int
+	Banner
+	State     BannerState
+	Author    string
+	Reviewer  string
+	Comment   string
+	UpdatedAt time.Time
+}
+
+// ReviewDecision moves the banner to the next state, Comment explains the rejection
+type ReviewDecision struct {
+	Action  ReviewAction
+	Comment string
+}
+
+// ReviewListOptions lists pending revisions in the state, the least recently changed first
+type ReviewListOptions struct {
+	State  BannerState
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+type PredicateOp string
+
+const (
+	PredicateEq       PredicateOp = "eq"
+	PredicateContains PredicateOp = "contains"
+	PredicateExists   PredicateOp = "exists"
+)
+
+// contentPrefix may start the path of the predicate, paths are inside the content anyway
+const contentPrefix = "content."
+
+var ErrInvalidPredicate = errors.New("predicate must be \"<path> eq|contains <value>\" or \"<path> exists\"")
+
+// ContentPredicate matches the value at Path of the content. Path is keys of nested objects or indexes of arrays,
+// values are compared as text, contains ignores the case
+type ContentPredicate struct {
+	Path  []string
+	Op    PredicateOp
+	Value string
+}
+
+// BannerSearchOptions finds banners whose content has words of Query and matches every predicate.
+// Filters, limit and cursor are the same as in the listing
+type BannerSearchOptions struct {
+	BannerListOptions
+	Query      string
+	Predicates []ContentPredicate
+}
+
+// SearchHit is the found banner, Rank is zero when nothing is searched by Query
+type SearchHit struct {
+	BannerExt
+	Rank float32
+}
+
+// SearchPage is a page of the search ordered by rank. Next is nil on the last page, Total is ZeroValue unless requested
+type SearchPage struct {
+	Hits  []SearchHit
+	Next  *ListCursor
+	Total int
+}
+
+// ParsePredicate parses "<path> <op> <value>" such as "content.title contains Black Friday", path keys are
+// separated by dots and the value is the rest of the string
+func ParsePredicate(str string) (ContentPredicate, error) {
+	path, rest, _ := strings.Cut(strings.TrimSpace(str), " ")
+	op, value, _ := strings.Cut(strings.TrimLeft(rest, " "), " ")
+	predicate := ContentPredicate{
+		Path:  strings.Split(strings.TrimPrefix(path, contentPrefix), "."),
+		Op:    PredicateOp(op),
+		Value: strings.TrimLeft(value, " "),
+	}
+	for _, key := range predicate.Path {
+		if key == "" {
+			return ContentPredicate{}, ErrInvalidPredicate
+		}
+	}
+	switch predicate.Op {
+	case PredicateEq:
+	case PredicateContains:
+		if predicate.Value == "" {
+			return ContentPredicate{}, ErrInvalidPredicate
+		}
+	case PredicateExists:
+		if predicate.Value != "" {
+			return ContentPredicate{}, ErrInvalidPredicate
+		}
+	default:
+		return ContentPredicate{}, ErrInvalidPredicate
+	}
+	return predicate, nil
+}
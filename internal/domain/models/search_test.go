@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePredicate(t *testing.T) {
+	for _, test := range []struct {
+		str  string
+		want ContentPredicate
+	}{
+		{str: "content.title contains Black Friday", want: ContentPredicate{Path: []string{"title"}, Op: PredicateContains, Value: "Black Friday"}},
+		{str: " cta.url  eq  https://example.com/a b", want: ContentPredicate{Path: []string{"cta", "url"}, Op: PredicateEq, Value: "https://example.com/a b"}},
+		{str: "items.0 exists", want: ContentPredicate{Path: []string{"items", "0"}, Op: PredicateExists}},
+		{str: "title eq", want: ContentPredicate{Path: []string{"title"}, Op: PredicateEq}},
+	} {
+		predicate, err := ParsePredicate(test.str)
+		require.NoError(t, err, test.str)
+		assert.Equal(t, test.want, predicate, test.str)
+	}
+
+	for _, str := range []string{"", "title", "title like Sale", "title contains", "title exists Sale", "cta..url eq x", "content. eq x"} {
+		_, err := ParsePredicate(str)
+		assert.ErrorIs(t, err, ErrInvalidPredicate, str)
+	}
+}
+
+func TestCursorAfterHit(t *testing.T) {
+	hit := &SearchHit{BannerExt: BannerExt{BannerId: 3}, Rank: 0.0607927}
+	for _, sort := range []ListSort{SortByRank, SortById} {
+		cursor := CursorAfterHit(hit, sort)
+		decoded, err := DecodeCursor(EncodeCursor(cursor))
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	}
+}
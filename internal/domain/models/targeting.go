@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidVersion = errors.New("version must be dot separated non negative numbers such as 1.2.10")
+
+// TargetingRule limits the banner to users with matching attributes, every non-empty condition must match.
+// Lists match when they contain the attribute of the user, Locales also match more specific locales of users,
+// so ru matches ru-RU. TagIds match when the user has any of them.
+// MinVersion and MaxVersion are inclusive bounds of the app version
+type TargetingRule struct {
+	Locales    []string `json:"locales,omitempty"`
+	Platforms  []string `json:"platforms,omitempty"`
+	Countries  []string `json:"countries,omitempty"`
+	MinVersion string   `json:"min_version,omitempty"`
+	MaxVersion string   `json:"max_version,omitempty"`
+	TagIds     []int    `json:"tag_ids,omitempty"`
+}
+
+// Targeting of the banner. When several banners match the user, the one with the highest Priority is given
+// to the user, ties are broken by the lower id
+type Targeting struct {
+	BannerId int
+	Priority int
+	Rule     TargetingRule
+}
+
+// UserAttributes describe the user requesting a banner, empty ones match only rules without their conditions
+type UserAttributes struct {
+	Locale     string
+	Platform   string
+	Country    string
+	AppVersion string
+	TagIds     []int
+}
+
+// Candidate is a banner the user may get, Targeting is nil for banners without a rule.
+// Content is translated to Locale, DefaultLocale is the content of the banner itself
+type Candidate struct {
+	BannerId int
+	UserBanner
+	Locale    string
+	Targeting *Targeting
+}
+
+// Priority of the candidate, banners without targeting have zero one
+func (c *Candidate) Priority() int {
+	if c.Targeting == nil {
+		return 0
+	}
+	return c.Targeting.Priority
+}
+
+// Mismatches explains why the rule doesn't match the user, empty result means it matches
+func (c *Candidate) Mismatches(attrs *UserAttributes) []string {
+	if c.Targeting == nil {
+		return nil
+	}
+	return c.Targeting.Rule.Mismatches(attrs)
+}
+
+// SortCandidates orders candidates the way they are chosen, by descending priority and then by id
+func SortCandidates(candidates []Candidate) {
+	slices.SortFunc(candidates, func(a, b Candidate) int {
+		if a.Priority() != b.Priority() {
+			return b.Priority() - a.Priority()
+		}
+		return a.BannerId - b.BannerId
+	})
+}
+
+// EvaluationOptions describe the user the banner of the feature is chosen for
+type EvaluationOptions struct {
+	FeatureId  int
+	Attributes UserAttributes
+}
+
+// CandidateResult explains the candidate, Reasons are mismatched conditions of its rule or why it is not served
+type CandidateResult struct {
+	BannerId int
+	Priority int
+	IsActive bool
+	Live     bool
+	Matched  bool
+	Served   bool
+	Reasons  []string
+}
+
+// Evaluation is the banner the user would get, BannerId is ZeroValue when none matches. Content is translated
+// to Locale, candidates are in the order they were checked
+type Evaluation struct {
+	BannerId   int
+	Content    map[string]any
+	Locale     string
+	Candidates []CandidateResult
+}
+
+// Mismatches explains why the rule doesn't match the user, empty result means it matches
+func (r *TargetingRule) Mismatches(attrs *UserAttributes) []string {
+	var reasons []string
+	lists := []struct {
+		name   string
+		values []string
+		value  string
+		accept []string
+	}{
+		{"locale", r.Locales, attrs.Locale, LocaleChain(attrs.Locale)},
+		{"platform", r.Platforms, attrs.Platform, []string{attrs.Platform}},
+		{"country", r.Countries, attrs.Country, []string{attrs.Country}},
+	}
+	for _, list := range lists {
+		if len(list.values) > 0 && !slices.ContainsFunc(list.values, func(value string) bool {
+			return slices.ContainsFunc(list.accept, func(accepted string) bool {
+				return strings.EqualFold(value, accepted)
+			})
+		}) {
+			reasons = append(reasons, fmt.Sprintf("%s %q is not one of %v", list.name, list.value, list.values))
+		}
+	}
+	if r.MinVersion != "" || r.MaxVersion != "" {
+		switch {
+		case attrs.AppVersion == "":
+			reasons = append(reasons, "app version is not given")
+		case r.MinVersion != "" && CompareVersions(attrs.AppVersion, r.MinVersion) < 0:
+			reasons = append(reasons, fmt.Sprintf("app version %s is lower than %s", attrs.AppVersion, r.MinVersion))
+		case r.MaxVersion != "" && CompareVersions(attrs.AppVersion, r.MaxVersion) > 0:
+			reasons = append(reasons, fmt.Sprintf("app version %s is higher than %s", attrs.AppVersion, r.MaxVersion))
+		}
+	}
+	if len(r.TagIds) > 0 && !slices.ContainsFunc(attrs.TagIds, func(id int) bool {
+		return slices.Contains(r.TagIds, id)
+	}) {
+		reasons = append(reasons, fmt.Sprintf("tags %v have none of %v", attrs.TagIds, r.TagIds))
+	}
+	return reasons
+}
+
+// ValidVersion reports whether the app version can be compared
+func ValidVersion(version string) bool {
+	_, err := parseVersion(version)
+	return err == nil
+}
+
+// CompareVersions compares app versions number by number, missing numbers are zeros, so 1.2 equals 1.2.0.
+// Invalid versions must be rejected by ValidVersion before
+func CompareVersions(a, b string) int {
+	left, _ := parseVersion(a)
+	right, _ := parseVersion(b)
+	for len(left) < len(right) {
+		left = append(left, 0)
+	}
+	for len(right) < len(left) {
+		right = append(right, 0)
+	}
+	return slices.Compare(left, right)
+}
+
+func parseVersion(version string) ([]int, error) {
+	parts := strings.Split(version, ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 || strings.HasPrefix(part, "+") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.10", b: "1.2.9", want: 1},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1", b: "1.0.1", want: -1},
+		{a: "2.0", b: "10.0", want: -1},
+	} {
+		assert.Equal(t, test.want, CompareVersions(test.a, test.b), test.a+" "+test.b)
+	}
+	for _, version := range []string{"", "1.", "1..2", "v1.2", "1.-2", "1.+2"} {
+		assert.False(t, ValidVersion(version), version)
+	}
+}
+
+func TestTargetingRuleMismatches(t *testing.T) {
+	rule := &TargetingRule{
+		Locales:    []string{"ru", "en"},
+		Platforms:  []string{"ios"},
+		MinVersion: "1.2",
+		MaxVersion: "2.0",
+		TagIds:     []int{3, 4},
+	}
+	for _, test := range []struct {
+		name  string
+		attrs UserAttributes
+		want  []string
+	}{
+		{
+			name:  "matches",
+			attrs: UserAttributes{Locale: "RU", Platform: "ios", AppVersion: "2.0.0", TagIds: []int{1, 4}},
+		},
+		{
+			name:  "every condition fails",
+			attrs: UserAttributes{Locale: "de", Platform: "android", AppVersion: "1.1.9", TagIds: []int{1}},
+			want: []string{
+				`locale "de" is not one of [ru en]`,
+				`platform "android" is not one of [ios]`,
+				"app version 1.1.9 is lower than 1.2",
+				"tags [1] have none of [3 4]",
+			},
+		},
+		{
+			name:  "missing attributes",
+			attrs: UserAttributes{Locale: "en", Platform: "ios", TagIds: []int{3}},
+			want:  []string{"app version is not given"},
+		},
+		{
+			name:  "newer version",
+			attrs: UserAttributes{Locale: "en", Platform: "ios", AppVersion: "2.1", TagIds: []int{3}},
+			want:  []string{"app version 2.1 is higher than 2.0"},
+		},
+	} {
+		assert.Equal(t, test.want, rule.Mismatches(&test.attrs), test.name)
+	}
+	assert.Empty(t, (&TargetingRule{}).Mismatches(&UserAttributes{}), "empty rule matches every user")
+}
+
+func TestSortCandidates(t *testing.T) {
+	candidates := []Candidate{
+		{BannerId: 5},
+		{BannerId: 4, Targeting: &Targeting{Priority: 1}},
+		{BannerId: 2, Targeting: &Targeting{Priority: -1}},
+		{BannerId: 3},
+	}
+	SortCandidates(candidates)
+	ids := make([]int, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.BannerId)
+	}
+	assert.Equal(t, []int{4, 3, 5, 2}, ids)
+}
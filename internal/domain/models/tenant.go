@@ -0,0 +1,8 @@
+package models
+
+// TenantQuota is the number of banners the tenant keeps and may keep. MaxBanners equal to zero does not limit them
+type TenantQuota struct {
+	Tenant     string
+	Banners    int
+	MaxBanners int
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// WebhookEvent is a change of the banner webhooks are notified about
+type WebhookEvent string
+
+const (
+	WebhookCreated         WebhookEvent = "created"
+	WebhookUpdated         WebhookEvent = "updated"
+	WebhookDeleted         WebhookEvent = "deleted"
+	WebhookActivated       WebhookEvent = "activated"
+	WebhookVersionRestored WebhookEvent = "version_restored"
+)
+
+var WebhookEvents = []WebhookEvent{WebhookCreated, WebhookUpdated, WebhookDeleted, WebhookActivated, WebhookVersionRestored}
+
+// DeliveryStatus is the state of the delivery of an event to the webhook. Pending deliveries are sent until
+// they succeed or run out of attempts, then they are dead letters
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// Webhook is the endpoint of the tenant receiving Events. Deliveries are signed with Secret, which is never returned
+type Webhook struct {
+	Id        int
+	Url       string
+	Events    []WebhookEvent
+	Secret    string
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery is the event sent to the webhook. Payload is the JSON document posted, ResponseStatus
+// and LastError describe the last attempt
+type WebhookDelivery struct {
+	Id             int64
+	WebhookId      int
+	Event          WebhookEvent
+	BannerId       int
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// DueDelivery is the delivery to send now with the endpoint and the secret of its webhook
+type DueDelivery struct {
+	WebhookDelivery
+	Url    string
+	Secret string
+}
+
+// DeliveryAttempt is the result of sending the delivery. The failed delivery is retried after RetryAfter,
+// Dead one is not retried anymore
+type DeliveryAttempt struct {
+	DeliveryId     int64
+	Delivered      bool
+	Dead           bool
+	ResponseStatus int
+	Error          string
+	RetryAfter     time.Duration
+}
+
+// DeliveryListOptions lists deliveries of the webhook in the status, ZeroValue WebhookId lists deliveries
+// of every webhook and empty Status lists every status. The latest deliveries come first
+type DeliveryListOptions struct {
+	WebhookId int
+	Status    DeliveryStatus
+	Limit     int
+	Offset    int
+}
+
+// SignWebhook signs the payload sent at the unix timestamp with HMAC-SHA256, the timestamp is signed too,
+// so a captured delivery can't be replayed later
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
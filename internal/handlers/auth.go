@@ -8,16 +8,13 @@ import (
 	"net/http"
 )
 
-func (b *HandlerBuilder) handleTokenGeneration(c *gin.Context) {
+func (b *HandlerBuilder) GetToken(c *gin.Context) {
 	var token string
 	var err error
 	param := api.AdminParam{}
 	err = c.ShouldBindUri(&param)
-	if err == nil && param.Admin == "/admin" {
-		token, err = b.generator.GenerateToken(true)
-	} else {
-		token, err = b.generator.GenerateToken(false)
-	}
+	// the name, roles and tenant of the token are never chosen by its requester
+	token, err = b.generator.GenerateToken(err == nil && param.Admin == "/admin")
 	if err != nil {
 		collectErrors(c, fmt.Errorf("%w: error generating token: %w", e.ErrorInternal, err))
 		return
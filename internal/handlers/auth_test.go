@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	"BannerFlow/pkg/api"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	handler := New(nil, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/get_token/admin?subject=bob&roles=reviewer&tenant=ads", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	token := &api.TokenResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), token))
+
+	id, err := sso.Authenticate(token.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", id.Actor)
+	assert.True(t, id.IsAdmin)
+	assert.Empty(t, id.Roles, "roles are assigned by the server only")
+	assert.Empty(t, id.Tenant)
+}
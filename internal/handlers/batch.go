@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// successStatus is the status of the same single request
+var successStatus = map[models.BatchOp]int{
+	models.BatchCreate: http.StatusCreated,
+	models.BatchUpdate: http.StatusOK,
+	models.BatchDelete: http.StatusNoContent,
+}
+
+func (b *HandlerBuilder) BatchBanners(c *gin.Context) {
+	results, err := b.batchBanners(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	items := make([]api.BatchItemResponse, 0, len(results))
+	for i, result := range results {
+		status, msg := successStatus[result.Op], ""
+		if result.Err != nil {
+			status, msg = errorStatus(result.Err)
+		}
+		items = append(items, converters.BatchResultToResponse(i, result, status, msg))
+	}
+	c.JSON(http.StatusMultiStatus, api.BatchResponse{Results: &items})
+}
+
+func (b *HandlerBuilder) batchBanners(c *gin.Context) ([]models.BatchResult, error) {
+	req, err := readRequest[api.BatchRequest](c)
+	if err != nil {
+		return nil, err
+	}
+	return b.srv.BatchBanners(c.Request.Context(), converters.BatchRequestToBatch(req))
+}
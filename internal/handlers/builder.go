@@ -1,52 +1,110 @@
 package handlers
 
 import (
+	"BannerFlow/internal/domain/identity"
 	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"BannerFlow/internal/validation"
 	"context"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"net/http"
+	"time"
 )
 
 const (
-	tokenName = "token"
+	tokenName  = "token"
+	apiKeyName = "X-API-Key"
 )
 
 //go:generate mockgen -source=gin_api.go -package=mocks -destination=./mocks/mock_gin_api.go
 type Service interface {
 	CreateBanner(ctx context.Context, banner *models.Banner) (int, error)
-	DeleteBanner(ctx context.Context, id int) error
-	ListBanners(ctx context.Context, options *models.BannerListOptions) ([]models.BannerExt, error)
+	DeleteBanner(ctx context.Context, id int, ifMatch string) error
+	RestoreBanner(ctx context.Context, id int) error
+	ListBanners(ctx context.Context, options *models.BannerListOptions) (*models.BannerPage, error)
+	SearchBanners(ctx context.Context, options *models.BannerSearchOptions) (*models.SearchPage, error)
+	GetBanner(ctx context.Context, id int) (*models.BannerExt, error)
 	UserGetBanners(ctx context.Context, options *models.BannerUserOptions) (*models.UserBanner, error)
-	UpdateBanner(ctx context.Context, id int, banner *models.UpdateBanner) error
-	ListBannerHistory(ctx context.Context, id int) ([]models.HistoryBanner, error)
-	SelectBannerVersion(ctx context.Context, id, version int) error
-	DeleteBannersByTagOrFeature(ctx context.Context, options *models.BannerIdentOptions) error
+	UserGetFeatureBanners(ctx context.Context, options *models.BannersUserOptions) (map[int]*models.UserBanner, error)
+	UpdateBanner(ctx context.Context, id int, banner *models.UpdateBanner, ifMatch string) error
+	PatchBannerContent(ctx context.Context, id int, contentPatch *models.ContentPatch, ifMatch string) error
+	ListBannerHistory(ctx context.Context, id int, options *models.HistoryListOptions) ([]models.HistoryBanner, error)
+	SelectBannerVersion(ctx context.Context, id, version int, ifMatch string) error
+	DiffBannerVersions(ctx context.Context, id, from, to int) (*models.BannerDiff, error)
+	DeleteBannersByTagOrFeature(ctx context.Context, options *models.BulkDeleteOptions) (*models.DeletePreview, error)
+	GetDeleteJob(ctx context.Context, id int) (*models.DeleteJob, error)
+	BatchBanners(ctx context.Context, batch *models.Batch) ([]models.BatchResult, error)
+	ExportBanners(ctx context.Context, options *models.BannerListOptions, fn func(banner *models.BannerExt) error) error
+	ImportBanners(ctx context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions) (*models.ImportReport, error)
+	ListFeatureSchemas(ctx context.Context) ([]models.FeatureSchema, error)
+	GetFeatureSchema(ctx context.Context, featureId int) (*models.FeatureSchema, error)
+	PutFeatureSchema(ctx context.Context, schema *models.FeatureSchema, dryRun bool) (*models.SchemaReport, error)
+	DeleteFeatureSchema(ctx context.Context, featureId int) error
+	ListRegistry(ctx context.Context, registry models.Registry) ([]models.RegistryEntry, error)
+	GetRegistryEntry(ctx context.Context, registry models.Registry, id int) (*models.RegistryEntry, error)
+	CreateRegistryEntry(ctx context.Context, registry models.Registry, entry *models.RegistryEntry) (int, error)
+	UpdateRegistryEntry(ctx context.Context, registry models.Registry, id int, entry *models.RegistryEntry) error
+	DeleteRegistryEntry(ctx context.Context, registry models.Registry, id int) error
+	ReviewBanner(ctx context.Context, id int, decision *models.ReviewDecision) error
+	GetBannerRevision(ctx context.Context, id int) (*models.PendingRevision, error)
+	ListReviews(ctx context.Context, options *models.ReviewListOptions) ([]models.PendingRevision, error)
+	GetTenantQuota(ctx context.Context) (*models.TenantQuota, error)
+	GetTargeting(ctx context.Context, id int) (*models.Targeting, error)
+	PutTargeting(ctx context.Context, targeting *models.Targeting) error
+	DeleteTargeting(ctx context.Context, id int) error
+	EvaluateBanner(ctx context.Context, options *models.EvaluationOptions) (*models.Evaluation, error)
+	ListTranslations(ctx context.Context, id int) ([]models.Translation, error)
+	PutTranslation(ctx context.Context, translation *models.Translation) error
+	DeleteTranslation(ctx context.Context, id int, locale string) error
+	MissingTranslations(ctx context.Context, featureId int, locales []string) ([]models.MissingTranslations, error)
+	WatchUserBanner(ctx context.Context, options *models.BannerWatchOptions) (<-chan models.BannerChange, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (*models.Webhook, error)
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) (int, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int) error
+	ListDeliveries(ctx context.Context, options *models.DeliveryListOptions) ([]models.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, webhookId int, id int64) error
 }
 
 type Authenticator interface {
-	Authenticate(token string) error // TODO get user?
-}
-
-type Authorizer interface {
-	IsAdmin(token string) bool
+	Authenticate(token string) (*identity.Identity, error)
+	AuthenticateKey(key string) (*identity.Identity, error)
 }
 
 type TokenGenerator interface {
 	GenerateToken(isAdmin bool) (string, error)
 }
 
+var _ ServerInterface = (*HandlerBuilder)(nil)
+
 type HandlerBuilder struct {
-	srv           Service
-	authenticator Authenticator
-	logger        *slog.Logger
-	authorizer    Authorizer
-	generator     TokenGenerator
+	srv               Service
+	authenticator     Authenticator
+	logger            *slog.Logger
+	generator         TokenGenerator
+	spec              *validation.Spec
+	validateResponses bool
+	heartbeat         time.Duration
+	// streams is done once streams are closed
+	streams      context.Context
+	closeStreams context.CancelFunc
 }
 
 // New creates new handlers builder
-func New(srv Service, auth Authenticator, authorizer Authorizer, generator TokenGenerator, logger *slog.Logger) *HandlerBuilder {
-	return &HandlerBuilder{srv: srv, logger: logger, authenticator: auth, authorizer: authorizer, generator: generator}
+func New(srv Service, auth Authenticator, generator TokenGenerator, logger *slog.Logger) *HandlerBuilder {
+	useTagNames()
+	streams, closeStreams := context.WithCancel(context.Background())
+	return &HandlerBuilder{
+		srv:           srv,
+		logger:        logger,
+		authenticator: auth,
+		generator:     generator,
+		heartbeat:     defaultHeartbeat,
+		streams:       streams,
+		closeStreams:  closeStreams,
+	}
 }
 
 // GetHandler initializes a default router with corresponding routes
@@ -54,26 +112,27 @@ func (b *HandlerBuilder) GetHandler() http.Handler {
 	r := gin.Default()
 	r.Use(b.errorMiddleware)
 
-	r.GET("/get_token/*admin", b.handleTokenGeneration)
+	r.GET("/openapi.yaml", b.handleSpec)
+	r.GET("/docs", b.handleDocs)
 
-	authenticateGroup := r.Group("/", b.authenticate)
-	authenticateGroup.GET("/user_banner", b.handleUserGetBanner)
-
-	adminGroup := authenticateGroup.Group("/banner", b.authorize)
-	adminGroup.GET("", b.handleListBanners)
-	adminGroup.POST("", b.handleCreateBanner)
-	adminGroup.DELETE("/:id", b.handleDeleteBanner)
-	adminGroup.PATCH("/:id", b.handleUpdateBanner)
-	adminGroup.GET("/versions/:id", b.handleListBannerHistory)
-	adminGroup.PUT("/versions/:id/activate", b.handleSelectBannerVersion)
-	adminGroup.DELETE("/banners", b.handleDeleteBanner)
+	security := map[string][]gin.HandlerFunc{
+		"":           nil,
+		"UserToken":  {b.authenticate},
+		"AdminToken": {b.authenticate, b.authorize},
+	}
+	if b.spec != nil {
+		for scheme, middlewares := range security {
+			security[scheme] = append(middlewares, b.validate)
+		}
+	}
+	RegisterHandlers(r, b, security)
 
 	return r
 }
 
 func (b *HandlerBuilder) log(c *gin.Context) {
 	const op = "handlers.log"
-	log := b.logger.With(op)
+	log := b.logger.With(utils.Text(op))
 	for _, msg := range c.Errors.Errors() {
 		log.Warn(msg)
 	}
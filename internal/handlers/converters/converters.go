@@ -1,8 +1,15 @@
 package converters
 
 import (
+	e "BannerFlow/internal/domain/errors"
 	"BannerFlow/internal/domain/models"
 	"BannerFlow/pkg/api"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 func BannerUpdateRequestToUpdateBanner(req *api.BannerUpdateRequest) *models.UpdateBanner {
@@ -54,24 +61,129 @@ func getDefaultValue[T any](ptr *T) (result T) {
 	return *ptr
 }
 
-func ConstructBannerUserOptions(params *api.UserBannerParams) *models.BannerUserOptions {
+// ConstructBannerUserOptions looks up the banner by tag_id and then by the other tags of the user
+// ConstructBannerUserOptions takes the locale from Accept-Language header unless the query has it
+func ConstructBannerUserOptions(params *api.UserBannerParams, header *api.UserBannerHeaderParams) *models.BannerUserOptions {
+	locale := getDefaultValue(params.Locale)
+	if params.Locale == nil {
+		locale = models.PreferredLocale(getDefaultValue(header.AcceptLanguage))
+	}
+	tagIds := []int{*params.TagId}
+	for _, tagId := range getDefaultValue(params.TagIds) {
+		if !slices.Contains(tagIds, tagId) {
+			tagIds = append(tagIds, tagId)
+		}
+	}
 	return &models.BannerUserOptions{
 		UseLastRevision: getDefaultValue(params.UseLastRevision),
 		BannerIdentOptions: models.BannerIdentOptions{
 			FeatureId: *params.FeatureId,
 			TagId:     *params.TagId,
 		},
+		Attributes: models.UserAttributes{
+			Locale:     locale,
+			Platform:   getDefaultValue(params.Platform),
+			Country:    getDefaultValue(params.Country),
+			AppVersion: getDefaultValue(params.AppVersion),
+			TagIds:     tagIds,
+		},
 	}
 }
 
+// UserBannerStreamToWatchOptions looks up the banner like ConstructBannerUserOptions, the watch is resumed by the caller
+func UserBannerStreamToWatchOptions(params *api.UserBannerStreamParams, header *api.UserBannerStreamHeaderParams) *models.BannerWatchOptions {
+	options := ConstructBannerUserOptions(&api.UserBannerParams{
+		TagId:      params.TagId,
+		FeatureId:  params.FeatureId,
+		TagIds:     params.TagIds,
+		Locale:     params.Locale,
+		Platform:   params.Platform,
+		Country:    params.Country,
+		AppVersion: params.AppVersion,
+	}, &api.UserBannerHeaderParams{AcceptLanguage: header.AcceptLanguage})
+	return &models.BannerWatchOptions{BannerUserOptions: *options}
+}
+
+// UserBannersRequestToOptions takes the locale from Accept-Language header unless the request has it
+func UserBannersRequestToOptions(req *api.UserBannersRequest, header *api.AcceptLanguageParams) *models.BannersUserOptions {
+	locale := getDefaultValue(req.Locale)
+	if req.Locale == nil {
+		locale = models.PreferredLocale(getDefaultValue(header.AcceptLanguage))
+	}
+	tagIds := []int{*req.TagId}
+	for _, tagId := range getDefaultValue(req.TagIds) {
+		if !slices.Contains(tagIds, tagId) {
+			tagIds = append(tagIds, tagId)
+		}
+	}
+	return &models.BannersUserOptions{
+		FeatureIds:      *req.FeatureIds,
+		UseLastRevision: getDefaultValue(req.UseLastRevision),
+		Attributes: models.UserAttributes{
+			Locale:     locale,
+			Platform:   getDefaultValue(req.Platform),
+			Country:    getDefaultValue(req.Country),
+			AppVersion: getDefaultValue(req.AppVersion),
+			TagIds:     tagIds,
+		},
+	}
+}
+
+// UserBannersToResponse marks features without a banner for the user as not found
+func UserBannersToResponse(banners map[int]*models.UserBanner) *api.UserBannersResponse {
+	items := make(map[string]api.UserBannerItem, len(banners))
+	for featureId, banner := range banners {
+		status := http.StatusNotFound
+		item := api.UserBannerItem{Status: &status}
+		if banner != nil {
+			status = http.StatusOK
+			item.Content = &banner.Content
+		}
+		items[strconv.Itoa(featureId)] = item
+	}
+	return &api.UserBannersResponse{Banners: &items}
+}
+
+// ConstructBannerListOptions converts times to UTC the banners are stored in, sort is field name with optional minus
 func ConstructBannerListOptions(params *api.ListBannerParams) *models.BannerListOptions {
+	sort := getDefaultValue(params.Sort)
+	desc := strings.HasPrefix(sort, "-")
+	sort = strings.TrimPrefix(sort, "-")
+	if sort == "" {
+		sort = string(models.SortById)
+	}
 	return &models.BannerListOptions{
 		BannerIdentOptions: models.BannerIdentOptions{
 			FeatureId: setZeroValueIfEmpty(params.FeatureId),
 			TagId:     setZeroValueIfEmpty(params.TagId),
 		},
-		Limit:  setZeroValueIfEmpty(params.Limit),
-		Offset: setZeroValueIfEmpty(params.Offset),
+		FeatureName:   getDefaultValue(params.FeatureName),
+		TagName:       getDefaultValue(params.TagName),
+		TagIds:        getDefaultValue(params.TagIds),
+		IsActive:      params.IsActive,
+		CreatedAfter:  getDefaultValue(params.CreatedAfter).UTC(),
+		CreatedBefore: getDefaultValue(params.CreatedBefore).UTC(),
+		UpdatedAfter:  getDefaultValue(params.UpdatedAfter).UTC(),
+		UpdatedBefore: getDefaultValue(params.UpdatedBefore).UTC(),
+		Sort:          models.ListSort(sort),
+		Desc:          desc,
+		WithTotal:     getDefaultValue(params.IncludeTotal),
+		Limit:         setZeroValueIfEmpty(params.Limit),
+		Offset:        setZeroValueIfEmpty(params.Offset),
+	}
+}
+
+// ListTrashParamsToListOptions lists deleted banners by id like the default listing
+func ListTrashParamsToListOptions(params *api.ListTrashParams) *models.BannerListOptions {
+	return &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{
+			FeatureId: setZeroValueIfEmpty(params.FeatureId),
+			TagId:     setZeroValueIfEmpty(params.TagId),
+		},
+		Sort:    models.SortById,
+		Deleted: true,
+		Limit:   setZeroValueIfEmpty(params.Limit),
+		Offset:  models.ZeroValue,
 	}
 }
 
@@ -82,22 +194,61 @@ func setZeroValueIfEmpty(arg *int) int {
 	return *arg
 }
 
+func BannerPageToResponse(page *models.BannerPage) *api.BannerListResponse {
+	items := BannersExtToInnerResponses(page.Banners)
+	if items == nil {
+		items = []api.BannerResponse{}
+	}
+	response := &api.BannerListResponse{Items: &items}
+	if page.Next != nil {
+		next := models.EncodeCursor(page.Next)
+		response.NextCursor = &next
+	}
+	if page.Total > models.ZeroValue {
+		response.Total = &page.Total
+	}
+	return response
+}
+
 func BannersExtToInnerResponses(banners []models.BannerExt) []api.BannerResponse {
 	var result []api.BannerResponse
 	for _, banner := range banners {
-		result = append(result, api.BannerResponse{
-			BannerId:  &banner.BannerId,
-			TagIds:    &banner.TagIds,
-			FeatureId: &banner.FeatureId,
-			Content:   &banner.Content,
-			IsActive:  &banner.IsActive,
-			CreatedAt: &banner.CreatedAt,
-			UpdatedAt: &banner.UpdatedAt,
-		})
+		result = append(result, BannerExtToResponse(banner))
 	}
 	return result
 }
 
+// BannerExtToResponse takes the banner by value, so every response refers to its own copy
+func BannerExtToResponse(banner models.BannerExt) api.BannerResponse {
+	etag := models.ETag(banner.Revision, banner.IsActive)
+	resp := api.BannerResponse{
+		BannerId:  &banner.BannerId,
+		TagIds:    &banner.TagIds,
+		FeatureId: &banner.FeatureId,
+		Content:   &banner.Content,
+		IsActive:  &banner.IsActive,
+		CreatedAt: &banner.CreatedAt,
+		UpdatedAt: &banner.UpdatedAt,
+		Revision:  &banner.Revision,
+		Etag:      &etag,
+		DeletedAt: banner.DeletedAt,
+	}
+	if banner.State != "" {
+		state := string(banner.State)
+		resp.State = &state
+		resp.Live = &banner.Live
+	}
+	return resp
+}
+
+func IfMatchParamsToETag(params *api.IfMatchParams) string {
+	return getDefaultValue(params.IfMatch)
+}
+
+func IfNoneMatchParamsToETag(params *api.UserBannerHeaderParams) string {
+	return getDefaultValue(params.IfNoneMatch)
+}
+
 func ConstructGet201Response(id int) *api.BannerIdResponse {
 	return &api.BannerIdResponse{
 		BannerId: &id,
@@ -112,14 +263,593 @@ func HistoryBannersToVersionResponse(banners []models.HistoryBanner) []api.Banne
 			TagIds:    &banner.TagIds,
 			FeatureId: &banner.FeatureId,
 			Version:   &banner.Version,
+			Actor:     &banner.Actor,
+			IsActive:  &banner.IsActive,
+			CreatedAt: &banner.CreatedAt,
+		})
+	}
+	return result
+}
+
+func ConstructHistoryListOptions(params *api.HistoryParams) *models.HistoryListOptions {
+	return &models.HistoryListOptions{
+		Limit:  setZeroValueIfEmpty(params.Limit),
+		Offset: setZeroValueIfEmpty(params.Offset),
+	}
+}
+
+// DiffParamsToVersions returns compared versions, missing one is the current state
+func DiffParamsToVersions(params *api.DiffParams) (int, int) {
+	return params.From, setZeroValueIfEmpty(params.To)
+}
+
+func BannerDiffToResponse(diff *models.BannerDiff) *api.BannerDiffResponse {
+	operations := make([]api.JsonPatchOperation, 0, len(diff.Patch))
+	for _, operation := range diff.Patch {
+		op, path := operation.Op, operation.Path
+		item := api.JsonPatchOperation{Op: &op, Path: &path, Value: operation.Value}
+		if operation.From != "" {
+			from := operation.From
+			item.From = &from
+		}
+		operations = append(operations, item)
+	}
+	resp := &api.BannerDiffResponse{
+		From:    &diff.From,
+		Patch:   &operations,
+		Summary: &diff.Summary,
+	}
+	if diff.To != models.ZeroValue {
+		resp.To = &diff.To
+	}
+	return resp
+}
+
+func ConstructBulkDeleteOptions(params *api.DeleteBannerParams) *models.BulkDeleteOptions {
+	return &models.BulkDeleteOptions{
+		BulkDeleteFilter: models.BulkDeleteFilter{
+			FeatureId:     setZeroValueIfEmpty(params.FeatureId),
+			TagIds:        getDefaultValue(params.TagIds),
+			InactiveOnly:  getDefaultValue(params.InactiveOnly),
+			UpdatedBefore: getDefaultValue(params.UpdatedBefore).UTC(),
+		},
+		DryRun:       getDefaultValue(params.DryRun),
+		ConfirmToken: getDefaultValue(params.ConfirmToken),
+	}
+}
+
+func DeletePreviewToResponse(preview *models.DeletePreview) *api.DeletePreviewResponse {
+	count := len(preview.Ids)
+	return &api.DeletePreviewResponse{
+		Count:           &count,
+		BannerIds:       &preview.Ids,
+		ConfirmToken:    &preview.Token,
+		ConfirmRequired: &preview.ConfirmRequired,
+	}
+}
+
+func DeleteJobToResponse(job *models.DeleteJob) *api.DeleteJobResponse {
+	status := string(job.Status)
+	removedIds := job.RemovedIds
+	if removedIds == nil {
+		removedIds = []int{}
+	}
+	removedCount := len(removedIds)
+	resp := &api.DeleteJobResponse{
+		JobId:        &job.Id,
+		Status:       &status,
+		InactiveOnly: &job.Filter.InactiveOnly,
+		RemovedIds:   &removedIds,
+		RemovedCount: &removedCount,
+		CreatedAt:    &job.CreatedAt,
+	}
+	if job.Filter.FeatureId != models.ZeroValue {
+		resp.FeatureId = &job.Filter.FeatureId
+	}
+	if len(job.Filter.TagIds) > 0 {
+		resp.TagIds = &job.Filter.TagIds
+	}
+	if !job.Filter.UpdatedBefore.IsZero() {
+		resp.UpdatedBefore = &job.Filter.UpdatedBefore
+	}
+	if !job.FinishedAt.IsZero() {
+		resp.FinishedAt = &job.FinishedAt
+	}
+	return resp
+}
+
+func ValidationErrorToResponse(err *e.ValidationError) *api.BannerErrorResponse {
+	msg := e.ErrorValidation.Error()
+	details := violationsToFieldErrors(err.Violations)
+	return &api.BannerErrorResponse{
+		Error:   &msg,
+		Details: &details,
+	}
+}
+
+func violationsToFieldErrors(violations []e.FieldViolation) []api.FieldError {
+	details := make([]api.FieldError, 0, len(violations))
+	for _, violation := range violations {
+		field, message := violation.Field, violation.Message
+		details = append(details, api.FieldError{Field: &field, Message: &message})
+	}
+	return details
+}
+
+func FeatureSchemaToResponse(schema *models.FeatureSchema) *api.FeatureSchemaResponse {
+	return &api.FeatureSchemaResponse{
+		FeatureId: &schema.FeatureId,
+		Schema:    &schema.Schema,
+		CreatedAt: &schema.CreatedAt,
+		UpdatedAt: &schema.UpdatedAt,
+	}
+}
+
+func FeatureSchemasToResponses(schemas []models.FeatureSchema) []api.FeatureSchemaResponse {
+	result := make([]api.FeatureSchemaResponse, 0, len(schemas))
+	for i := range schemas {
+		result = append(result, *FeatureSchemaToResponse(&schemas[i]))
+	}
+	return result
+}
+
+func SchemaReportToResponse(report *models.SchemaReport) *api.SchemaReportResponse {
+	invalid := make([]api.InvalidBanner, 0, len(report.Invalid))
+	for _, banner := range report.Invalid {
+		id, details := banner.BannerId, violationsToFieldErrors(banner.Violations)
+		invalid = append(invalid, api.InvalidBanner{BannerId: &id, Details: &details})
+	}
+	return &api.SchemaReportResponse{
+		FeatureId: &report.FeatureId,
+		DryRun:    &report.DryRun,
+		Checked:   &report.Checked,
+		Invalid:   &invalid,
+	}
+}
+
+func RegistryRequestToEntry(req *api.RegistryRequest) *models.RegistryEntry {
+	return &models.RegistryEntry{
+		Id:          setZeroValueIfEmpty(req.Id),
+		Name:        *req.Name,
+		Description: getDefaultValue(req.Description),
+		Owner:       getDefaultValue(req.Owner),
+	}
+}
+
+func RegistryEntryToResponse(entry *models.RegistryEntry) *api.RegistryResponse {
+	return &api.RegistryResponse{
+		Id:          &entry.Id,
+		Name:        &entry.Name,
+		Description: &entry.Description,
+		Owner:       &entry.Owner,
+		CreatedAt:   &entry.CreatedAt,
+		UpdatedAt:   &entry.UpdatedAt,
+	}
+}
+
+func RegistryEntriesToResponses(entries []models.RegistryEntry) []api.RegistryResponse {
+	result := make([]api.RegistryResponse, 0, len(entries))
+	for i := range entries {
+		result = append(result, *RegistryEntryToResponse(&entries[i]))
+	}
+	return result
+}
+
+func ConstructRegistryIdResponse(id int) *api.RegistryIdResponse {
+	return &api.RegistryIdResponse{
+		Id: &id,
+	}
+}
+
+const atomicBatchMode = "atomic"
+
+func BatchRequestToBatch(req *api.BatchRequest) *models.Batch {
+	operations := getDefaultValue(req.Operations)
+	batch := &models.Batch{
+		Atomic:     getDefaultValue(req.Mode) == atomicBatchMode,
+		Operations: make([]models.BatchOperation, 0, len(operations)),
+	}
+	for _, operation := range operations {
+		result := models.BatchOperation{
+			Op:      models.BatchOp(getDefaultValue(operation.Op)),
+			Id:      getDefaultValue(operation.Id),
+			IfMatch: getDefaultValue(operation.IfMatch),
+		}
+		if operation.Banner != nil {
+			result.Banner = *BannerUpdateRequestToUpdateBanner(operation.Banner)
+		}
+		batch.Operations = append(batch.Operations, result)
+	}
+	return batch
+}
+
+// BatchResultToResponse builds item of the multi-status response, status is the one error would get as a response
+func BatchResultToResponse(index int, result models.BatchResult, status int, message string) api.BatchItemResponse {
+	item := api.BatchItemResponse{Index: &index, Status: &status}
+	if result.Id > 0 {
+		item.BannerId = &result.Id
+	}
+	if result.Err == nil {
+		return item
+	}
+	item.Error = &message
+	var validationErr *e.ValidationError
+	if errors.As(result.Err, &validationErr) {
+		details := violationsToFieldErrors(validationErr.Violations)
+		item.Details = &details
+	}
+	return item
+}
+
+func ExportBannersParamsToListOptions(params *api.ExportBannersParams) *models.BannerListOptions {
+	return &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{
+			FeatureId: setZeroValueIfEmpty(params.FeatureId),
+			TagId:     setZeroValueIfEmpty(params.TagId),
+		},
+		Limit:  models.ZeroValue,
+		Offset: models.ZeroValue,
+	}
+}
+
+func BannerExtToRecord(banner models.BannerExt) api.BannerRecord {
+	return api.BannerRecord{
+		BannerId:  &banner.BannerId,
+		FeatureId: &banner.FeatureId,
+		TagIds:    &banner.TagIds,
+		Content:   &banner.Content,
+		IsActive:  &banner.IsActive,
+	}
+}
+
+// BannerRecordToImportRecord expects the record to be validated, banner_id is not imported
+func BannerRecordToImportRecord(line int, record *api.BannerRecord) *models.ImportRecord {
+	return &models.ImportRecord{
+		Line: line,
+		Banner: models.Banner{
+			BaseBanner: models.BaseBanner{
+				UserBanner: models.UserBanner{Content: getDefaultValue(record.Content)},
+				FeatureId:  getDefaultValue(record.FeatureId),
+				TagIds:     getDefaultValue(record.TagIds),
+			},
+			IsActive: getDefaultValue(record.IsActive),
+		},
+	}
+}
+
+func ImportBannersParamsToOptions(params *api.ImportBannersParams) *models.ImportOptions {
+	mode := models.ImportFailOnConflict
+	if params.Mode != nil {
+		mode = models.ImportMode(*params.Mode)
+	}
+	return &models.ImportOptions{
+		Mode:       mode,
+		DryRun:     getDefaultValue(params.DryRun),
+		MaxCreated: models.ZeroValue,
+	}
+}
+
+func ImportReportToResponse(report *models.ImportReport) *api.ImportReportResponse {
+	mode := string(report.Mode)
+	errs := make([]api.ImportRecordError, 0, len(report.Errors))
+	for _, recordErr := range report.Errors {
+		line, details := recordErr.Line, violationsToFieldErrors(recordErr.Violations)
+		errs = append(errs, api.ImportRecordError{Line: &line, Details: &details})
+	}
+	return &api.ImportReportResponse{
+		Mode:    &mode,
+		DryRun:  &report.DryRun,
+		Applied: &report.Applied,
+		Total:   &report.Total,
+		Created: &report.Created,
+		Updated: &report.Updated,
+		Skipped: &report.Skipped,
+		Failed:  &report.Failed,
+		Errors:  &errs,
+	}
+}
+
+// SearchBannersParamsToOptions leaves predicates and the cursor to the handler, they are parsed with their own errors
+func SearchBannersParamsToOptions(params *api.SearchBannersParams) *models.BannerSearchOptions {
+	return &models.BannerSearchOptions{
+		BannerListOptions: models.BannerListOptions{
+			BannerIdentOptions: models.BannerIdentOptions{
+				FeatureId: setZeroValueIfEmpty(params.FeatureId),
+				TagId:     setZeroValueIfEmpty(params.TagId),
+			},
+			IsActive:  params.IsActive,
+			WithTotal: getDefaultValue(params.IncludeTotal),
+			Limit:     setZeroValueIfEmpty(params.Limit),
+			Offset:    models.ZeroValue,
+		},
+		Query: getDefaultValue(params.Q),
+	}
+}
+
+func SearchPageToResponse(page *models.SearchPage) *api.BannerSearchResponse {
+	items := make([]api.BannerSearchHit, 0, len(page.Hits))
+	for _, hit := range page.Hits {
+		banner := BannerExtToResponse(hit.BannerExt)
+		rank := float64(hit.Rank)
+		items = append(items, api.BannerSearchHit{Banner: &banner, Rank: &rank})
+	}
+	response := &api.BannerSearchResponse{Items: &items}
+	if page.Next != nil {
+		next := models.EncodeCursor(page.Next)
+		response.NextCursor = &next
+	}
+	if page.Total > models.ZeroValue {
+		response.Total = &page.Total
+	}
+	return response
+}
+
+// PendingRevisionToResponse takes the revision by value, so every response refers to its own copy
+func PendingRevisionToResponse(revision models.PendingRevision) api.BannerRevisionResponse {
+	state := string(revision.State)
+	resp := api.BannerRevisionResponse{
+		BannerId:  &revision.BannerId,
+		TagIds:    &revision.TagIds,
+		FeatureId: &revision.FeatureId,
+		Content:   &revision.Content,
+		IsActive:  &revision.IsActive,
+		State:     &state,
+		Author:    &revision.Author,
+		UpdatedAt: &revision.UpdatedAt,
+	}
+	if revision.Reviewer != "" {
+		resp.Reviewer = &revision.Reviewer
+	}
+	if revision.Comment != "" {
+		resp.Comment = &revision.Comment
+	}
+	return resp
+}
+
+func PendingRevisionsToResponse(revisions []models.PendingRevision) []api.BannerRevisionResponse {
+	result := make([]api.BannerRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		result = append(result, PendingRevisionToResponse(revision))
+	}
+	return result
+}
+
+func ListReviewsParamsToOptions(params *api.ListReviewsParams) *models.ReviewListOptions {
+	state := models.StateInReview
+	if params.State != nil {
+		state = models.BannerState(*params.State)
+	}
+	return &models.ReviewListOptions{
+		State:  state,
+		Limit:  setZeroValueIfEmpty(params.Limit),
+		Offset: setZeroValueIfEmpty(params.Offset),
+	}
+}
+
+func TenantQuotaToResponse(quota *models.TenantQuota) *api.TenantQuotaResponse {
+	return &api.TenantQuotaResponse{
+		Tenant:     &quota.Tenant,
+		Banners:    &quota.Banners,
+		MaxBanners: &quota.MaxBanners,
+	}
+}
+
+func TargetingRequestToTargeting(id int, req *api.TargetingRequest) *models.Targeting {
+	return &models.Targeting{
+		BannerId: id,
+		Priority: getDefaultValue(req.Priority),
+		Rule: models.TargetingRule{
+			Locales:    getDefaultValue(req.Rule.Locales),
+			Platforms:  getDefaultValue(req.Rule.Platforms),
+			Countries:  getDefaultValue(req.Rule.Countries),
+			MinVersion: getDefaultValue(req.Rule.MinVersion),
+			MaxVersion: getDefaultValue(req.Rule.MaxVersion),
+			TagIds:     getDefaultValue(req.Rule.TagIds),
+		},
+	}
+}
+
+func TargetingToResponse(targeting *models.Targeting) *api.TargetingResponse {
+	rule := targetingRuleToResponse(targeting.Rule)
+	return &api.TargetingResponse{
+		BannerId: &targeting.BannerId,
+		Priority: &targeting.Priority,
+		Rule:     &rule,
+	}
+}
+
+// targetingRuleToResponse takes the rule by value and leaves out its empty conditions
+func targetingRuleToResponse(rule models.TargetingRule) api.TargetingRule {
+	resp := api.TargetingRule{}
+	if len(rule.Locales) > 0 {
+		resp.Locales = &rule.Locales
+	}
+	if len(rule.Platforms) > 0 {
+		resp.Platforms = &rule.Platforms
+	}
+	if len(rule.Countries) > 0 {
+		resp.Countries = &rule.Countries
+	}
+	if rule.MinVersion != "" {
+		resp.MinVersion = &rule.MinVersion
+	}
+	if rule.MaxVersion != "" {
+		resp.MaxVersion = &rule.MaxVersion
+	}
+	if len(rule.TagIds) > 0 {
+		resp.TagIds = &rule.TagIds
+	}
+	return resp
+}
+
+func EvaluateRequestToOptions(req *api.EvaluateRequest) *models.EvaluationOptions {
+	return &models.EvaluationOptions{
+		FeatureId: *req.FeatureId,
+		Attributes: models.UserAttributes{
+			Locale:     getDefaultValue(req.Locale),
+			Platform:   getDefaultValue(req.Platform),
+			Country:    getDefaultValue(req.Country),
+			AppVersion: getDefaultValue(req.AppVersion),
+			TagIds:     *req.TagIds,
+		},
+	}
+}
+
+func EvaluationToResponse(evaluation *models.Evaluation) *api.EvaluateResponse {
+	candidates := make([]api.EvaluatedCandidate, 0, len(evaluation.Candidates))
+	for _, candidate := range evaluation.Candidates {
+		candidates = append(candidates, candidateResultToResponse(candidate))
+	}
+	response := &api.EvaluateResponse{Candidates: &candidates}
+	if evaluation.BannerId != models.ZeroValue {
+		response.BannerId = &evaluation.BannerId
+		response.Content = &evaluation.Content
+	}
+	if evaluation.Locale != models.DefaultLocale {
+		response.Locale = &evaluation.Locale
+	}
+	return response
+}
+
+// candidateResultToResponse takes the result by value, so every response refers to its own copy
+func candidateResultToResponse(candidate models.CandidateResult) api.EvaluatedCandidate {
+	if candidate.Reasons == nil {
+		candidate.Reasons = []string{}
+	}
+	return api.EvaluatedCandidate{
+		BannerId:  &candidate.BannerId,
+		Priority:  &candidate.Priority,
+		IsActive:  &candidate.IsActive,
+		Published: &candidate.Live,
+		Matched:   &candidate.Matched,
+		Served:    &candidate.Served,
+		Reasons:   &candidate.Reasons,
+	}
+}
+
+func TranslationsToResponse(translations []models.Translation) []api.TranslationResponse {
+	result := make([]api.TranslationResponse, 0, len(translations))
+	for i := range translations {
+		translation := &translations[i]
+		result = append(result, api.TranslationResponse{
+			Locale:    &translation.Locale,
+			Content:   &translation.Content,
+			UpdatedAt: &translation.UpdatedAt,
+		})
+	}
+	return result
+}
+
+func MissingTranslationsToResponse(missing []models.MissingTranslations) []api.MissingTranslationsResponse {
+	result := make([]api.MissingTranslationsResponse, 0, len(missing))
+	for i := range missing {
+		banner := &missing[i]
+		result = append(result, api.MissingTranslationsResponse{
+			BannerId:  &banner.BannerId,
+			FeatureId: &banner.FeatureId,
+			Locales:   &banner.Locales,
 		})
 	}
 	return result
 }
 
-func ConstructIdentOptions(params *api.DeleteBannerParams) *models.BannerIdentOptions {
-	return &models.BannerIdentOptions{
-		FeatureId: setZeroValueIfEmpty(params.FeatureId),
-		TagId:     setZeroValueIfEmpty(params.TagIds),
+// MissingTranslationsParamsToFeatureId returns models.ZeroValue to check banners of every feature
+func MissingTranslationsParamsToFeatureId(params *api.MissingTranslationsParams) int {
+	return setZeroValueIfEmpty(params.FeatureId)
+}
+
+// WebhookRequestToWebhook converts the request, webhooks are active unless is_active is false
+func WebhookRequestToWebhook(id int, req *api.WebhookRequest) *models.Webhook {
+	events := getDefaultValue(req.Events)
+	webhook := &models.Webhook{
+		Id:       id,
+		Url:      *req.Url,
+		Events:   make([]models.WebhookEvent, 0, len(events)),
+		Secret:   getDefaultValue(req.Secret),
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+	for _, event := range events {
+		webhook.Events = append(webhook.Events, models.WebhookEvent(event))
+	}
+	return webhook
+}
+
+func WebhookToResponse(webhook *models.Webhook) *api.WebhookResponse {
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+	return &api.WebhookResponse{
+		Id:        &webhook.Id,
+		Url:       &webhook.Url,
+		Events:    &events,
+		IsActive:  &webhook.IsActive,
+		CreatedAt: &webhook.CreatedAt,
+		UpdatedAt: &webhook.UpdatedAt,
+	}
+}
+
+func WebhooksToResponses(webhooks []models.Webhook) []api.WebhookResponse {
+	result := make([]api.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		result = append(result, *WebhookToResponse(&webhooks[i]))
 	}
+	return result
+}
+
+func ConstructWebhookIdResponse(id int) *api.WebhookIdResponse {
+	return &api.WebhookIdResponse{
+		Id: &id,
+	}
+}
+
+func DeliveriesParamsToOptions(webhookId int, params *api.DeliveriesParams) *models.DeliveryListOptions {
+	return &models.DeliveryListOptions{
+		WebhookId: webhookId,
+		Status:    models.DeliveryStatus(getDefaultValue(params.Status)),
+		Limit:     setZeroValueIfEmpty(params.Limit),
+		Offset:    setZeroValueIfEmpty(params.Offset),
+	}
+}
+
+func DeadLettersParamsToOptions(params *api.DeadLettersParams) *models.DeliveryListOptions {
+	return &models.DeliveryListOptions{
+		WebhookId: models.ZeroValue,
+		Status:    models.DeliveryDead,
+		Limit:     setZeroValueIfEmpty(params.Limit),
+		Offset:    setZeroValueIfEmpty(params.Offset),
+	}
+}
+
+func WebhookDeliveriesToResponses(deliveries []models.WebhookDelivery) []api.WebhookDeliveryResponse {
+	result := make([]api.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		id := int(delivery.Id)
+		event := string(delivery.Event)
+		status := string(delivery.Status)
+		payload := map[string]interface{}{}
+		// the payload is built by the database, it is always an object
+		_ = json.Unmarshal(delivery.Payload, &payload)
+		response := api.WebhookDeliveryResponse{
+			Id:            &id,
+			WebhookId:     &delivery.WebhookId,
+			Event:         &event,
+			BannerId:      &delivery.BannerId,
+			Payload:       &payload,
+			Status:        &status,
+			Attempts:      &delivery.Attempts,
+			NextAttemptAt: &delivery.NextAttemptAt,
+			CreatedAt:     &delivery.CreatedAt,
+			DeliveredAt:   delivery.DeliveredAt,
+		}
+		if delivery.ResponseStatus != 0 {
+			response.ResponseStatus = &delivery.ResponseStatus
+		}
+		if delivery.LastError != "" {
+			response.LastError = &delivery.LastError
+		}
+		result = append(result, response)
+	}
+	return result
 }
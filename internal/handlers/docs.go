@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"BannerFlow/pkg/api"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8"/>
+  <title>BannerFlow API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css"/>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+  window.onload = () => {
+    window.ui = SwaggerUIBundle({url: "/openapi.yaml", dom_id: "#swagger-ui"});
+  };
+</script>
+</body>
+</html>`
+
+func (b *HandlerBuilder) handleSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml", api.Spec)
+}
+
+func (b *HandlerBuilder) handleDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
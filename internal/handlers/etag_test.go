@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type etagService struct {
+	Service
+	banner  models.BannerExt
+	ifMatch string
+}
+
+func (s *etagService) GetBanner(_ context.Context, _ int) (*models.BannerExt, error) {
+	return &s.banner, nil
+}
+
+func (s *etagService) UserGetBanners(_ context.Context, _ *models.BannerUserOptions) (*models.UserBanner, error) {
+	return &s.banner.UserBanner, nil
+}
+
+func (s *etagService) DeleteBanner(_ context.Context, _ int, ifMatch string) error {
+	s.ifMatch = ifMatch
+	if ifMatch != "" && !models.MatchETag(ifMatch, models.ETag(s.banner.Revision, s.banner.IsActive), false) {
+		return e.ErrorPreconditionFailed
+	}
+	return nil
+}
+
+func TestETagHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	adminToken, err := sso.GenerateToken(true)
+	require.NoError(t, err)
+	srv := &etagService{banner: models.BannerExt{
+		BannerId: 1,
+		Revision: 2,
+		Banner: models.Banner{
+			IsActive:   true,
+			BaseBanner: models.BaseBanner{UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		},
+	}}
+	handler := New(srv, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler()
+	serve := func(method, target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("token", adminToken)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	bannerETag := models.ETag(2, true)
+
+	w := serve(http.MethodGet, "/banner/1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, bannerETag, w.Header().Get("ETag"))
+
+	w = serve(http.MethodGet, "/user_banner?tag_id=1&feature_id=1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	contentETag := w.Header().Get("ETag")
+	assert.Equal(t, models.ContentETag(srv.banner.Content), contentETag)
+
+	w = serve(http.MethodGet, "/user_banner?tag_id=1&feature_id=1", map[string]string{"If-None-Match": contentETag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+
+	w = serve(http.MethodDelete, "/banner/1", map[string]string{"If-Match": models.ETag(1, true)})
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = serve(http.MethodDelete, "/banner/1", map[string]string{"If-Match": bannerETag})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, bannerETag, srv.ifMatch)
+}
@@ -4,23 +4,36 @@ import (
 	e "BannerFlow/internal/domain/errors"
 	"BannerFlow/internal/domain/models"
 	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/internal/patch"
 	"BannerFlow/pkg/api"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
-func (b *HandlerBuilder) handleDeleteBannerByTagOrFeature(c *gin.Context) {
-	err := b.DeleteBannerByTagOrFeature(c)
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+	etagHeader     = "ETag"
+)
+
+func (b *HandlerBuilder) DeleteBanners(c *gin.Context) {
+	preview, dryRun, err := b.deleteBannerByTagOrFeature(c)
 	if err != nil {
 		collectErrors(c, err)
 		return
 	}
-	c.Status(http.StatusAccepted)
+	if dryRun {
+		c.JSON(http.StatusOK, converters.DeletePreviewToResponse(preview))
+		return
+	}
+	c.JSON(http.StatusAccepted, converters.DeleteJobToResponse(preview.Job))
 }
 
-func (b *HandlerBuilder) handleListBannerHistory(c *gin.Context) {
-	banners, err := b.ListBannerHistory(c)
+func (b *HandlerBuilder) ListBannerVersions(c *gin.Context) {
+	banners, err := b.listBannerHistory(c)
 	if err != nil {
 		collectErrors(c, err)
 		return
@@ -28,7 +41,16 @@ func (b *HandlerBuilder) handleListBannerHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, converters.HistoryBannersToVersionResponse(banners))
 }
 
-func (b *HandlerBuilder) handleSelectBannerVersion(c *gin.Context) {
+func (b *HandlerBuilder) DiffBannerVersions(c *gin.Context) {
+	diff, err := b.diffBannerVersions(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.BannerDiffToResponse(diff))
+}
+
+func (b *HandlerBuilder) ActivateBannerVersion(c *gin.Context) {
 	err := b.selectBannerVersion(c)
 	if err != nil {
 		collectErrors(c, err)
@@ -37,25 +59,50 @@ func (b *HandlerBuilder) handleSelectBannerVersion(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
-func (b *HandlerBuilder) handleListBanners(c *gin.Context) {
-	banners, err := b.listBanner(c)
+func (b *HandlerBuilder) ListBanners(c *gin.Context) {
+	page, err := b.listBanner(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.BannerPageToResponse(page))
+}
+
+func (b *HandlerBuilder) GetBanner(c *gin.Context) {
+	banner, err := b.getBanner(c)
 	if err != nil {
 		collectErrors(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, converters.BannersExtToInnerResponses(banners))
+	c.Header(etagHeader, models.ETag(banner.Revision, banner.IsActive))
+	c.JSON(http.StatusOK, converters.BannerExtToResponse(*banner))
 }
 
-func (b *HandlerBuilder) handleUserGetBanner(c *gin.Context) {
+func (b *HandlerBuilder) GetUserBanner(c *gin.Context) {
 	content, err := b.userGetBanner(c)
 	if err != nil {
 		collectErrors(c, err)
 		return
 	}
+	etag := models.ContentETag(content.Content)
+	c.Header(etagHeader, etag)
+	if notModified(c, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
 	c.JSON(http.StatusOK, content.Content)
 }
 
-func (b *HandlerBuilder) handleCreateBanner(c *gin.Context) {
+func (b *HandlerBuilder) GetUserBanners(c *gin.Context) {
+	banners, err := b.userGetBanners(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.UserBannersToResponse(banners))
+}
+
+func (b *HandlerBuilder) CreateBanner(c *gin.Context) {
 	id, err := b.createBanner(c)
 	if err != nil {
 		collectErrors(c, err)
@@ -64,7 +111,7 @@ func (b *HandlerBuilder) handleCreateBanner(c *gin.Context) {
 	c.JSON(http.StatusCreated, converters.ConstructGet201Response(id))
 }
 
-func (b *HandlerBuilder) handleUpdateBanner(c *gin.Context) {
+func (b *HandlerBuilder) UpdateBanner(c *gin.Context) {
 	err := b.updateBanner(c)
 	if err != nil {
 		collectErrors(c, err)
@@ -73,7 +120,7 @@ func (b *HandlerBuilder) handleUpdateBanner(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
-func (b *HandlerBuilder) handleDeleteBanner(c *gin.Context) {
+func (b *HandlerBuilder) DeleteBanner(c *gin.Context) {
 	err := b.deleteBanner(c)
 	if err != nil {
 		collectErrors(c, err)
@@ -82,68 +129,112 @@ func (b *HandlerBuilder) handleDeleteBanner(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-func (b *HandlerBuilder) DeleteBannerByTagOrFeature(c *gin.Context) error {
+func (b *HandlerBuilder) deleteBannerByTagOrFeature(c *gin.Context) (*models.DeletePreview, bool, error) {
 	params := &api.DeleteBannerParams{}
 	err := c.ShouldBindQuery(params)
 	if err != nil {
-		return fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return nil, false, bindingError("query", err)
 	}
-	return b.srv.DeleteBannersByTagOrFeature(c.Request.Context(), converters.ConstructIdentOptions(params))
+	options := converters.ConstructBulkDeleteOptions(params)
+	preview, err := b.srv.DeleteBannersByTagOrFeature(c.Request.Context(), options)
+	return preview, options.DryRun, err
 }
 
 func (b *HandlerBuilder) selectBannerVersion(c *gin.Context) error {
 	id := &api.IdParams{}
 	err := c.ShouldBindUri(id)
 	if err != nil {
-		return fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return bindingError("path", err)
 	}
 	version := &api.SelectBannersParams{}
 	err = c.ShouldBindQuery(version)
 	if err != nil {
-		return fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return bindingError("query", err)
+	}
+	etag, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+	return b.srv.SelectBannerVersion(c.Request.Context(), id.Id, version.Version, etag)
+}
+
+func (b *HandlerBuilder) diffBannerVersions(c *gin.Context) (*models.BannerDiff, error) {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	params := &api.DiffParams{}
+	err = c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
 	}
-	return b.srv.SelectBannerVersion(c.Request.Context(), id.Id, version.Version)
+	from, to := converters.DiffParamsToVersions(params)
+	return b.srv.DiffBannerVersions(c.Request.Context(), id.Id, from, to)
 }
 
-func (b *HandlerBuilder) ListBannerHistory(c *gin.Context) ([]models.HistoryBanner, error) {
+func (b *HandlerBuilder) listBannerHistory(c *gin.Context) ([]models.HistoryBanner, error) {
 	id := &api.IdParams{}
 	err := c.ShouldBindUri(id)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return nil, bindingError("path", err)
 	}
-	return b.srv.ListBannerHistory(c.Request.Context(), id.Id)
+	params := &api.HistoryParams{}
+	err = c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	return b.srv.ListBannerHistory(c.Request.Context(), id.Id, converters.ConstructHistoryListOptions(params))
 }
 
 func (b *HandlerBuilder) updateBanner(c *gin.Context) error {
 	id := &api.IdParams{}
 	err := c.ShouldBindUri(id)
 	if err != nil {
-		return fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return bindingError("path", err)
+	}
+	etag, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+	switch c.ContentType() {
+	case mergePatchType, jsonPatchType:
+		contentPatch, err := readContentPatch(c)
+		if err != nil {
+			return err
+		}
+		return b.srv.PatchBannerContent(c.Request.Context(), id.Id, contentPatch, etag)
 	}
 	req, err := readRequest[api.BannerUpdateRequest](c)
 	if err != nil {
-		return fmt.Errorf("%w: %w", e.ErrorInRequestBody, err)
+		return err
 	}
 	updateBanner := converters.BannerUpdateRequestToUpdateBanner(req)
 	if updateBanner.Flags == models.ZeroBit {
-		return fmt.Errorf("%w: all fields are empty", e.ErrorInRequestBody)
+		return e.NewValidationError(e.FieldViolation{Field: "body", Message: "all fields are empty"})
 	}
-	return b.srv.UpdateBanner(c.Request.Context(), id.Id, updateBanner)
+	return b.srv.UpdateBanner(c.Request.Context(), id.Id, updateBanner, etag)
 }
 
-func (b *HandlerBuilder) listBanner(c *gin.Context) ([]models.BannerExt, error) {
+func (b *HandlerBuilder) listBanner(c *gin.Context) (*models.BannerPage, error) {
 	params := &api.ListBannerParams{}
 	err := c.ShouldBindQuery(params)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return nil, bindingError("query", err)
 	}
-	return b.srv.ListBanners(c.Request.Context(), converters.ConstructBannerListOptions(params))
+	options := converters.ConstructBannerListOptions(params)
+	if params.Cursor != nil {
+		if options.After, err = models.DecodeCursor(*params.Cursor); err != nil {
+			return nil, e.NewValidationError(e.FieldViolation{Field: "query.cursor", Message: "is invalid"})
+		}
+	}
+	return b.srv.ListBanners(c.Request.Context(), options)
 }
 
 func (b *HandlerBuilder) createBanner(c *gin.Context) (int, error) {
 	req, err := readRequest[api.BannerRequest](c)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %w", e.ErrorInRequestBody, err)
+		return 0, err
 	}
 	return b.srv.CreateBanner(c.Request.Context(), converters.BannerRequestToBanner(req))
 }
@@ -152,28 +243,99 @@ func (b *HandlerBuilder) deleteBanner(c *gin.Context) error {
 	id := &api.IdParams{}
 	err := c.ShouldBindUri(id)
 	if err != nil {
-		return fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return bindingError("path", err)
+	}
+	etag, err := ifMatch(c)
+	if err != nil {
+		return err
 	}
-	return b.srv.DeleteBanner(c.Request.Context(), id.Id)
+	return b.srv.DeleteBanner(c.Request.Context(), id.Id, etag)
+}
+
+func (b *HandlerBuilder) getBanner(c *gin.Context) (*models.BannerExt, error) {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	return b.srv.GetBanner(c.Request.Context(), id.Id)
 }
 
 func (b *HandlerBuilder) userGetBanner(c *gin.Context) (*models.UserBanner, error) {
 	params := &api.UserBannerParams{}
 	err := c.ShouldBindQuery(params)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", e.ErrorInParam, err)
+		return nil, bindingError("query", err)
+	}
+	header := &api.UserBannerHeaderParams{}
+	if err = c.ShouldBindHeader(header); err != nil {
+		return nil, bindingError("header", err)
+	}
+	return b.srv.UserGetBanners(c.Request.Context(), converters.ConstructBannerUserOptions(params, header))
+}
+
+func (b *HandlerBuilder) userGetBanners(c *gin.Context) (map[int]*models.UserBanner, error) {
+	header := &api.AcceptLanguageParams{}
+	if err := c.ShouldBindHeader(header); err != nil {
+		return nil, bindingError("header", err)
+	}
+	req, err := readRequest[api.UserBannersRequest](c)
+	if err != nil {
+		return nil, err
+	}
+	return b.srv.UserGetFeatureBanners(c.Request.Context(), converters.UserBannersRequestToOptions(req, header))
+}
+
+// ifMatch returns If-Match header value, empty one means the change is unconditional
+func ifMatch(c *gin.Context) (string, error) {
+	params := &api.IfMatchParams{}
+	if err := c.ShouldBindHeader(params); err != nil {
+		return "", bindingError("header", err)
+	}
+	return converters.IfMatchParamsToETag(params), nil
+}
+
+// notModified reports whether If-None-Match header lists the etag of the response
+func notModified(c *gin.Context, etag string) bool {
+	params := &api.UserBannerHeaderParams{}
+	if err := c.ShouldBindHeader(params); err != nil {
+		return false
 	}
-	return b.srv.UserGetBanners(c.Request.Context(), converters.ConstructBannerUserOptions(params))
+	return models.MatchETag(converters.IfNoneMatchParamsToETag(params), etag, true)
 }
 
 func readRequest[T any](c *gin.Context) (*T, error) {
 	var request T
 	if err := c.ShouldBind(&request); err != nil {
-		return nil, fmt.Errorf("%w: %w", e.ErrorInRequestBody, err)
+		return nil, bindingError("body", err)
 	}
 	return &request, nil
 }
 
+// readContentPatch reads merge patch or JSON Patch according to the content type
+func readContentPatch(c *gin.Context) (*models.ContentPatch, error) {
+	data, err := c.GetRawData()
+	if err != nil {
+		return nil, bindingError("body", err)
+	}
+	if c.ContentType() == mergePatchType {
+		contentPatch := &models.ContentPatch{}
+		if err = json.Unmarshal(data, &contentPatch.Merge); err != nil {
+			return nil, bindingError("body", err)
+		}
+		return contentPatch, nil
+	}
+	operations, err := patch.Decode(data)
+	var patchErr *patch.Error
+	if errors.As(err, &patchErr) {
+		return nil, e.NewValidationError(e.FieldViolation{Field: fmt.Sprintf("body[%d]", patchErr.Index), Message: patchErr.Reason})
+	}
+	if err != nil {
+		return nil, bindingError("body", err)
+	}
+	return &models.ContentPatch{Operations: operations}, nil
+}
+
 func collectErrors(c *gin.Context, err error) {
 	c.Error(err)
 	c.Abort()
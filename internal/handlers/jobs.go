@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) GetDeleteJob(c *gin.Context) {
+	job, err := b.getDeleteJob(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.DeleteJobToResponse(job))
+}
+
+func (b *HandlerBuilder) getDeleteJob(c *gin.Context) (*models.DeleteJob, error) {
+	id := &api.JobIdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	return b.srv.GetDeleteJob(c.Request.Context(), id.JobId)
+}
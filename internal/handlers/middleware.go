@@ -2,6 +2,8 @@ package handlers
 
 import (
 	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/handlers/converters"
 	"BannerFlow/pkg/api"
 	"errors"
 	"github.com/gin-gonic/gin"
@@ -17,17 +19,37 @@ func (b *HandlerBuilder) errorMiddleware(c *gin.Context) {
 	}
 	b.log(c)
 
+	var validationErr *e.ValidationError
+	status, msg := errorStatus(lastErr)
 	switch {
-	case errors.Is(lastErr, e.ErrorNotFound):
-		c.Status(http.StatusNotFound)
-	case errors.Is(lastErr, e.ErrorNoPermission):
-		c.Status(http.StatusForbidden)
-	case errors.Is(lastErr, e.ErrorAuthenticationFailed):
-		c.Status(http.StatusUnauthorized)
-	case errors.Is(lastErr, e.ErrorBadRequest):
-		sendJSONError(c, http.StatusBadRequest, lastErr.Error())
+	case errors.As(lastErr, &validationErr):
+		c.JSON(http.StatusBadRequest, converters.ValidationErrorToResponse(validationErr))
+	case status == http.StatusNotFound || status == http.StatusForbidden || status == http.StatusUnauthorized:
+		c.Status(status)
 	default:
-		sendJSONError(c, http.StatusInternalServerError, "something went wrong")
+		sendJSONError(c, status, msg)
+	}
+}
+
+// errorStatus maps domain error to the response status and message, internal errors are not disclosed
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, e.ErrorNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, e.ErrorPreconditionFailed), errors.Is(err, e.ErrorStaleConfirmation):
+		return http.StatusPreconditionFailed, err.Error()
+	case errors.Is(err, e.ErrorRolledBack):
+		return http.StatusFailedDependency, err.Error()
+	case errors.Is(err, e.ErrorQuotaExceeded):
+		return http.StatusTooManyRequests, err.Error()
+	case errors.Is(err, e.ErrorNoPermission):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, e.ErrorAuthenticationFailed):
+		return http.StatusUnauthorized, err.Error()
+	case errors.Is(err, e.ErrorBadRequest):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, "something went wrong"
 	}
 }
 
@@ -44,22 +66,30 @@ func (b *HandlerBuilder) authenticate(c *gin.Context) {
 	}
 }
 
+// handleAuthentication accepts the api key instead of the token, the identity of either of them
+// carries the tenant of the request
 func (b *HandlerBuilder) handleAuthentication(c *gin.Context) error {
-	token := &api.TokenParam{}
-	err := c.ShouldBindHeader(&token)
-	if err != nil {
-		return e.ErrorNoToken
+	var id *identity.Identity
+	var err error
+	if key := c.GetHeader(apiKeyName); key != "" {
+		id, err = b.authenticator.AuthenticateKey(key)
+	} else {
+		token := &api.TokenParam{}
+		if err = c.ShouldBindHeader(&token); err != nil {
+			return e.ErrorNoToken
+		}
+		id, err = b.authenticator.Authenticate(token.Token)
 	}
-	err = b.authenticator.Authenticate(token.Token)
 	if err != nil {
 		return e.ErrorAuthenticationFailed
 	}
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
 	return nil
 }
 
+// authorize runs after authenticate, so the identity of the request is known
 func (b *HandlerBuilder) authorize(c *gin.Context) {
-	token := c.GetHeader(tokenName)
-	if !b.authorizer.IsAdmin(token) {
+	if !identity.From(c.Request.Context()).IsAdmin {
 		collectErrors(c, e.ErrorNoPermission)
 	}
 }
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) ListFeatures(c *gin.Context) {
+	b.listRegistry(c, models.Features)
+}
+
+func (b *HandlerBuilder) CreateFeature(c *gin.Context) {
+	b.createRegistryEntry(c, models.Features)
+}
+
+func (b *HandlerBuilder) GetFeature(c *gin.Context) {
+	b.getRegistryEntry(c, models.Features, featureIdParam)
+}
+
+func (b *HandlerBuilder) UpdateFeature(c *gin.Context) {
+	b.updateRegistryEntry(c, models.Features, featureIdParam)
+}
+
+func (b *HandlerBuilder) DeleteFeature(c *gin.Context) {
+	b.deleteRegistryEntry(c, models.Features, featureIdParam)
+}
+
+func (b *HandlerBuilder) ListTags(c *gin.Context) {
+	b.listRegistry(c, models.Tags)
+}
+
+func (b *HandlerBuilder) CreateTag(c *gin.Context) {
+	b.createRegistryEntry(c, models.Tags)
+}
+
+func (b *HandlerBuilder) GetTag(c *gin.Context) {
+	b.getRegistryEntry(c, models.Tags, tagIdParam)
+}
+
+func (b *HandlerBuilder) UpdateTag(c *gin.Context) {
+	b.updateRegistryEntry(c, models.Tags, tagIdParam)
+}
+
+func (b *HandlerBuilder) DeleteTag(c *gin.Context) {
+	b.deleteRegistryEntry(c, models.Tags, tagIdParam)
+}
+
+// idParam binds path id of the registry entry
+type idParam func(c *gin.Context) (int, error)
+
+func featureIdParam(c *gin.Context) (int, error) {
+	params := &api.FeatureIdParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		return 0, bindingError("path", err)
+	}
+	return *params.FeatureId, nil
+}
+
+func tagIdParam(c *gin.Context) (int, error) {
+	params := &api.TagIdParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		return 0, bindingError("path", err)
+	}
+	return *params.TagId, nil
+}
+
+func (b *HandlerBuilder) listRegistry(c *gin.Context, registry models.Registry) {
+	entries, err := b.srv.ListRegistry(c.Request.Context(), registry)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.RegistryEntriesToResponses(entries))
+}
+
+func (b *HandlerBuilder) createRegistryEntry(c *gin.Context, registry models.Registry) {
+	req, err := readRequest[api.RegistryRequest](c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	id, err := b.srv.CreateRegistryEntry(c.Request.Context(), registry, converters.RegistryRequestToEntry(req))
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, converters.ConstructRegistryIdResponse(id))
+}
+
+func (b *HandlerBuilder) getRegistryEntry(c *gin.Context, registry models.Registry, param idParam) {
+	id, err := param(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	entry, err := b.srv.GetRegistryEntry(c.Request.Context(), registry, id)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.RegistryEntryToResponse(entry))
+}
+
+func (b *HandlerBuilder) updateRegistryEntry(c *gin.Context, registry models.Registry, param idParam) {
+	id, err := param(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	req, err := readRequest[api.RegistryRequest](c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	err = b.srv.UpdateRegistryEntry(c.Request.Context(), registry, id, converters.RegistryRequestToEntry(req))
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (b *HandlerBuilder) deleteRegistryEntry(c *gin.Context, registry models.Registry, param idParam) {
+	id, err := param(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	err = b.srv.DeleteRegistryEntry(c.Request.Context(), registry, id)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	if registry == models.Tags {
+		// tags are removed only without banners, nothing is scheduled
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) SubmitBanner(c *gin.Context) {
+	b.reviewBanner(c, models.ActionSubmit)
+}
+
+func (b *HandlerBuilder) ApproveBanner(c *gin.Context) {
+	b.reviewBanner(c, models.ActionApprove)
+}
+
+func (b *HandlerBuilder) RejectBanner(c *gin.Context) {
+	b.reviewBanner(c, models.ActionReject)
+}
+
+func (b *HandlerBuilder) PublishBanner(c *gin.Context) {
+	b.reviewBanner(c, models.ActionPublish)
+}
+
+func (b *HandlerBuilder) ArchiveBanner(c *gin.Context) {
+	b.reviewBanner(c, models.ActionArchive)
+}
+
+func (b *HandlerBuilder) GetBannerRevision(c *gin.Context) {
+	revision, err := b.getBannerRevision(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.PendingRevisionToResponse(*revision))
+}
+
+func (b *HandlerBuilder) ListReviews(c *gin.Context) {
+	revisions, err := b.listReviews(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.PendingRevisionsToResponse(revisions))
+}
+
+func (b *HandlerBuilder) reviewBanner(c *gin.Context, action models.ReviewAction) {
+	err := b.decideReview(c, action)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) decideReview(c *gin.Context, action models.ReviewAction) error {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	decision := &models.ReviewDecision{Action: action}
+	if action == models.ActionReject {
+		req, err := readRequest[api.ReviewRejectRequest](c)
+		if err != nil {
+			return err
+		}
+		decision.Comment = *req.Comment
+	}
+	return b.srv.ReviewBanner(c.Request.Context(), id.Id, decision)
+}
+
+func (b *HandlerBuilder) getBannerRevision(c *gin.Context) (*models.PendingRevision, error) {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	return b.srv.GetBannerRevision(c.Request.Context(), id.Id)
+}
+
+func (b *HandlerBuilder) listReviews(c *gin.Context) ([]models.PendingRevision, error) {
+	params := &api.ListReviewsParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	return b.srv.ListReviews(c.Request.Context(), converters.ListReviewsParamsToOptions(params))
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) ListFeatureSchemas(c *gin.Context) {
+	schemas, err := b.srv.ListFeatureSchemas(c.Request.Context())
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.FeatureSchemasToResponses(schemas))
+}
+
+func (b *HandlerBuilder) GetFeatureSchema(c *gin.Context) {
+	schema, err := b.getFeatureSchema(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.FeatureSchemaToResponse(schema))
+}
+
+func (b *HandlerBuilder) PutFeatureSchema(c *gin.Context) {
+	report, err := b.putFeatureSchema(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.SchemaReportToResponse(report))
+}
+
+func (b *HandlerBuilder) DeleteFeatureSchema(c *gin.Context) {
+	err := b.deleteFeatureSchema(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) getFeatureSchema(c *gin.Context) (*models.FeatureSchema, error) {
+	params := &api.FeatureIdParams{}
+	err := c.ShouldBindUri(params)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	return b.srv.GetFeatureSchema(c.Request.Context(), *params.FeatureId)
+}
+
+func (b *HandlerBuilder) putFeatureSchema(c *gin.Context) (*models.SchemaReport, error) {
+	params := &api.FeatureIdParams{}
+	err := c.ShouldBindUri(params)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	dryRun := &api.DryRunParams{}
+	err = c.ShouldBindQuery(dryRun)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	schema, err := readRequest[api.Content](c)
+	if err != nil {
+		return nil, err
+	}
+	return b.srv.PutFeatureSchema(c.Request.Context(), &models.FeatureSchema{
+		FeatureId: *params.FeatureId,
+		Schema:    *schema,
+	}, dryRun.DryRun != nil && *dryRun.DryRun)
+}
+
+func (b *HandlerBuilder) deleteFeatureSchema(c *gin.Context) error {
+	params := &api.FeatureIdParams{}
+	err := c.ShouldBindUri(params)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	return b.srv.DeleteFeatureSchema(c.Request.Context(), *params.FeatureId)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) SearchBanners(c *gin.Context) {
+	page, err := b.searchBanners(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.SearchPageToResponse(page))
+}
+
+func (b *HandlerBuilder) searchBanners(c *gin.Context) (*models.SearchPage, error) {
+	params := &api.SearchBannersParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	options := converters.SearchBannersParamsToOptions(params)
+	var violations []e.FieldViolation
+	if params.Where != nil {
+		for i, where := range *params.Where {
+			predicate, err := models.ParsePredicate(where)
+			if err != nil {
+				violations = append(violations, e.FieldViolation{Field: fmt.Sprintf("query.where[%d]", i), Message: err.Error()})
+				continue
+			}
+			options.Predicates = append(options.Predicates, predicate)
+		}
+	}
+	if params.Cursor != nil {
+		if options.After, err = models.DecodeCursor(*params.Cursor); err != nil {
+			violations = append(violations, e.FieldViolation{Field: "query.cursor", Message: "is invalid"})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, e.NewValidationError(violations...)
+	}
+	return b.srv.SearchBanners(c.Request.Context(), options)
+}
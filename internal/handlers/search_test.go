@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/pkg/api"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchService returns the first banner of the search and the cursor after it
+type searchService struct {
+	Service
+	options *models.BannerSearchOptions
+}
+
+func (s *searchService) SearchBanners(_ context.Context, options *models.BannerSearchOptions) (*models.SearchPage, error) {
+	s.options = options
+	hit := models.SearchHit{BannerExt: exportBanner(4, 1, []int{2}, true), Rank: 0.5}
+	return &models.SearchPage{Hits: []models.SearchHit{hit}, Next: models.CursorAfterHit(&hit, models.SortByRank), Total: models.ZeroValue}, nil
+}
+
+func TestSearchBanners(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	adminToken, err := sso.GenerateToken(true)
+	require.NoError(t, err)
+	srv := &searchService{}
+	handler := New(srv, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler()
+	serve := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("token", adminToken)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/banner/search?q=black+friday&where=content.title+contains+Sale&where=cta.url+exists&feature_id=1&limit=1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "black friday", srv.options.Query)
+	assert.Equal(t, 1, srv.options.FeatureId)
+	assert.Equal(t, []models.ContentPredicate{
+		{Path: []string{"title"}, Op: models.PredicateContains, Value: "Sale"},
+		{Path: []string{"cta", "url"}, Op: models.PredicateExists},
+	}, srv.options.Predicates)
+	response := &api.BannerSearchResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), response))
+	require.Len(t, *response.Items, 1)
+	assert.Equal(t, 0.5, *(*response.Items)[0].Rank)
+	assert.Equal(t, 4, *(*response.Items)[0].Banner.BannerId)
+	assert.Nil(t, response.Total)
+
+	w = serve("/banner/search?q=sale&cursor=" + *response.NextCursor)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, &models.ListCursor{Sort: models.SortByRank, Rank: 0.5, Id: 4}, srv.options.After)
+
+	srv.options = nil
+	for _, target := range []string{
+		"/banner/search?where=title+like+Sale",
+		"/banner/search?q=sale&cursor=broken",
+		"/banner/search?q=sale&limit=0",
+	} {
+		w = serve(target)
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		assert.Nil(t, srv.options, target)
+	}
+}
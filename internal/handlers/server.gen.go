@@ -0,0 +1,255 @@
+// Code generated by apigen from api.yaml. DO NOT EDIT.
+
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ServerInterface represents all routes described in api.yaml
+type ServerInterface interface {
+	// ListBanners Получение всех баннеров c фильтрацией по фиче и/или тегу
+	// (/banner)
+	ListBanners(c *gin.Context)
+	// CreateBanner Создание нового баннера
+	// (/banner)
+	CreateBanner(c *gin.Context)
+	// DeleteBanners Отложенное удаление баннеров по фиче и тэгам
+	// (/banner/banners)
+	DeleteBanners(c *gin.Context)
+	// GetDeleteJob Получение задачи отложенного удаления с идентификаторами удаленных баннеров
+	// (/banner/banners/jobs/{job_id})
+	GetDeleteJob(c *gin.Context)
+	// BatchBanners Пакетное создание, изменение и удаление баннеров
+	// (/banner/batch)
+	BatchBanners(c *gin.Context)
+	// EvaluateBanner Проверка таргетинга, какой баннер получит пользователь и почему
+	// (/banner/evaluate)
+	EvaluateBanner(c *gin.Context)
+	// ExportBanners Выгрузка баннеров в NDJSON или CSV
+	// (/banner/export)
+	ExportBanners(c *gin.Context)
+	// ImportBanners Загрузка баннеров из NDJSON или CSV
+	// (/banner/import)
+	ImportBanners(c *gin.Context)
+	// GetTenantQuota Получение квоты баннеров тенанта
+	// (/banner/quota)
+	GetTenantQuota(c *gin.Context)
+	// ListReviews Получение изменений баннеров, ожидающих проверки
+	// (/banner/reviews)
+	ListReviews(c *gin.Context)
+	// SearchBanners Поиск баннеров по тексту контента и условиям на его поля
+	// (/banner/search)
+	SearchBanners(c *gin.Context)
+	// ListMissingTranslations Получение баннеров без переводов на локали
+	// (/banner/translations/missing)
+	ListMissingTranslations(c *gin.Context)
+	// ListTrash Удаленные баннеры, которые еще можно восстановить
+	// (/banner/trash)
+	ListTrash(c *gin.Context)
+	// ListBannerVersions Получение предыдущих версий баннера в порядке возрастания версии
+	// (/banner/versions/{id})
+	ListBannerVersions(c *gin.Context)
+	// ActivateBannerVersion Выбор версии баннера
+	// (/banner/versions/{id}/activate)
+	ActivateBannerVersion(c *gin.Context)
+	// GetBanner Получение баннера по идентификатору
+	// (/banner/{id})
+	GetBanner(c *gin.Context)
+	// UpdateBanner Обновление содержимого баннера
+	// (/banner/{id})
+	UpdateBanner(c *gin.Context)
+	// DeleteBanner Удаление баннера по идентификатору
+	// (/banner/{id})
+	DeleteBanner(c *gin.Context)
+	// ApproveBanner Одобрение изменений баннера
+	// (/banner/{id}/approve)
+	ApproveBanner(c *gin.Context)
+	// ArchiveBanner Архивирование опубликованного баннера
+	// (/banner/{id}/archive)
+	ArchiveBanner(c *gin.Context)
+	// DiffBannerVersions Различия между версиями баннера в виде JSON Patch (RFC 6902) и краткого описания
+	// (/banner/{id}/diff)
+	DiffBannerVersions(c *gin.Context)
+	// PublishBanner Публикация одобренных изменений баннера
+	// (/banner/{id}/publish)
+	PublishBanner(c *gin.Context)
+	// RejectBanner Отклонение изменений баннера с комментарием
+	// (/banner/{id}/reject)
+	RejectBanner(c *gin.Context)
+	// RestoreBanner Восстановление удаленного баннера из корзины
+	// (/banner/{id}/restore)
+	RestoreBanner(c *gin.Context)
+	// GetBannerRevision Получение ожидающей проверки версии баннера
+	// (/banner/{id}/revision)
+	GetBannerRevision(c *gin.Context)
+	// SubmitBanner Отправка черновика баннера на проверку
+	// (/banner/{id}/submit)
+	SubmitBanner(c *gin.Context)
+	// GetBannerTargeting Получение таргетинга баннера
+	// (/banner/{id}/targeting)
+	GetBannerTargeting(c *gin.Context)
+	// PutBannerTargeting Создание или замена таргетинга баннера
+	// (/banner/{id}/targeting)
+	PutBannerTargeting(c *gin.Context)
+	// DeleteBannerTargeting Удаление таргетинга баннера, баннер снова подходит всем пользователям
+	// (/banner/{id}/targeting)
+	DeleteBannerTargeting(c *gin.Context)
+	// ListBannerTranslations Получение переводов содержимого баннера в порядке локалей
+	// (/banner/{id}/translations)
+	ListBannerTranslations(c *gin.Context)
+	// PutBannerTranslation Создание или замена перевода содержимого баннера
+	// (/banner/{id}/translations/{locale})
+	PutBannerTranslation(c *gin.Context)
+	// DeleteBannerTranslation Удаление перевода содержимого баннера
+	// (/banner/{id}/translations/{locale})
+	DeleteBannerTranslation(c *gin.Context)
+	// ListFeatureSchemas Получение JSON Schema содержимого баннеров для всех фич
+	// (/feature_schemas)
+	ListFeatureSchemas(c *gin.Context)
+	// GetFeatureSchema Получение JSON Schema содержимого баннеров фичи
+	// (/feature_schemas/{feature_id})
+	GetFeatureSchema(c *gin.Context)
+	// PutFeatureSchema Создание или замена JSON Schema фичи с отчетом о проверке существующих баннеров
+	// (/feature_schemas/{feature_id})
+	PutFeatureSchema(c *gin.Context)
+	// DeleteFeatureSchema Удаление JSON Schema фичи
+	// (/feature_schemas/{feature_id})
+	DeleteFeatureSchema(c *gin.Context)
+	// ListFeatures Получение всех фич
+	// (/features)
+	ListFeatures(c *gin.Context)
+	// CreateFeature Регистрация фичи
+	// (/features)
+	CreateFeature(c *gin.Context)
+	// GetFeature Получение фичи
+	// (/features/{feature_id})
+	GetFeature(c *gin.Context)
+	// UpdateFeature Изменение названия, описания и владельца фичи
+	// (/features/{feature_id})
+	UpdateFeature(c *gin.Context)
+	// DeleteFeature Удаление фичи и отложенное удаление его баннеров
+	// (/features/{feature_id})
+	DeleteFeature(c *gin.Context)
+	// GetToken Получение токена пользователя или админа
+	// (/get_token/{admin})
+	GetToken(c *gin.Context)
+	// ListTags Получение всех тэгов
+	// (/tags)
+	ListTags(c *gin.Context)
+	// CreateTag Регистрация тэга
+	// (/tags)
+	CreateTag(c *gin.Context)
+	// GetTag Получение тэга
+	// (/tags/{tag_id})
+	GetTag(c *gin.Context)
+	// UpdateTag Изменение названия, описания и владельца тэга
+	// (/tags/{tag_id})
+	UpdateTag(c *gin.Context)
+	// DeleteTag Удаление тэга, которого нет ни у одного баннера
+	// (/tags/{tag_id})
+	DeleteTag(c *gin.Context)
+	// GetUserBanner Получение баннера для пользователя
+	// (/user_banner)
+	GetUserBanner(c *gin.Context)
+	// StreamUserBanner Подписка на изменения баннера пользователя
+	// (/user_banner/stream)
+	StreamUserBanner(c *gin.Context)
+	// GetUserBanners Получение баннеров пользователя для нескольких фич
+	// (/user_banners)
+	GetUserBanners(c *gin.Context)
+	// ListWebhooks Получение вебхуков
+	// (/webhooks)
+	ListWebhooks(c *gin.Context)
+	// CreateWebhook Регистрация вебхука
+	// (/webhooks)
+	CreateWebhook(c *gin.Context)
+	// ListDeadLetters Получение недоставленных событий всех вебхуков
+	// (/webhooks/dead_letters)
+	ListDeadLetters(c *gin.Context)
+	// GetWebhook Получение вебхука
+	// (/webhooks/{webhook_id})
+	GetWebhook(c *gin.Context)
+	// UpdateWebhook Изменение вебхука
+	// (/webhooks/{webhook_id})
+	UpdateWebhook(c *gin.Context)
+	// DeleteWebhook Удаление вебхука вместе с его доставками
+	// (/webhooks/{webhook_id})
+	DeleteWebhook(c *gin.Context)
+	// ListWebhookDeliveries Получение журнала доставок вебхука
+	// (/webhooks/{webhook_id}/deliveries)
+	ListWebhookDeliveries(c *gin.Context)
+	// RetryWebhookDelivery Повторная отправка недоставленного события
+	// (/webhooks/{webhook_id}/deliveries/{delivery_id}/retry)
+	RetryWebhookDelivery(c *gin.Context)
+}
+
+// RegisterHandlers registers routes from api.yaml.
+// Security maps security scheme names to middlewares which are run before the handler
+func RegisterHandlers(router gin.IRoutes, si ServerInterface, security map[string][]gin.HandlerFunc) {
+	router.Handle(http.MethodGet, "/banner", withMiddlewares(security["AdminToken"], si.ListBanners)...)
+	router.Handle(http.MethodPost, "/banner", withMiddlewares(security["AdminToken"], si.CreateBanner)...)
+	router.Handle(http.MethodDelete, "/banner/banners", withMiddlewares(security["AdminToken"], si.DeleteBanners)...)
+	router.Handle(http.MethodGet, "/banner/banners/jobs/:job_id", withMiddlewares(security["AdminToken"], si.GetDeleteJob)...)
+	router.Handle(http.MethodPost, "/banner/batch", withMiddlewares(security["AdminToken"], si.BatchBanners)...)
+	router.Handle(http.MethodPost, "/banner/evaluate", withMiddlewares(security["AdminToken"], si.EvaluateBanner)...)
+	router.Handle(http.MethodGet, "/banner/export", withMiddlewares(security["AdminToken"], si.ExportBanners)...)
+	router.Handle(http.MethodPost, "/banner/import", withMiddlewares(security["AdminToken"], si.ImportBanners)...)
+	router.Handle(http.MethodGet, "/banner/quota", withMiddlewares(security["AdminToken"], si.GetTenantQuota)...)
+	router.Handle(http.MethodGet, "/banner/reviews", withMiddlewares(security["AdminToken"], si.ListReviews)...)
+	router.Handle(http.MethodGet, "/banner/search", withMiddlewares(security["AdminToken"], si.SearchBanners)...)
+	router.Handle(http.MethodGet, "/banner/translations/missing", withMiddlewares(security["AdminToken"], si.ListMissingTranslations)...)
+	router.Handle(http.MethodGet, "/banner/trash", withMiddlewares(security["AdminToken"], si.ListTrash)...)
+	router.Handle(http.MethodGet, "/banner/versions/:id", withMiddlewares(security["AdminToken"], si.ListBannerVersions)...)
+	router.Handle(http.MethodPut, "/banner/versions/:id/activate", withMiddlewares(security["AdminToken"], si.ActivateBannerVersion)...)
+	router.Handle(http.MethodGet, "/banner/:id", withMiddlewares(security["AdminToken"], si.GetBanner)...)
+	router.Handle(http.MethodPatch, "/banner/:id", withMiddlewares(security["AdminToken"], si.UpdateBanner)...)
+	router.Handle(http.MethodDelete, "/banner/:id", withMiddlewares(security["AdminToken"], si.DeleteBanner)...)
+	router.Handle(http.MethodPost, "/banner/:id/approve", withMiddlewares(security["AdminToken"], si.ApproveBanner)...)
+	router.Handle(http.MethodPost, "/banner/:id/archive", withMiddlewares(security["AdminToken"], si.ArchiveBanner)...)
+	router.Handle(http.MethodGet, "/banner/:id/diff", withMiddlewares(security["AdminToken"], si.DiffBannerVersions)...)
+	router.Handle(http.MethodPost, "/banner/:id/publish", withMiddlewares(security["AdminToken"], si.PublishBanner)...)
+	router.Handle(http.MethodPost, "/banner/:id/reject", withMiddlewares(security["AdminToken"], si.RejectBanner)...)
+	router.Handle(http.MethodPost, "/banner/:id/restore", withMiddlewares(security["AdminToken"], si.RestoreBanner)...)
+	router.Handle(http.MethodGet, "/banner/:id/revision", withMiddlewares(security["AdminToken"], si.GetBannerRevision)...)
+	router.Handle(http.MethodPost, "/banner/:id/submit", withMiddlewares(security["AdminToken"], si.SubmitBanner)...)
+	router.Handle(http.MethodGet, "/banner/:id/targeting", withMiddlewares(security["AdminToken"], si.GetBannerTargeting)...)
+	router.Handle(http.MethodPut, "/banner/:id/targeting", withMiddlewares(security["AdminToken"], si.PutBannerTargeting)...)
+	router.Handle(http.MethodDelete, "/banner/:id/targeting", withMiddlewares(security["AdminToken"], si.DeleteBannerTargeting)...)
+	router.Handle(http.MethodGet, "/banner/:id/translations", withMiddlewares(security["AdminToken"], si.ListBannerTranslations)...)
+	router.Handle(http.MethodPut, "/banner/:id/translations/:locale", withMiddlewares(security["AdminToken"], si.PutBannerTranslation)...)
+	router.Handle(http.MethodDelete, "/banner/:id/translations/:locale", withMiddlewares(security["AdminToken"], si.DeleteBannerTranslation)...)
+	router.Handle(http.MethodGet, "/feature_schemas", withMiddlewares(security["AdminToken"], si.ListFeatureSchemas)...)
+	router.Handle(http.MethodGet, "/feature_schemas/:feature_id", withMiddlewares(security["AdminToken"], si.GetFeatureSchema)...)
+	router.Handle(http.MethodPut, "/feature_schemas/:feature_id", withMiddlewares(security["AdminToken"], si.PutFeatureSchema)...)
+	router.Handle(http.MethodDelete, "/feature_schemas/:feature_id", withMiddlewares(security["AdminToken"], si.DeleteFeatureSchema)...)
+	router.Handle(http.MethodGet, "/features", withMiddlewares(security["AdminToken"], si.ListFeatures)...)
+	router.Handle(http.MethodPost, "/features", withMiddlewares(security["AdminToken"], si.CreateFeature)...)
+	router.Handle(http.MethodGet, "/features/:feature_id", withMiddlewares(security["AdminToken"], si.GetFeature)...)
+	router.Handle(http.MethodPut, "/features/:feature_id", withMiddlewares(security["AdminToken"], si.UpdateFeature)...)
+	router.Handle(http.MethodDelete, "/features/:feature_id", withMiddlewares(security["AdminToken"], si.DeleteFeature)...)
+	router.Handle(http.MethodGet, "/get_token/*admin", withMiddlewares(security[""], si.GetToken)...)
+	router.Handle(http.MethodGet, "/tags", withMiddlewares(security["AdminToken"], si.ListTags)...)
+	router.Handle(http.MethodPost, "/tags", withMiddlewares(security["AdminToken"], si.CreateTag)...)
+	router.Handle(http.MethodGet, "/tags/:tag_id", withMiddlewares(security["AdminToken"], si.GetTag)...)
+	router.Handle(http.MethodPut, "/tags/:tag_id", withMiddlewares(security["AdminToken"], si.UpdateTag)...)
+	router.Handle(http.MethodDelete, "/tags/:tag_id", withMiddlewares(security["AdminToken"], si.DeleteTag)...)
+	router.Handle(http.MethodGet, "/user_banner", withMiddlewares(security["UserToken"], si.GetUserBanner)...)
+	router.Handle(http.MethodGet, "/user_banner/stream", withMiddlewares(security["UserToken"], si.StreamUserBanner)...)
+	router.Handle(http.MethodPost, "/user_banners", withMiddlewares(security["UserToken"], si.GetUserBanners)...)
+	router.Handle(http.MethodGet, "/webhooks", withMiddlewares(security["AdminToken"], si.ListWebhooks)...)
+	router.Handle(http.MethodPost, "/webhooks", withMiddlewares(security["AdminToken"], si.CreateWebhook)...)
+	router.Handle(http.MethodGet, "/webhooks/dead_letters", withMiddlewares(security["AdminToken"], si.ListDeadLetters)...)
+	router.Handle(http.MethodGet, "/webhooks/:webhook_id", withMiddlewares(security["AdminToken"], si.GetWebhook)...)
+	router.Handle(http.MethodPut, "/webhooks/:webhook_id", withMiddlewares(security["AdminToken"], si.UpdateWebhook)...)
+	router.Handle(http.MethodDelete, "/webhooks/:webhook_id", withMiddlewares(security["AdminToken"], si.DeleteWebhook)...)
+	router.Handle(http.MethodGet, "/webhooks/:webhook_id/deliveries", withMiddlewares(security["AdminToken"], si.ListWebhookDeliveries)...)
+	router.Handle(http.MethodPost, "/webhooks/:webhook_id/deliveries/:delivery_id/retry", withMiddlewares(security["AdminToken"], si.RetryWebhookDelivery)...)
+}
+
+func withMiddlewares(middlewares []gin.HandlerFunc, handler gin.HandlerFunc) []gin.HandlerFunc {
+	result := make([]gin.HandlerFunc, 0, len(middlewares)+1)
+	result = append(result, middlewares...)
+	return append(result, handler)
+}
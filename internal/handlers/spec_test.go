@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"BannerFlow/pkg/api"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// routes which are not part of the api itself
+var specExcluded = map[string]bool{
+	"GET /openapi.yaml": true,
+	"GET /docs":         true,
+}
+
+func newTestEngine(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine, ok := New(nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler().(*gin.Engine)
+	require.True(t, ok)
+	return engine
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	spec := struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}{}
+	require.NoError(t, yaml.Unmarshal(api.Spec, &spec))
+
+	var expected []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			expected = append(expected, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var actual []string
+	for _, route := range newTestEngine(t).Routes() {
+		key := route.Method + " " + ginParam.ReplaceAllString(route.Path, "{$1}")
+		if !specExcluded[key] {
+			actual = append(actual, key)
+		}
+	}
+
+	assert.ElementsMatch(t, expected, actual, "routes registered in HandlerBuilder differ from api.yaml")
+}
+
+func TestServeSpec(t *testing.T) {
+	engine := newTestEngine(t)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, api.Spec, w.Body.Bytes())
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.yaml")
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/internal/utils"
+	"BannerFlow/pkg/api"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHeartbeat = 15 * time.Second
+	bannerEvent      = "banner"
+	notFoundEvent    = "not_found"
+)
+
+// SetHeartbeat sets how often comments are sent to idle streams, so proxies don't close them
+func (b *HandlerBuilder) SetHeartbeat(heartbeat time.Duration) {
+	if heartbeat > 0 {
+		b.heartbeat = heartbeat
+	}
+}
+
+// CloseStreams ends every open stream, so the server can shut down without waiting for their clients
+func (b *HandlerBuilder) CloseStreams() {
+	b.closeStreams()
+}
+
+func (b *HandlerBuilder) StreamUserBanner(c *gin.Context) {
+	const op = "handlers.StreamUserBanner"
+	changes, err := b.watchUserBanner(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(b.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			if err = writeChange(c.Writer, &change); err != nil {
+				b.logger.Warn("failed to write banner change", utils.Text(op), utils.Err(err))
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		case <-b.streams.Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+func (b *HandlerBuilder) watchUserBanner(c *gin.Context) (<-chan models.BannerChange, error) {
+	params := &api.UserBannerStreamParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	header := &api.UserBannerStreamHeaderParams{}
+	if err = c.ShouldBindHeader(header); err != nil {
+		return nil, bindingError("header", err)
+	}
+	options := converters.UserBannerStreamToWatchOptions(params, header)
+	if header.LastEventId != nil {
+		if options.LastEventId, err = strconv.ParseInt(*header.LastEventId, 10, 64); err != nil || options.LastEventId < 0 {
+			return nil, e.NewValidationError(e.FieldViolation{Field: "header.Last-Event-ID", Message: "is invalid"})
+		}
+	}
+	return b.srv.WatchUserBanner(c.Request.Context(), options)
+}
+
+// writeChange writes the change as an event with the content of the banner in data
+func writeChange(w io.Writer, change *models.BannerChange) error {
+	if change.Banner == nil {
+		_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata:\n\n", change.EventId, notFoundEvent)
+		return err
+	}
+	data, err := json.Marshal(change.Banner.Content)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", change.EventId, bannerEvent, data)
+	return err
+}
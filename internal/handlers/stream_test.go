@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamService sends the changes and closes the stream unless it is kept open
+type streamService struct {
+	Service
+	changes  []models.BannerChange
+	keepOpen bool
+	options  *models.BannerWatchOptions
+}
+
+func (s *streamService) WatchUserBanner(_ context.Context, options *models.BannerWatchOptions) (<-chan models.BannerChange, error) {
+	s.options = options
+	changes := make(chan models.BannerChange, len(s.changes))
+	for _, change := range s.changes {
+		changes <- change
+	}
+	if !s.keepOpen {
+		close(changes)
+	}
+	return changes, nil
+}
+
+func TestStreamUserBanner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	userToken, err := sso.GenerateToken(false)
+	require.NoError(t, err)
+	srv := &streamService{changes: []models.BannerChange{
+		{EventId: 3, Banner: &models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		{EventId: 5},
+	}}
+	builder := New(srv, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := builder.GetHandler()
+	serve := func(target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("token", userToken)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/user_banner/stream?feature_id=1&tag_id=2&tag_ids=3", map[string]string{"Last-Event-ID": "2", "Accept-Language": "ru"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id: 3\nevent: banner\ndata: {\"title\":\"Sale\"}\n\nid: 5\nevent: not_found\ndata:\n\n", w.Body.String())
+	assert.Equal(t, int64(2), srv.options.LastEventId)
+	assert.Equal(t, models.BannerIdentOptions{FeatureId: 1, TagId: 2}, srv.options.BannerIdentOptions)
+	assert.Equal(t, models.UserAttributes{Locale: "ru", TagIds: []int{2, 3}}, srv.options.Attributes)
+
+	w = serve("/user_banner/stream?feature_id=1&tag_id=2", map[string]string{"Last-Event-ID": "last"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// closed streams end at once, the server doesn't wait for them on shutdown
+	srv.changes, srv.keepOpen = nil, true
+	builder.CloseStreams()
+	w = serve("/user_banner/stream?feature_id=1&tag_id=2", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) GetBannerTargeting(c *gin.Context) {
+	targeting, err := b.getBannerTargeting(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.TargetingToResponse(targeting))
+}
+
+func (b *HandlerBuilder) PutBannerTargeting(c *gin.Context) {
+	err := b.putBannerTargeting(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) DeleteBannerTargeting(c *gin.Context) {
+	err := b.deleteBannerTargeting(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) EvaluateBanner(c *gin.Context) {
+	evaluation, err := b.evaluateBanner(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.EvaluationToResponse(evaluation))
+}
+
+func (b *HandlerBuilder) getBannerTargeting(c *gin.Context) (*models.Targeting, error) {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	return b.srv.GetTargeting(c.Request.Context(), id.Id)
+}
+
+func (b *HandlerBuilder) putBannerTargeting(c *gin.Context) error {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	req, err := readRequest[api.TargetingRequest](c)
+	if err != nil {
+		return err
+	}
+	return b.srv.PutTargeting(c.Request.Context(), converters.TargetingRequestToTargeting(id.Id, req))
+}
+
+func (b *HandlerBuilder) deleteBannerTargeting(c *gin.Context) error {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	return b.srv.DeleteTargeting(c.Request.Context(), id.Id)
+}
+
+func (b *HandlerBuilder) evaluateBanner(c *gin.Context) (*models.Evaluation, error) {
+	req, err := readRequest[api.EvaluateRequest](c)
+	if err != nil {
+		return nil, err
+	}
+	return b.srv.EvaluateBanner(c.Request.Context(), converters.EvaluateRequestToOptions(req))
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"BannerFlow/internal/handlers/converters"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) GetTenantQuota(c *gin.Context) {
+	quota, err := b.srv.GetTenantQuota(c.Request.Context())
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.TenantQuotaToResponse(quota))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/pkg/api"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaService returns the quota of the request tenant
+type quotaService struct {
+	Service
+}
+
+func (s *quotaService) GetTenantQuota(ctx context.Context) (*models.TenantQuota, error) {
+	return &models.TenantQuota{Tenant: identity.From(ctx).Tenant, Banners: 1, MaxBanners: 10}, nil
+}
+
+// tenantBannerService keeps banners of tenants and doesn't find banners of other tenants, as the database does
+type tenantBannerService struct {
+	Service
+	owners map[int]string
+}
+
+func (s *tenantBannerService) find(ctx context.Context, id int) error {
+	if owner, ok := s.owners[id]; !ok || owner != identity.From(ctx).Tenant {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func (s *tenantBannerService) GetBanner(ctx context.Context, id int) (*models.BannerExt, error) {
+	if err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	return &models.BannerExt{BannerId: id}, nil
+}
+
+func (s *tenantBannerService) UpdateBanner(ctx context.Context, id int, _ *models.UpdateBanner, _ string) error {
+	return s.find(ctx, id)
+}
+
+func (s *tenantBannerService) DeleteBanner(ctx context.Context, id int, _ string) error {
+	return s.find(ctx, id)
+}
+
+func TestCrossTenantBanners(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	sso.AddAPIKey("ads-key", identity.Identity{Actor: "ci", IsAdmin: true, Tenant: "ads"})
+	sso.AddAPIKey("promo-key", identity.Identity{Actor: "ci", IsAdmin: true, Tenant: "promo"})
+	handler := New(&tenantBannerService{owners: map[int]string{1: "ads"}}, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler()
+	serve := func(method, target string, header map[string]string) int {
+		req := httptest.NewRequest(method, target, strings.NewReader(`{"is_active": false}`))
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/get_token/admin?tenant=ads", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	token := &api.TokenResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), token))
+
+	for _, method := range []string{http.MethodGet, http.MethodPatch, http.MethodDelete} {
+		assert.Equal(t, http.StatusNotFound, serve(method, "/banner/1", map[string]string{"X-API-Key": "promo-key"}), method)
+		assert.Equal(t, http.StatusNotFound, serve(method, "/banner/1", map[string]string{"token": token.Token}),
+			"%s: tenant of the token is not chosen by its requester", method)
+		assert.Less(t, serve(method, "/banner/1", map[string]string{"X-API-Key": "ads-key"}), http.StatusMultipleChoices, method)
+	}
+}
+
+func TestTenantAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	sso.AddAPIKey("admin-key", identity.Identity{Actor: "ci", IsAdmin: true, Tenant: "ads"})
+	sso.AddAPIKey("user-key", identity.Identity{Actor: "app", Tenant: "ads"})
+	tenantToken, err := sso.IssueToken(&identity.Identity{Actor: "admin", IsAdmin: true, Tenant: "promo"})
+	require.NoError(t, err)
+	handler := New(&quotaService{}, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler()
+	serve := func(header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/banner/quota", nil)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	tenantOf := func(w *httptest.ResponseRecorder) string {
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		response := &api.TenantQuotaResponse{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), response))
+		return *response.Tenant
+	}
+
+	assert.Equal(t, "ads", tenantOf(serve(map[string]string{"X-API-Key": "admin-key"})))
+	assert.Equal(t, "promo", tenantOf(serve(map[string]string{"token": tenantToken})))
+	assert.Equal(t, "ads", tenantOf(serve(map[string]string{"X-API-Key": "admin-key", "token": tenantToken})),
+		"api key is preferred to the token")
+	assert.Equal(t, http.StatusForbidden, serve(map[string]string{"X-API-Key": "user-key"}).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(map[string]string{"X-API-Key": "unknown"}).Code)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/internal/utils"
+	"BannerFlow/pkg/api"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	ndjsonFormat      = "ndjson"
+	csvFormat         = "csv"
+	ndjsonContentType = "application/x-ndjson"
+	csvContentType    = "text/csv"
+	recordLocation    = "record"
+)
+
+// csvHeader is the first row of CSV, tag_ids and content are written as JSON
+var csvHeader = []string{"banner_id", "feature_id", "tag_ids", "content", "is_active"}
+
+func (b *HandlerBuilder) ExportBanners(c *gin.Context) {
+	const op = "handlers.ExportBanners"
+	params := &api.ExportBannersParams{}
+	if err := c.ShouldBindQuery(params); err != nil {
+		collectErrors(c, bindingError("query", err))
+		return
+	}
+	format := ndjsonFormat
+	if params.Format != nil {
+		format = *params.Format
+	}
+	encoder, err := newRecordEncoder(format, c.Writer)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	// headers are sent with the first banner, so errors before it still get their status
+	started := false
+	start := func() {
+		c.Header("Content-Type", encoder.contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="banners.%s"`, format))
+		c.Status(http.StatusOK)
+		started = true
+	}
+	err = b.srv.ExportBanners(c.Request.Context(), converters.ExportBannersParamsToListOptions(params), func(banner *models.BannerExt) error {
+		if !started {
+			start()
+		}
+		return encoder.encode(converters.BannerExtToRecord(*banner))
+	})
+	if err != nil && !started {
+		collectErrors(c, err)
+		return
+	}
+	if !started {
+		start()
+	}
+	if err == nil {
+		err = encoder.flush()
+	}
+	if err != nil {
+		// the response is already sent in part, the client sees it cut off
+		b.logger.Warn("export is interrupted", utils.Text(op), utils.Err(err))
+		c.Abort()
+	}
+}
+
+func (b *HandlerBuilder) ImportBanners(c *gin.Context) {
+	report, err := b.importBanners(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.ImportReportToResponse(report))
+}
+
+func (b *HandlerBuilder) importBanners(c *gin.Context) (*models.ImportReport, error) {
+	params := &api.ImportBannersParams{}
+	if err := c.ShouldBindQuery(params); err != nil {
+		return nil, bindingError("query", err)
+	}
+	var next func() (*models.ImportRecord, error)
+	switch c.ContentType() {
+	case ndjsonContentType:
+		next = ndjsonRecords(c.Request.Body)
+	case csvContentType:
+		next = csvRecords(c.Request.Body)
+	default:
+		return nil, e.NewValidationError(e.FieldViolation{
+			Field:   "body",
+			Message: fmt.Sprintf("unsupported content type %q", c.ContentType()),
+		})
+	}
+	return b.srv.ImportBanners(c.Request.Context(), next, converters.ImportBannersParamsToOptions(params))
+}
+
+// recordEncoder writes banners of the export in the chosen format
+type recordEncoder struct {
+	contentType string
+	encode      func(record api.BannerRecord) error
+	flush       func() error
+}
+
+func newRecordEncoder(format string, w io.Writer) (*recordEncoder, error) {
+	switch format {
+	case ndjsonFormat:
+		// Encode ends every value with a new line
+		encoder := json.NewEncoder(w)
+		return &recordEncoder{
+			contentType: ndjsonContentType,
+			encode: func(record api.BannerRecord) error {
+				return encoder.Encode(record)
+			},
+			flush: func() error { return nil },
+		}, nil
+	case csvFormat:
+		writer := csv.NewWriter(w)
+		headerWritten := false
+		header := func() error {
+			if headerWritten {
+				return nil
+			}
+			headerWritten = true
+			return writer.Write(csvHeader)
+		}
+		return &recordEncoder{
+			contentType: csvContentType,
+			encode: func(record api.BannerRecord) error {
+				if err := header(); err != nil {
+					return err
+				}
+				row, err := recordToCSV(record)
+				if err != nil {
+					return err
+				}
+				return writer.Write(row)
+			},
+			flush: func() error {
+				if err := header(); err != nil {
+					return err
+				}
+				writer.Flush()
+				return writer.Error()
+			},
+		}, nil
+	default:
+		return nil, e.NewValidationError(e.FieldViolation{Field: "query.format", Message: "must be one of ndjson, csv"})
+	}
+}
+
+func recordToCSV(record api.BannerRecord) ([]string, error) {
+	tagIds, err := json.Marshal(record.TagIds)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(record.Content)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprint(*record.BannerId),
+		fmt.Sprint(*record.FeatureId),
+		string(tagIds),
+		string(content),
+		fmt.Sprint(*record.IsActive),
+	}, nil
+}
+
+// ndjsonRecords reads a record from every non-empty line, lines are counted from one
+func ndjsonRecords(r io.Reader) func() (*models.ImportRecord, error) {
+	reader := bufio.NewReader(r)
+	line := 0
+	return func() (*models.ImportRecord, error) {
+		for {
+			data, err := reader.ReadBytes('\n')
+			// the last line may have no new line, io.EOF is returned by the next call then
+			if err != nil && (len(data) == 0 || !errors.Is(err, io.EOF)) {
+				return nil, err
+			}
+			line++
+			if len(bytes.TrimSpace(data)) == 0 {
+				continue
+			}
+			return decodeRecord(line, func(record *api.BannerRecord) error {
+				return json.Unmarshal(data, record)
+			})
+		}
+	}
+}
+
+// csvRecords reads records after the header, cells are JSON values and empty cells are missing fields.
+// Lines are counted from the header
+func csvRecords(r io.Reader) func() (*models.ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+	headerRead := false
+	return func() (*models.ImportRecord, error) {
+		if !headerRead {
+			header, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			if err != nil || !slices.Equal(header, csvHeader) {
+				return nil, e.NewValidationError(e.FieldViolation{
+					Field:   "body",
+					Message: "first line must be " + strings.Join(csvHeader, ","),
+				})
+			}
+			headerRead = true
+		}
+		row, err := reader.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, recordError(parseErr.StartLine, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		return decodeRecord(line, func(record *api.BannerRecord) error {
+			fields := make(map[string]json.RawMessage, len(row))
+			for i, cell := range row {
+				if cell != "" {
+					fields[csvHeader[i]] = json.RawMessage(cell)
+				}
+			}
+			data, err := json.Marshal(fields)
+			if err != nil {
+				return e.NewValidationError(e.FieldViolation{Field: recordLocation, Message: "cells must be JSON values"})
+			}
+			return json.Unmarshal(data, record)
+		})
+	}
+}
+
+// decodeRecord decodes and validates the record the same way as a request body
+func decodeRecord(line int, decode func(record *api.BannerRecord) error) (*models.ImportRecord, error) {
+	record := &api.BannerRecord{}
+	if err := decode(record); err != nil {
+		return nil, recordError(line, err)
+	}
+	if err := binding.Validator.ValidateStruct(record); err != nil {
+		return nil, recordError(line, err)
+	}
+	return converters.BannerRecordToImportRecord(line, record), nil
+}
+
+// recordError rejects the record of the line with violations of err
+func recordError(line int, err error) error {
+	var validationErr *e.ValidationError
+	if !errors.As(err, &validationErr) {
+		errors.As(bindingError(recordLocation, err), &validationErr)
+	}
+	return &models.RecordError{Line: line, Violations: validationErr.Violations}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferService exports banners and imports every record read from the request
+type transferService struct {
+	Service
+	banners  []models.BannerExt
+	exported *models.BannerListOptions
+	imported []models.ImportRecord
+	rejected []models.RecordError
+	options  *models.ImportOptions
+}
+
+func (s *transferService) ExportBanners(_ context.Context, options *models.BannerListOptions, fn func(banner *models.BannerExt) error) error {
+	s.exported = options
+	for i := range s.banners {
+		if err := fn(&s.banners[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *transferService) ImportBanners(_ context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions) (*models.ImportReport, error) {
+	s.options = options
+	s.imported, s.rejected = nil, nil
+	for {
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var recordErr *models.RecordError
+		if errors.As(err, &recordErr) {
+			s.rejected = append(s.rejected, *recordErr)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.imported = append(s.imported, *record)
+	}
+	return &models.ImportReport{Mode: options.Mode, Total: len(s.imported) + len(s.rejected), Created: len(s.imported)}, nil
+}
+
+func exportBanner(id, featureId int, tagIds []int, isActive bool) models.BannerExt {
+	return models.BannerExt{BannerId: id, Banner: models.Banner{
+		BaseBanner: models.BaseBanner{
+			UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale, \"50%\"", "price": 9.5}},
+			FeatureId:  featureId,
+			TagIds:     tagIds,
+		},
+		IsActive: isActive,
+	}}
+}
+
+func TestExportImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	adminToken, err := sso.GenerateToken(true)
+	require.NoError(t, err)
+	srv := &transferService{banners: []models.BannerExt{
+		exportBanner(1, 1, []int{1, 2}, true),
+		exportBanner(2, 3, []int{4}, false),
+	}}
+	handler := New(srv, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil))).GetHandler()
+	serve := func(method, target, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("token", adminToken)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, test := range []struct {
+		format      string
+		contentType string
+		wantLines   []int
+	}{
+		{format: "ndjson", contentType: ndjsonContentType, wantLines: []int{1, 2}},
+		{format: "csv", contentType: csvContentType, wantLines: []int{2, 3}},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			w := serve(http.MethodGet, "/banner/export?feature_id=1&format="+test.format, "", "")
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, test.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, 1, srv.exported.FeatureId)
+
+			w = serve(http.MethodPost, "/banner/import?mode=upsert", test.contentType, w.Body.String())
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, models.ImportUpsert, srv.options.Mode)
+			assert.Empty(t, srv.rejected)
+			require.Len(t, srv.imported, len(srv.banners))
+			for i, record := range srv.imported {
+				assert.Equal(t, test.wantLines[i], record.Line)
+				assert.Equal(t, srv.banners[i].Banner, record.Banner)
+			}
+		})
+	}
+
+	t.Run("empty csv export has header", func(t *testing.T) {
+		banners := srv.banners
+		srv.banners = nil
+		defer func() { srv.banners = banners }()
+		w := serve(http.MethodGet, "/banner/export?format=csv", "", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, strings.Join(csvHeader, ",")+"\n", w.Body.String())
+	})
+
+	t.Run("invalid records", func(t *testing.T) {
+		body := `{"feature_id": 1, "tag_ids": [1], "content": {}, "is_active": true}
+
+{"feature_id": 1, "tag_ids": [], "content": {}, "is_active": true}
+not json
+{"feature_id": "1", "tag_ids": [1], "content": {}, "is_active": true}`
+		w := serve(http.MethodPost, "/banner/import", ndjsonContentType, body)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.ImportFailOnConflict, srv.options.Mode)
+		require.Len(t, srv.imported, 1)
+		var lines []int
+		var fields []string
+		for _, rejected := range srv.rejected {
+			lines = append(lines, rejected.Line)
+			fields = append(fields, rejected.Violations[0].Field)
+		}
+		assert.Equal(t, []int{3, 4, 5}, lines)
+		assert.Equal(t, []string{"record.tag_ids", "record", "record.feature_id"}, fields)
+	})
+
+	t.Run("invalid csv header", func(t *testing.T) {
+		w := serve(http.MethodPost, "/banner/import", csvContentType, "id,feature\n1,2\n")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		w := serve(http.MethodPost, "/banner/import", "application/json", "{}")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
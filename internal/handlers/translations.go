@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) ListBannerTranslations(c *gin.Context) {
+	translations, err := b.listBannerTranslations(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.TranslationsToResponse(translations))
+}
+
+func (b *HandlerBuilder) PutBannerTranslation(c *gin.Context) {
+	err := b.putBannerTranslation(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) DeleteBannerTranslation(c *gin.Context) {
+	err := b.deleteBannerTranslation(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) ListMissingTranslations(c *gin.Context) {
+	missing, err := b.listMissingTranslations(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.MissingTranslationsToResponse(missing))
+}
+
+func (b *HandlerBuilder) listBannerTranslations(c *gin.Context) ([]models.Translation, error) {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return nil, bindingError("path", err)
+	}
+	return b.srv.ListTranslations(c.Request.Context(), id.Id)
+}
+
+func (b *HandlerBuilder) putBannerTranslation(c *gin.Context) error {
+	params := &api.TranslationParams{}
+	err := c.ShouldBindUri(params)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	content, err := readRequest[api.Content](c)
+	if err != nil {
+		return err
+	}
+	return b.srv.PutTranslation(c.Request.Context(), &models.Translation{
+		BannerId: params.Id,
+		Locale:   params.Locale,
+		Content:  *content,
+	})
+}
+
+func (b *HandlerBuilder) deleteBannerTranslation(c *gin.Context) error {
+	params := &api.TranslationParams{}
+	err := c.ShouldBindUri(params)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	return b.srv.DeleteTranslation(c.Request.Context(), params.Id, params.Locale)
+}
+
+func (b *HandlerBuilder) listMissingTranslations(c *gin.Context) ([]models.MissingTranslations, error) {
+	params := &api.MissingTranslationsParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	return b.srv.MissingTranslations(c.Request.Context(), converters.MissingTranslationsParamsToFeatureId(params), *params.Locales)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) ListTrash(c *gin.Context) {
+	page, err := b.listTrash(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.BannerPageToResponse(page))
+}
+
+func (b *HandlerBuilder) RestoreBanner(c *gin.Context) {
+	err := b.restoreBanner(c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) listTrash(c *gin.Context) (*models.BannerPage, error) {
+	params := &api.ListTrashParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		return nil, bindingError("query", err)
+	}
+	options := converters.ListTrashParamsToListOptions(params)
+	if params.Cursor != nil {
+		if options.After, err = models.DecodeCursor(*params.Cursor); err != nil {
+			return nil, e.NewValidationError(e.FieldViolation{Field: "query.cursor", Message: "is invalid"})
+		}
+	}
+	return b.srv.ListBanners(c.Request.Context(), options)
+}
+
+func (b *HandlerBuilder) restoreBanner(c *gin.Context) error {
+	id := &api.IdParams{}
+	err := c.ShouldBindUri(id)
+	if err != nil {
+		return bindingError("path", err)
+	}
+	return b.srv.RestoreBanner(c.Request.Context(), id.Id)
+}
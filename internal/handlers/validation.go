@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/utils"
+	"BannerFlow/internal/validation"
+	"BannerFlow/pkg/api"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	registerTagNames sync.Once
+	ginParam         = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+)
+
+// EnableValidation validates every request against api.yaml, and responses if validateResponses is set.
+// Must be called before GetHandler
+func (b *HandlerBuilder) EnableValidation(validateResponses bool) error {
+	spec, err := validation.LoadSpec(api.Spec)
+	if err != nil {
+		return err
+	}
+	b.spec = spec
+	b.validateResponses = validateResponses
+	return nil
+}
+
+func (b *HandlerBuilder) validate(c *gin.Context) {
+	const op = "handlers.validate"
+	operation, ok := b.spec.Operation(c.Request.Method, ginParam.ReplaceAllString(c.FullPath(), "{$1}"))
+	if !ok {
+		return
+	}
+	params := make(map[string]string, len(c.Params))
+	for _, p := range c.Params {
+		params[p.Key] = p.Value
+	}
+	violations, err := b.spec.ValidateRequest(operation, c.Request, params)
+	if err != nil {
+		collectErrors(c, fmt.Errorf("%w: failed to read request: %w", e.ErrorInternal, err))
+		return
+	}
+	if len(violations) > 0 {
+		collectErrors(c, e.NewValidationError(violations...))
+		return
+	}
+	if !b.validateResponses {
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+	if len(c.Errors) > 0 {
+		return
+	}
+	violations = b.spec.ValidateResponse(operation, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+	if len(violations) > 0 {
+		b.logger.Warn("response does not match api.yaml", utils.Text(op), utils.Err(e.NewValidationError(violations...)))
+	}
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.recorded() {
+		r.body.Write(data)
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	if r.recorded() {
+		r.body.WriteString(s)
+	}
+	return r.ResponseWriter.WriteString(s)
+}
+
+// recorded is false for streamed responses, only JSON documents are validated
+func (r *responseRecorder) recorded() bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header().Get("Content-Type"))
+	return mediaType == "" || validation.IsJSON(mediaType)
+}
+
+// bindingError converts binding errors into field violations of the given location (path, query or body)
+func bindingError(location string, err error) error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		violations := make([]e.FieldViolation, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			field := fe.Namespace()
+			if i := strings.Index(field, "."); i >= 0 {
+				field = field[i+1:]
+			}
+			violations = append(violations, e.FieldViolation{Field: location + "." + field, Message: ruleMessage(fe)})
+		}
+		return e.NewValidationError(violations...)
+	case errors.As(err, &typeErr):
+		return e.NewValidationError(e.FieldViolation{Field: location + "." + typeErr.Field, Message: "must be " + typeErr.Type.String()})
+	case errors.As(err, &syntaxErr):
+		return e.NewValidationError(e.FieldViolation{Field: location, Message: "invalid json: " + syntaxErr.Error()})
+	case errors.Is(err, io.EOF):
+		return e.NewValidationError(e.FieldViolation{Field: location, Message: "is required"})
+	default:
+		return e.NewValidationError(e.FieldViolation{Field: location, Message: err.Error()})
+	}
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	isCollection := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is missing", fe.Param())
+	case "gte":
+		if isCollection {
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	default:
+		return fmt.Sprintf("failed on %s rule", fe.Tag())
+	}
+}
+
+// useTagNames makes validator report fields by their json, form, uri or header names
+func useTagNames() {
+	registerTagNames.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form", "uri", "header"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	})
+}
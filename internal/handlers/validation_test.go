@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"BannerFlow/internal/auth"
+	"BannerFlow/pkg/api"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	adminToken, err := sso.GenerateToken(true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		validate    bool
+		method      string
+		target      string
+		token       string
+		contentType string
+		body        string
+		wantStatus  int
+		wantFields  []string
+	}{
+		{
+			name:       "spec body",
+			validate:   true,
+			method:     http.MethodPost,
+			target:     "/banner",
+			token:      adminToken,
+			body:       `{"tag_ids": [-1], "feature_id": "1", "content": {}}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"body.is_active", "body.feature_id", "body.tag_ids[0]"},
+		},
+		{
+			name:       "spec query",
+			validate:   true,
+			method:     http.MethodGet,
+			target:     "/user_banner?tag_id=abc",
+			token:      adminToken,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"query.tag_id", "query.feature_id"},
+		},
+		{
+			name:       "spec path",
+			validate:   true,
+			method:     http.MethodDelete,
+			target:     "/banner/0",
+			token:      adminToken,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"path.id"},
+		},
+		{
+			name:       "authentication goes first",
+			validate:   true,
+			method:     http.MethodGet,
+			target:     "/user_banner",
+			token:      "invalid",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "binding tag values",
+			method:     http.MethodPost,
+			target:     "/banner",
+			token:      adminToken,
+			body:       `{"tag_ids": [1, -1], "feature_id": 1, "content": {}, "is_active": true}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"body.tag_ids[1]"},
+		},
+		{
+			name:       "binding json types",
+			method:     http.MethodPatch,
+			target:     "/banner/1",
+			token:      adminToken,
+			body:       `{"content": 1}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"body.content"},
+		},
+		{
+			name:        "spec json patch",
+			validate:    true,
+			method:      http.MethodPatch,
+			target:      "/banner/1",
+			token:       adminToken,
+			contentType: jsonPatchType,
+			body:        `[{"op": "delete", "path": "/title"}]`,
+			wantStatus:  http.StatusBadRequest,
+			wantFields:  []string{"body[0].op"},
+		},
+		{
+			name:        "json patch members",
+			method:      http.MethodPatch,
+			target:      "/banner/1",
+			token:       adminToken,
+			contentType: jsonPatchType,
+			body:        `[{"op": "remove", "path": "/text"}, {"op": "replace", "path": "/title"}]`,
+			wantStatus:  http.StatusBadRequest,
+			wantFields:  []string{"body[1]"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder := New(nil, sso, sso, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if test.validate {
+				require.NoError(t, builder.EnableValidation(true))
+			}
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			contentType := test.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("token", test.token)
+			w := httptest.NewRecorder()
+			builder.GetHandler().ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			if test.wantFields == nil {
+				return
+			}
+			resp := api.BannerErrorResponse{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Details)
+			var fields []string
+			for _, detail := range *resp.Details {
+				fields = append(fields, *detail.Field)
+			}
+			assert.ElementsMatch(t, test.wantFields, fields)
+		})
+	}
+}
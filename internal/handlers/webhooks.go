@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"BannerFlow/internal/handlers/converters"
+	"BannerFlow/pkg/api"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (b *HandlerBuilder) ListWebhooks(c *gin.Context) {
+	webhooks, err := b.srv.ListWebhooks(c.Request.Context())
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.WebhooksToResponses(webhooks))
+}
+
+func (b *HandlerBuilder) CreateWebhook(c *gin.Context) {
+	req, err := readRequest[api.WebhookRequest](c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	id, err := b.srv.CreateWebhook(c.Request.Context(), converters.WebhookRequestToWebhook(0, req))
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, converters.ConstructWebhookIdResponse(id))
+}
+
+func (b *HandlerBuilder) GetWebhook(c *gin.Context) {
+	params := &api.WebhookIdParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		collectErrors(c, bindingError("path", err))
+		return
+	}
+	webhook, err := b.srv.GetWebhook(c.Request.Context(), params.WebhookId)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.WebhookToResponse(webhook))
+}
+
+func (b *HandlerBuilder) UpdateWebhook(c *gin.Context) {
+	params := &api.WebhookIdParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		collectErrors(c, bindingError("path", err))
+		return
+	}
+	req, err := readRequest[api.WebhookRequest](c)
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	err = b.srv.UpdateWebhook(c.Request.Context(), converters.WebhookRequestToWebhook(params.WebhookId, req))
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (b *HandlerBuilder) DeleteWebhook(c *gin.Context) {
+	params := &api.WebhookIdParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		collectErrors(c, bindingError("path", err))
+		return
+	}
+	if err := b.srv.DeleteWebhook(c.Request.Context(), params.WebhookId); err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (b *HandlerBuilder) ListWebhookDeliveries(c *gin.Context) {
+	params := &api.WebhookIdParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		collectErrors(c, bindingError("path", err))
+		return
+	}
+	query := &api.DeliveriesParams{}
+	if err := c.ShouldBindQuery(query); err != nil {
+		collectErrors(c, bindingError("query", err))
+		return
+	}
+	deliveries, err := b.srv.ListDeliveries(c.Request.Context(), converters.DeliveriesParamsToOptions(params.WebhookId, query))
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.WebhookDeliveriesToResponses(deliveries))
+}
+
+func (b *HandlerBuilder) ListDeadLetters(c *gin.Context) {
+	query := &api.DeadLettersParams{}
+	if err := c.ShouldBindQuery(query); err != nil {
+		collectErrors(c, bindingError("query", err))
+		return
+	}
+	deliveries, err := b.srv.ListDeliveries(c.Request.Context(), converters.DeadLettersParamsToOptions(query))
+	if err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, converters.WebhookDeliveriesToResponses(deliveries))
+}
+
+func (b *HandlerBuilder) RetryWebhookDelivery(c *gin.Context) {
+	params := &api.DeliveryParams{}
+	if err := c.ShouldBindUri(params); err != nil {
+		collectErrors(c, bindingError("path", err))
+		return
+	}
+	if err := b.srv.RetryDelivery(c.Request.Context(), params.WebhookId, int64(params.DeliveryId)); err != nil {
+		collectErrors(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,282 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Error describes the operation which failed, Index is its position in the patch
+type Error struct {
+	Index     int
+	Operation Operation
+	Reason    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("operation %d (%s %s): %s", e.Index, e.Operation.Op, e.Operation.Path, e.Reason)
+}
+
+var (
+	errNotFound     = errors.New("path does not exist")
+	errBadIndex     = errors.New("invalid array index")
+	errNotContainer = errors.New("parent is not an object or array")
+)
+
+// Decode parses JSON Patch document checking that every operation has its required members
+func Decode(data []byte) ([]Operation, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	ops := make([]Operation, 0, len(raw))
+	for i, member := range raw {
+		op := Operation{}
+		if err := decodeMember(member, "op", &op.Op); err != nil {
+			return nil, &Error{Index: i, Operation: op, Reason: err.Error()}
+		}
+		if err := decodeMember(member, "path", &op.Path); err != nil {
+			return nil, &Error{Index: i, Operation: op, Reason: err.Error()}
+		}
+		switch op.Op {
+		case OpAdd, OpReplace, OpTest:
+			if err := decodeMember(member, "value", &op.Value); err != nil {
+				return nil, &Error{Index: i, Operation: op, Reason: err.Error()}
+			}
+		case OpMove, OpCopy:
+			if err := decodeMember(member, "from", &op.From); err != nil {
+				return nil, &Error{Index: i, Operation: op, Reason: err.Error()}
+			}
+		case OpRemove:
+		default:
+			return nil, &Error{Index: i, Operation: op, Reason: "unknown operation"}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func decodeMember(member map[string]json.RawMessage, name string, dst any) error {
+	raw, ok := member[name]
+	if !ok {
+		return fmt.Errorf("%s is required", name)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if err := decoder.Decode(dst); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return nil
+}
+
+// Apply applies operations to the copy of doc. Patch is atomic: doc is never modified
+func Apply(doc any, ops []Operation) (any, error) {
+	result := deepCopy(doc)
+	for i, op := range ops {
+		var err error
+		result, err = apply(result, op)
+		if err != nil {
+			return nil, &Error{Index: i, Operation: op, Reason: err.Error()}
+		}
+	}
+	return result, nil
+}
+
+func apply(doc any, op Operation) (any, error) {
+	tokens := Split(op.Path)
+	switch op.Op {
+	case OpAdd:
+		return modify(doc, tokens, addLeaf(deepCopy(op.Value)))
+	case OpRemove:
+		return modify(doc, tokens, removeLeaf)
+	case OpReplace:
+		return modify(doc, tokens, replaceLeaf(deepCopy(op.Value)))
+	case OpTest:
+		value, err := get(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, op.Value) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	case OpMove, OpCopy:
+		from := Split(op.From)
+		if op.Op == OpMove && op.Path != op.From && strings.HasPrefix(op.Path+"/", op.From+"/") {
+			return nil, errors.New("cannot move value into itself")
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		if op.Op == OpMove {
+			if doc, err = modify(doc, from, removeLeaf); err != nil {
+				return nil, err
+			}
+		}
+		return modify(doc, tokens, addLeaf(deepCopy(value)))
+	}
+	return nil, errors.New("unknown operation")
+}
+
+type leaf func(parent any, token string) (any, error)
+
+// modify calls leaf with the parent of the last token and stores returned parent back
+func modify(doc any, tokens []string, fn leaf) (any, error) {
+	if len(tokens) == 0 {
+		// whole document is the target, wrap it to reuse object leaf
+		root, err := fn(map[string]any{"": doc}, "")
+		if err != nil {
+			return nil, err
+		}
+		return root.(map[string]any)[""], nil
+	}
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+	switch container := doc.(type) {
+	case map[string]any:
+		child, ok := container[tokens[0]]
+		if !ok {
+			return nil, errNotFound
+		}
+		updated, err := modify(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[tokens[0]] = updated
+		return container, nil
+	case []any:
+		i, err := index(tokens[0], len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := modify(container[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[i] = updated
+		return container, nil
+	}
+	return nil, errNotFound
+}
+
+func addLeaf(value any) leaf {
+	return func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			container[token] = value
+			return container, nil
+		case []any:
+			if token == "-" {
+				return append(container, value), nil
+			}
+			i, err := index(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		}
+		return nil, errNotContainer
+	}
+}
+
+func removeLeaf(parent any, token string) (any, error) {
+	switch container := parent.(type) {
+	case map[string]any:
+		if _, ok := container[token]; !ok {
+			return nil, errNotFound
+		}
+		delete(container, token)
+		return container, nil
+	case []any:
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		return append(container[:i], container[i+1:]...), nil
+	}
+	return nil, errNotContainer
+}
+
+func replaceLeaf(value any) leaf {
+	return func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if _, ok := container[token]; !ok {
+				return nil, errNotFound
+			}
+			container[token] = value
+			return container, nil
+		case []any:
+			i, err := index(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			container[i] = value
+			return container, nil
+		}
+		return nil, errNotContainer
+	}
+}
+
+func get(doc any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch container := doc.(type) {
+		case map[string]any:
+			value, ok := container[token]
+			if !ok {
+				return nil, errNotFound
+			}
+			doc = value
+		case []any:
+			i, err := index(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, errNotFound
+		}
+	}
+	return doc, nil
+}
+
+// index parses array index which must not exceed max
+func index(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, errBadIndex
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, errBadIndex
+	}
+	if i > max {
+		return 0, errNotFound
+	}
+	return i, nil
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = deepCopy(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = deepCopy(item)
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,23 @@
+package patch
+
+// Merge applies RFC 7396 JSON Merge Patch to the copy of target
+func Merge(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return deepCopy(patch)
+	}
+	targetObj, ok := target.(map[string]any)
+	if ok {
+		targetObj = deepCopy(targetObj).(map[string]any)
+	} else {
+		targetObj = make(map[string]any, len(patchObj))
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = Merge(targetObj[key], value)
+	}
+	return targetObj
+}
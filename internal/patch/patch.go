@@ -0,0 +1,115 @@
+package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON writes value of add, replace and test operations even when it is null, a null value is still
+// the value of them, and never writes it for remove, move and copy
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	switch o.Op {
+	case OpAdd, OpReplace, OpTest:
+		return json.Marshal(operation(o))
+	}
+	return json.Marshal(struct {
+		operation
+		Value any `json:"value,omitempty"`
+	}{operation: operation(o)})
+}
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Diff returns operations transforming from into to. Objects are compared recursively,
+// other values including arrays are replaced as a whole
+func Diff(from, to any) []Operation {
+	var ops []Operation
+	diff("", from, to, &ops)
+	return ops
+}
+
+func diff(path string, from, to any, ops *[]Operation) {
+	fromObj, fromOk := from.(map[string]any)
+	toObj, toOk := to.(map[string]any)
+	if !fromOk || !toOk {
+		if !reflect.DeepEqual(from, to) {
+			*ops = append(*ops, Operation{Op: OpReplace, Path: path, Value: to})
+		}
+		return
+	}
+	for _, key := range sortedKeys(fromObj) {
+		if _, ok := toObj[key]; !ok {
+			*ops = append(*ops, Operation{Op: OpRemove, Path: Join(path, key)})
+		}
+	}
+	for _, key := range sortedKeys(toObj) {
+		fromValue, ok := fromObj[key]
+		if !ok {
+			*ops = append(*ops, Operation{Op: OpAdd, Path: Join(path, key), Value: toObj[key]})
+			continue
+		}
+		diff(Join(path, key), fromValue, toObj[key], ops)
+	}
+}
+
+// Join appends escaped reference token to JSON Pointer
+func Join(pointer, token string) string {
+	return pointer + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// Split returns unescaped reference tokens of JSON Pointer
+func Split(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for i, token := range tokens {
+		tokens[i] = unescape.Replace(token)
+	}
+	return tokens
+}
+
+// Field converts JSON Pointer to the dotted form used in validation errors, e.g. content.items[0]
+func Field(pointer string) string {
+	var b strings.Builder
+	for _, token := range Split(pointer) {
+		if _, err := strconv.Atoi(token); err == nil && b.Len() > 0 {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
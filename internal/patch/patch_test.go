@@ -0,0 +1,156 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, raw string) any {
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(raw), &value))
+	return value
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []Operation
+	}{
+		{name: "equal", from: `{"a": {"b": [1, 2]}}`, to: `{"a": {"b": [1, 2]}}`, want: nil},
+		{
+			name: "nested",
+			from: `{"title": "old", "removed": 1, "meta": {"color": "red", "size": 1}}`,
+			to:   `{"title": "new", "meta": {"color": "red", "size": 2}, "url": "u"}`,
+			want: []Operation{
+				{Op: OpRemove, Path: "/removed"},
+				{Op: OpReplace, Path: "/meta/size", Value: float64(2)},
+				{Op: OpReplace, Path: "/title", Value: "new"},
+				{Op: OpAdd, Path: "/url", Value: "u"},
+			},
+		},
+		{name: "arrays are replaced", from: `{"a": [1, 2]}`, to: `{"a": [2]}`, want: []Operation{{Op: OpReplace, Path: "/a", Value: []any{float64(2)}}}},
+		{name: "escaped keys", from: `{}`, to: `{"a/b~c": true}`, want: []Operation{{Op: OpAdd, Path: "/a~1b~0c", Value: true}}},
+		{name: "type change", from: `{"a": {"b": 1}}`, to: `{"a": "b"}`, want: []Operation{{Op: OpReplace, Path: "/a", Value: "b"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, Diff(decode(t, test.from), decode(t, test.to)))
+		})
+	}
+}
+
+func TestDiffNullValue(t *testing.T) {
+	ops := Diff(decode(t, `{"a": 1, "b": null}`), decode(t, `{"a": null, "c": null}`))
+	raw, err := json.Marshal(ops)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "remove", "path": "/b"},
+		{"op": "replace", "path": "/a", "value": null},
+		{"op": "add", "path": "/c", "value": null}
+	]`, string(raw), "null values are kept and remove has no value")
+
+	decoded, err := Decode(raw)
+	require.NoError(t, err, "marshaled patch is a valid one")
+	result, err := Apply(decode(t, `{"a": 1, "b": null}`), decoded)
+	require.NoError(t, err)
+	assert.Equal(t, decode(t, `{"a": null, "c": null}`), result)
+}
+
+func TestMarshalOperation(t *testing.T) {
+	for _, test := range []struct {
+		op   Operation
+		want string
+	}{
+		{op: Operation{Op: OpTest, Path: "/a"}, want: `{"op": "test", "path": "/a", "value": null}`},
+		{op: Operation{Op: OpMove, From: "/a", Path: "/b", Value: 1}, want: `{"op": "move", "from": "/a", "path": "/b"}`},
+		{op: Operation{Op: OpCopy, From: "/a", Path: "/b"}, want: `{"op": "copy", "from": "/a", "path": "/b"}`},
+	} {
+		raw, err := json.Marshal(test.op)
+		require.NoError(t, err)
+		assert.JSONEq(t, test.want, string(raw), test.op.Op)
+	}
+}
+
+func TestPointer(t *testing.T) {
+	assert.Equal(t, []string{"a/b", "c~d", "0"}, Split(Join(Join(Join("", "a/b"), "c~d"), "0")))
+	assert.Nil(t, Split(""))
+	assert.Equal(t, "items[0].title", Field("/items/0/title"))
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		patch     string
+		want      string
+		wantIndex int
+	}{
+		{name: "add member", doc: `{"a": 1}`, patch: `[{"op": "add", "path": "/b", "value": {"c": [1]}}]`, want: `{"a": 1, "b": {"c": [1]}}`},
+		{name: "insert and append", doc: `{"a": [1, 3]}`, patch: `[{"op": "add", "path": "/a/1", "value": 2}, {"op": "add", "path": "/a/-", "value": 4}]`, want: `{"a": [1, 2, 3, 4]}`},
+		{name: "remove and replace", doc: `{"a": [1, 2], "b": "x"}`, patch: `[{"op": "remove", "path": "/a/0"}, {"op": "replace", "path": "/b", "value": null}]`, want: `{"a": [2], "b": null}`},
+		{name: "move and copy", doc: `{"a": {"b": 1}, "c": {}}`, patch: `[{"op": "move", "from": "/a/b", "path": "/c/d"}, {"op": "copy", "from": "/c", "path": "/e"}]`, want: `{"a": {}, "c": {"d": 1}, "e": {"d": 1}}`},
+		{name: "test", doc: `{"a": {"b": [1, "x"]}}`, patch: `[{"op": "test", "path": "/a", "value": {"b": [1, "x"]}}]`, want: `{"a": {"b": [1, "x"]}}`},
+		{name: "failed test", doc: `{"a": 1}`, patch: `[{"op": "remove", "path": "/a"}, {"op": "test", "path": "/a", "value": 1}]`, wantIndex: 1},
+		{name: "missing parent", doc: `{}`, patch: `[{"op": "add", "path": "/a/b", "value": 1}]`, wantIndex: 0},
+		{name: "replace missing", doc: `{"a": 1}`, patch: `[{"op": "add", "path": "/b", "value": 1}, {"op": "replace", "path": "/c", "value": 1}]`, wantIndex: 1},
+		{name: "index out of range", doc: `{"a": [1]}`, patch: `[{"op": "add", "path": "/a/2", "value": 1}]`, wantIndex: 0},
+		{name: "leading zero index", doc: `{"a": [1, 2]}`, patch: `[{"op": "remove", "path": "/a/01"}]`, wantIndex: 0},
+		{name: "move into itself", doc: `{"a": {"b": {}}}`, patch: `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`, wantIndex: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := decode(t, test.doc)
+			ops, err := Decode([]byte(test.patch))
+			require.NoError(t, err)
+			result, err := Apply(doc, ops)
+			assert.Equal(t, decode(t, test.doc), doc, "document must not be modified")
+			if test.want == "" {
+				var patchErr *Error
+				require.ErrorAs(t, err, &patchErr)
+				assert.Equal(t, test.wantIndex, patchErr.Index)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, decode(t, test.want), result)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	_, err := Decode([]byte(`[{"op": "remove", "path": "/a"}, {"op": "add", "path": "/a"}]`))
+	var patchErr *Error
+	require.ErrorAs(t, err, &patchErr)
+	assert.Equal(t, 1, patchErr.Index)
+
+	_, err = Decode([]byte(`[{"op": "delete", "path": "/a"}]`))
+	require.ErrorAs(t, err, &patchErr)
+
+	ops, err := Decode([]byte(`[{"op": "add", "path": "/a", "value": null}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Operation{{Op: OpAdd, Path: "/a"}}, ops)
+}
+
+func TestMerge(t *testing.T) {
+	// examples from RFC 7396 appendix A
+	tests := []struct{ target, patch, want string }{
+		{`{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{`{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{`{"a": "b"}`, `{"a": null}`, `{}`},
+		{`{"a": ["b"]}`, `{"a": "c"}`, `{"a": "c"}`},
+		{`{"a": {"b": "c"}}`, `{"a": {"b": "d", "c": null}}`, `{"a": {"b": "d"}}`},
+		{`{"a": [{"b": "c"}]}`, `{"a": [1]}`, `{"a": [1]}`},
+		{`{"e": null}`, `{"a": 1}`, `{"e": null, "a": 1}`},
+		{`[1, 2]`, `{"a": "b", "c": null}`, `{"a": "b"}`},
+		{`{}`, `{"a": {"bb": {"ccc": null}}}`, `{"a": {"bb": {}}}`},
+	}
+	for _, test := range tests {
+		target := decode(t, test.target)
+		assert.Equal(t, decode(t, test.want), Merge(target, decode(t, test.patch)), test.patch)
+		assert.Equal(t, decode(t, test.target), target, "target must not be modified")
+	}
+}
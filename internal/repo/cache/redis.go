@@ -2,17 +2,42 @@ package cache
 
 import (
 	"BannerFlow/internal/config"
+	"BannerFlow/internal/domain/identity"
 	"BannerFlow/internal/domain/models"
 	"context"
 	"errors"
 	"github.com/go-redis/cache/v9"
 	"github.com/redis/go-redis/v9"
+	"sync"
 	"time"
 )
 
+// RedisCache keeps banners of every feature and tag in a redis hash by locale. The hash expires ttl after
+// its first banner is cached, so banners of all locales are read again at once
 type RedisCache struct {
-	redisCache *cache.Cache
-	ttl        time.Duration
+	rdb         *redis.Client
+	local       cache.LocalCache
+	ttl         time.Duration
+	generations *generations
+}
+
+// generations counts invalidations of features and tags. Local entries are keyed by the generation,
+// so entries of every locale become unreachable at once and are evicted later
+type generations struct {
+	mu     sync.RWMutex
+	counts map[string]uint64
+}
+
+func (g *generations) get(key string) uint64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.counts[key]
+}
+
+func (g *generations) next(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.counts[key]++
 }
 
 // NewRedisClient new redis connection
@@ -29,71 +54,138 @@ func NewRedisClient(ctx context.Context, cfg *config.RedisConfig) (*redis.Client
 }
 
 func New(rdb *redis.Client, cfg *config.CacheConfig) *RedisCache {
-	redisCache := cache.New(&cache.Options{
-		Redis:      rdb,
-		LocalCache: cache.NewTinyLFU(cfg.LocalSize, cfg.TTL),
-	})
 	return &RedisCache{
-		redisCache: redisCache,
-		ttl:        cfg.TTL,
+		rdb:         rdb,
+		local:       cache.NewTinyLFU(cfg.LocalSize, cfg.TTL),
+		ttl:         cfg.TTL,
+		generations: &generations{counts: make(map[string]uint64)},
 	}
 }
 
-func (r RedisCache) Get(ctx context.Context, options *models.BannerIdentOptions) (*models.UserBanner, error) {
+// Get returns the banner given to users by the feature and tag with its targeting and content translated
+// to the locale, nil is returned on cache miss
+func (r RedisCache) Get(ctx context.Context, options *models.BannerIdentOptions, locale string) (*models.Candidate, error) {
+	banners, err := r.GetMany(ctx, []models.BannerIdentOptions{*options}, locale)
+	if err != nil {
+		return nil, err
+	}
+	return banners[0], nil
+}
+
+// GetMany returns banners of every feature and tag of options like Get in their order, missing ones are nil.
+// Banners absent in the local cache are read with a single pipeline
+func (r RedisCache) GetMany(ctx context.Context, options []models.BannerIdentOptions, locale string) ([]*models.Candidate, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
-		res, err := r.handleGet(ctx, options)
-		switch {
-		case errors.Is(err, cache.ErrCacheMiss):
-			return nil, nil
-		case err != nil:
-			return nil, err
-		default:
-			return res, nil
-		}
+		return r.handleGetMany(ctx, options, locale)
 	}
 }
 
-func (r RedisCache) handleGet(ctx context.Context, options *models.BannerIdentOptions) (*models.UserBanner, error) {
-	adapter := RedisStorageAdapter{
-		FeatureId: options.FeatureId,
-		TagId:     options.TagId,
+func (r RedisCache) handleGetMany(ctx context.Context, options []models.BannerIdentOptions, locale string) ([]*models.Candidate, error) {
+	adapters := make([]RedisStorageAdapter, len(options))
+	localKeys := make([]string, len(options))
+	var remote []int
+	for i := range options {
+		adapters[i] = RedisStorageAdapter{
+			Tenant:    identity.From(ctx).Tenant,
+			FeatureId: options[i].FeatureId,
+			TagId:     options[i].TagId,
+			Locale:    locale,
+		}
+		// the generation is taken before reading, so banners read before an invalidation are not kept locally
+		localKeys[i] = adapters[i].LocalKey(r.generations.get(adapters[i].Key()))
+		if b, ok := r.local.Get(localKeys[i]); ok {
+			adapters[i].Bytes = b
+			continue
+		}
+		remote = append(remote, i)
 	}
-	err := r.redisCache.Get(ctx, adapter.Key(), &adapter.Bytes)
-	if err != nil {
-		return nil, err
+	if len(remote) > 0 {
+		cmds := make([]*redis.StringCmd, len(remote))
+		_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for j, i := range remote {
+				cmds[j] = pipe.HGet(ctx, adapters[i].Key(), locale)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		for j, i := range remote {
+			b, err := cmds[j].Bytes()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			adapters[i].Bytes = b
+			r.local.Set(localKeys[i], b)
+		}
 	}
-	content, err := adapter.Content()
-	if err != nil {
-		return nil, err
+	banners := make([]*models.Candidate, len(options))
+	for i := range adapters {
+		if adapters[i].Bytes == nil {
+			continue
+		}
+		banner, err := adapters[i].Banner()
+		if err != nil {
+			return nil, err
+		}
+		banners[i] = banner
 	}
-	return &models.UserBanner{Content: content}, nil
+	return banners, nil
 }
 
-func (r RedisCache) Put(ctx context.Context, options *models.BannerIdentOptions, banner *models.UserBanner) error {
+// Put caches the banner for users requesting the locale, which may differ from the locale of its content
+func (r RedisCache) Put(ctx context.Context, options *models.BannerIdentOptions, locale string, banner *models.Candidate) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 		adapter := RedisStorageAdapter{
-			UserBanner: banner.Content,
-			FeatureId:  options.FeatureId,
-			TagId:      options.TagId,
+			Candidate: *banner,
+			Tenant:    identity.From(ctx).Tenant,
+			FeatureId: options.FeatureId,
+			TagId:     options.TagId,
+			Locale:    locale,
 		}
 		value, err := adapter.Value()
 		if err != nil {
 			return err
 		}
-		if err := r.redisCache.Set(&cache.Item{
-			Ctx:   ctx,
-			Key:   adapter.Key(),
-			Value: value,
-			TTL:   r.ttl,
-		}); err != nil {
+		generation := r.generations.get(adapter.Key())
+		_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, adapter.Key(), locale, value)
+			pipe.ExpireNX(ctx, adapter.Key(), r.ttl)
+			return nil
+		})
+		if err != nil {
 			return err
 		}
+		r.local.Set(adapter.LocalKey(generation), value)
 		return nil
 	}
 }
+
+// Invalidate removes banners of every feature and tag of options in all locales, so users get them from the database.
+// Local entries are removed by the instance invalidating them only
+func (r RedisCache) Invalidate(ctx context.Context, options []models.BannerIdentOptions) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		keys := make([]string, len(options))
+		for i := range options {
+			adapter := RedisStorageAdapter{Tenant: identity.From(ctx).Tenant, FeatureId: options[i].FeatureId, TagId: options[i].TagId}
+			keys[i] = adapter.Key()
+			r.generations.next(keys[i])
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		return r.rdb.Del(ctx, keys...).Err()
+	}
+}
@@ -1,33 +1,49 @@
 package cache
 
 import (
+	"BannerFlow/internal/domain/models"
 	"encoding/json"
 	"fmt"
 )
 
 type RedisStorageAdapter struct {
-	UserBanner map[string]any
-	Bytes      []byte
-	FeatureId  int
-	TagId      int
+	Candidate models.Candidate
+	Bytes     []byte
+	Tenant    string
+	FeatureId int
+	TagId     int
+	Locale    string
 }
 
+// Key is the redis hash of the feature and tag namespaced by the tenant, so tenants using the same feature and tag
+// get their own banners. Locale resolved from the request is the field of the hash, content of each locale
+// is cached separately
 func (adapter RedisStorageAdapter) Key() string {
-	return fmt.Sprintf("feature: %d, tag: %d", adapter.FeatureId, adapter.TagId)
+	return fmt.Sprintf("tenant: %s, feature: %d, tag: %d", adapter.Tenant, adapter.FeatureId, adapter.TagId)
+}
+
+// LocalKey is the key of the local cache entry for the generation of the feature and tag
+func (adapter RedisStorageAdapter) LocalKey(generation uint64) string {
+	return fmt.Sprintf("%s, generation: %d, locale: %s", adapter.Key(), generation, adapter.Locale)
 }
 
 func (adapter RedisStorageAdapter) Value() ([]byte, error) {
-	b, err := json.Marshal(&adapter.UserBanner)
+	b, err := json.Marshal(&adapter.Candidate)
 	if err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
-func (adapter RedisStorageAdapter) Content() (map[string]any, error) {
-	err := json.Unmarshal(adapter.Bytes, &adapter.UserBanner)
+// Banner decodes the cached candidate, entries cached before banners had targeting hold only the content
+// and are reported as missing
+func (adapter RedisStorageAdapter) Banner() (*models.Candidate, error) {
+	err := json.Unmarshal(adapter.Bytes, &adapter.Candidate)
 	if err != nil {
 		return nil, err
 	}
-	return adapter.UserBanner, nil
+	if adapter.Candidate.BannerId == 0 {
+		return nil, nil
+	}
+	return &adapter.Candidate, nil
 }
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"BannerFlow/internal/domain/models"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	bannerEventsChannel   = "banner_events"
+	listenBannerEvents    = "LISTEN " + bannerEventsChannel
+	unlistenBannerEvents  = "UNLISTEN " + bannerEventsChannel
+	bannerEventColumns    = "id, tenant, bannerId, featureId, tagIds, created"
+	listBannerEventsQuery = "SELECT " + bannerEventColumns + " FROM banner_events WHERE id > $1 ORDER BY id LIMIT $2"
+	lastBannerEventQuery  = "SELECT COALESCE(max(id), 0) FROM banner_events"
+	bannerChangedQuery    = `SELECT EXISTS (SELECT 1 FROM banner_events
+	WHERE tenant = $1 AND featureId = $2 AND tagIds && $3 AND id > $4) OR NOT EXISTS (SELECT 1 FROM banner_events WHERE id <= $4)`
+	pruneBannerEventsQuery = "DELETE FROM banner_events WHERE created < current_timestamp - make_interval(secs => $1)"
+)
+
+// ListenBannerEvents calls fn once it listens to notifications about banner events and then on every notification,
+// so events raised while the previous connection was lost are read too. It holds a connection of the pool
+// until ctx is done, fn fails or the connection is lost
+func (p PostgresDatabase) ListenBannerEvents(ctx context.Context, fn func(ctx context.Context) error) error {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the connection goes back to the pool, it must not receive notifications there
+		unlistenCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, _ = conn.Exec(unlistenCtx, unlistenBannerEvents)
+		conn.Release()
+	}()
+	if _, err = conn.Exec(ctx, listenBannerEvents); err != nil {
+		return err
+	}
+	for {
+		if err = fn(ctx); err != nil {
+			return err
+		}
+		if _, err = conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ListBannerEvents returns at most limit events of every tenant following the event after in the order of ids
+func (p PostgresDatabase) ListBannerEvents(ctx context.Context, after int64, limit int) ([]models.BannerEvent, error) {
+	rows, _ := p.conn(ctx).Query(ctx, listBannerEventsQuery, after, limit)
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.BannerEvent, error) {
+		res := models.BannerEvent{}
+		err := row.Scan(&res.Id, &res.Tenant, &res.BannerId, &res.FeatureId, &res.TagIds, &res.CreatedAt)
+		return res, err
+	})
+}
+
+// LastBannerEventId returns the id of the last event of every tenant, zero if there are none
+func (p PostgresDatabase) LastBannerEventId(ctx context.Context) (int64, error) {
+	var id int64
+	err := p.conn(ctx).QueryRow(ctx, lastBannerEventQuery).Scan(&id)
+	return id, err
+}
+
+// BannerChangedSince reports whether the banner of the feature may differ for users having any of the tags
+// since the event. Events up to it may be pruned already, then the banner is reported as changed
+func (p PostgresDatabase) BannerChangedSince(ctx context.Context, featureId int, tagIds []int, after int64) (bool, error) {
+	var changed bool
+	err := p.conn(ctx).QueryRow(ctx, bannerChangedQuery, tenant(ctx), featureId, tagIds, after).Scan(&changed)
+	return changed, err
+}
+
+// PruneBannerEvents removes events older than the retention
+func (p PostgresDatabase) PruneBannerEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	tag, err := p.conn(ctx).Exec(ctx, pruneBannerEventsQuery, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
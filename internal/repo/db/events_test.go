@@ -0,0 +1,77 @@
+package db
+
+import (
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBannerEvents(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := tenantContext("acme")
+	id, err := db.Add(ctx, &models.Banner{
+		BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{1, 2}, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		IsActive:   true,
+	})
+	require.NoError(t, err)
+	last, err := db.LastBannerEventId(ctx)
+	require.NoError(t, err)
+
+	_, err = db.pool.Exec(ctx, "UPDATE banners SET tagIds = '{2,3}' WHERE id = $1", id)
+	require.NoError(t, err)
+	_, err = db.pool.Exec(ctx, insertDeactivatedBannerQuery, id)
+	require.NoError(t, err)
+	_, err = db.pool.Exec(ctx, "UPDATE banners SET actor = 'admin' WHERE id = $1", id)
+	require.NoError(t, err)
+	events, err := db.ListBannerEvents(ctx, last, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2, "changes users don't see raise no events")
+	assert.Equal(t, models.BannerEvent{Id: last + 1, Tenant: "acme", BannerId: id, FeatureId: 1, TagIds: []int{1, 2, 3},
+		CreatedAt: events[0].CreatedAt}, events[0], "users of removed tags are notified too")
+	assert.Equal(t, []int{2, 3}, events[1].TagIds)
+
+	changed, err := db.BannerChangedSince(ctx, 1, []int{1}, last+1)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	changed, err = db.BannerChangedSince(ctx, 1, []int{3, 4}, last+1)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = db.BannerChangedSince(tenantContext("other"), 1, []int{3}, last+1)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	removed, err := db.PruneBannerEvents(ctx, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, last+2, removed)
+	changed, err = db.BannerChangedSince(ctx, 1, []int{1}, last+1)
+	require.NoError(t, err)
+	assert.True(t, changed, "pruned events may have changed the banner")
+}
+
+func TestListenBannerEvents(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx, cancel := context.WithTimeout(tenantContext(""), 10*time.Second)
+	defer cancel()
+	stop := errors.New("stop")
+	calls := 0
+	err := db.ListenBannerEvents(ctx, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			_, err := db.Add(ctx, &models.Banner{
+				BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{1}, UserBanner: models.UserBanner{Content: map[string]any{}}},
+				IsActive:   true,
+			})
+			return err
+		}
+		events, err := db.ListBannerEvents(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+		return stop
+	})
+	assert.ErrorIs(t, err, stop, "notification of the event wakes the listener")
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	selectFeatureSchemaQuery = "SELECT featureId, schema, created, updated FROM feature_schemas WHERE featureId = $1 AND tenant = $2"
+	listFeatureSchemasQuery  = "SELECT featureId, schema, created, updated FROM feature_schemas WHERE tenant = $1 ORDER BY featureId"
+	deleteFeatureSchemaQuery = "DELETE FROM feature_schemas WHERE featureId = $1 AND tenant = $2"
+	upsertFeatureSchemaQuery = `INSERT INTO feature_schemas (featureId, schema, tenant) VALUES ($1, $2, $3)
+	ON CONFLICT (tenant, featureId) DO UPDATE SET schema = EXCLUDED.schema, updated = current_timestamp`
+)
+
+func (p PostgresDatabase) GetFeatureSchema(ctx context.Context, featureId int) (*models.FeatureSchema, error) {
+	rows, _ := p.conn(ctx).Query(ctx, selectFeatureSchemaQuery, featureId, tenant(ctx))
+	schema, err := pgx.CollectOneRow(rows, scanFeatureSchema)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (p PostgresDatabase) ListFeatureSchemas(ctx context.Context) ([]models.FeatureSchema, error) {
+	rows, _ := p.conn(ctx).Query(ctx, listFeatureSchemasQuery, tenant(ctx))
+	return pgx.CollectRows(rows, scanFeatureSchema)
+}
+
+func (p PostgresDatabase) PutFeatureSchema(ctx context.Context, schema *models.FeatureSchema) error {
+	_, err := p.conn(ctx).Exec(ctx, upsertFeatureSchemaQuery, schema.FeatureId, Attrs(schema.Schema), tenant(ctx))
+	return err
+}
+
+func (p PostgresDatabase) DeleteFeatureSchema(ctx context.Context, featureId int) error {
+	tag, err := p.conn(ctx).Exec(ctx, deleteFeatureSchemaQuery, featureId, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func scanFeatureSchema(row pgx.CollectableRow) (models.FeatureSchema, error) {
+	res := models.FeatureSchema{}
+	attr := make(Attrs)
+	err := row.Scan(&res.FeatureId, &attr, &res.CreatedAt, &res.UpdatedAt)
+	res.Schema = attr
+	return res, err
+}
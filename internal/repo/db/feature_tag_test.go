@@ -0,0 +1,180 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const selectKeysQuery = "SELECT featureId, tagId FROM feature_tag WHERE bannerId = $1 ORDER BY featureId, tagId"
+
+// bannerKeys returns feature_tag keys of the banner as feature:tag
+func bannerKeys(t *testing.T, db *PostgresDatabase, id int) []string {
+	t.Helper()
+	rows, _ := db.pool.Query(context.Background(), selectKeysQuery, id)
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (string, error) {
+		var featureId, tagId int
+		err := row.Scan(&featureId, &tagId)
+		return fmt.Sprintf("%d:%d", featureId, tagId), err
+	})
+	require.NoError(t, err)
+	return keys
+}
+
+// findBanner looks the banner up the same way user banners are
+func findBanner(t *testing.T, db *PostgresDatabase, featureId, tagId int) []int {
+	t.Helper()
+	banners, err := db.List(context.Background(), &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: featureId, TagId: tagId},
+		Limit:              models.ZeroValue,
+		Offset:             models.ZeroValue,
+	})
+	require.NoError(t, err)
+	var ids []int
+	for _, banner := range banners {
+		ids = append(ids, banner.BannerId)
+	}
+	return ids
+}
+
+func TestFeatureTagSync(t *testing.T) {
+	content := map[string]any{"title": "Sale"}
+	tests := []struct {
+		name     string
+		mutate   func(ctx context.Context, db *PostgresDatabase, id, otherId int) error
+		wantErr  error
+		wantKeys []string
+	}{
+		{
+			name:     "add",
+			mutate:   func(context.Context, *PostgresDatabase, int, int) error { return nil },
+			wantKeys: []string{"1:1", "1:2"},
+		},
+		{
+			name: "add with taken key",
+			mutate: func(ctx context.Context, db *PostgresDatabase, _, _ int) error {
+				_, err := db.Add(ctx, &models.Banner{BaseBanner: models.BaseBanner{
+					UserBanner: models.UserBanner{Content: content}, FeatureId: 1, TagIds: []int{2, 5},
+				}})
+				return err
+			},
+			wantErr:  e.ErrorConflict,
+			wantKeys: []string{"1:1", "1:2"},
+		},
+		{
+			name: "update tags",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				update := updateBanner(models.TagBit, 0, []int{2, 4}, nil, false)
+				return db.Update(ctx, id, &update, "")
+			},
+			wantKeys: []string{"1:2", "1:4"},
+		},
+		{
+			name: "update feature",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				update := updateBanner(models.FeatureBit, 5, nil, nil, false)
+				return db.Update(ctx, id, &update, "")
+			},
+			wantKeys: []string{"5:1", "5:2"},
+		},
+		{
+			name: "update feature and tags",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				update := updateBanner(models.FeatureBit|models.TagBit, 5, []int{6}, nil, false)
+				return db.Update(ctx, id, &update, "")
+			},
+			wantKeys: []string{"5:6"},
+		},
+		{
+			name: "update with taken key",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				update := updateBanner(models.FeatureBit|models.TagBit, 2, []int{1, 3}, nil, false)
+				return db.Update(ctx, id, &update, "")
+			},
+			wantErr:  e.ErrorConflict,
+			wantKeys: []string{"1:1", "1:2"},
+		},
+		{
+			name: "update content",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				return db.UpdateContent(ctx, id, "", func(*models.BaseBanner) (map[string]any, error) {
+					return map[string]any{"title": "Black Friday"}, nil
+				})
+			},
+			wantKeys: []string{"1:1", "1:2"},
+		},
+		{
+			name: "select version",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				update := updateBanner(models.FeatureBit|models.TagBit, 5, []int{4}, nil, false)
+				if err := db.Update(ctx, id, &update, ""); err != nil {
+					return err
+				}
+				return db.SelectBannerVersion(ctx, id, 1, "")
+			},
+			wantKeys: []string{"1:1", "1:2"},
+		},
+		{
+			name: "select version with taken key",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, otherId int) error {
+				update := updateBanner(models.TagBit, 0, []int{4}, nil, false)
+				if err := db.Update(ctx, id, &update, ""); err != nil {
+					return err
+				}
+				update = updateBanner(models.FeatureBit|models.TagBit, 1, []int{1}, nil, false)
+				if err := db.Update(ctx, otherId, &update, ""); err != nil {
+					return err
+				}
+				return db.SelectBannerVersion(ctx, id, 1, "")
+			},
+			wantErr:  e.ErrorConflict,
+			wantKeys: []string{"1:4"},
+		},
+		{
+			name: "delete",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				return db.DeleteById(ctx, id, "")
+			},
+		},
+		{
+			name: "delete by ids",
+			mutate: func(ctx context.Context, db *PostgresDatabase, id, _ int) error {
+				_, err := db.DeleteByIds(ctx, []int{id})
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			ctx := context.Background()
+			id := addBanner(t, db, 1, []int{1, 2}, content, true)
+			otherId := addBanner(t, db, 2, []int{3}, content, true)
+
+			err := test.mutate(ctx, db, id, otherId)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			keys := bannerKeys(t, db, id)
+			assert.ElementsMatch(t, test.wantKeys, keys)
+			for _, key := range keys {
+				var featureId, tagId int
+				_, err = fmt.Sscanf(key, "%d:%d", &featureId, &tagId)
+				require.NoError(t, err)
+				assert.Equal(t, []int{id}, findBanner(t, db, featureId, tagId), "banner must be found by %s", key)
+			}
+			if len(test.wantKeys) == 0 || test.wantKeys[0] != "1:1" {
+				assert.NotContains(t, findBanner(t, db, 1, 1), id, "banner must not be found by its previous key")
+			}
+		})
+	}
+}
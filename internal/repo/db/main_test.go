@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// dsnEnv points the tests to an existing empty database instead of a started container
+	dsnEnv          = "TEST_POSTGRES_DSN"
+	postgresImage   = "postgres:alpine"
+	postgresPass    = "postgres1234"
+	migrationsDir   = "../../../migrations"
+	truncateQuery   = "TRUNCATE banners, deactivated, feature_tag, banner_history, feature_schemas, features, tags, banner_events, webhooks RESTART IDENTITY CASCADE"
+	startupDeadline = time.Minute
+)
+
+// testPool is nil when neither TEST_POSTGRES_DSN nor docker is available, tests using it are skipped then
+var testPool *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	os.Exit(runWithPostgres(m))
+}
+
+func runWithPostgres(m *testing.M) int {
+	ctx := context.Background()
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		container, containerDsn, err := startPostgres(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "postgres tests are skipped: %v\n", err)
+			return m.Run()
+		}
+		defer exec.Command("docker", "rm", "-f", container).Run()
+		dsn = containerDsn
+	}
+	pool, err := connect(ctx, dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to postgres: %v\n", err)
+		return 1
+	}
+	defer pool.Close()
+	if err = migrate(ctx, pool); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to apply migrations: %v\n", err)
+		return 1
+	}
+	testPool = pool
+	return m.Run()
+}
+
+// startPostgres runs a throwaway container published on a random local port
+func startPostgres(ctx context.Context) (string, string, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return "", "", fmt.Errorf("docker is not available and %s is not set", dsnEnv)
+	}
+	out, err := exec.CommandContext(ctx, "docker", "run", "-d", "--rm",
+		"-e", "POSTGRES_PASSWORD="+postgresPass, "-p", "127.0.0.1::5432", postgresImage).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start container: %w", err)
+	}
+	container := strings.TrimSpace(string(out))
+	out, err = exec.CommandContext(ctx, "docker", "port", container, "5432/tcp").Output()
+	if err != nil {
+		exec.Command("docker", "rm", "-f", container).Run()
+		return "", "", fmt.Errorf("failed to get container port: %w", err)
+	}
+	address, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return container, fmt.Sprintf("postgres://postgres:%s@%s/postgres?sslmode=disable", postgresPass, address), nil
+}
+
+// connect waits until the database accepts connections
+func connect(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
+	deadline := time.Now().Add(startupDeadline)
+	for {
+		pool, err := NewPostgres(ctx, dsn)
+		if err == nil {
+			return pool, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// migrate applies up migrations in the order of their numbers
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
+	if err != nil {
+		return err
+	}
+	number := func(file string) int {
+		n, _ := strconv.Atoi(strings.SplitN(filepath.Base(file), "_", 2)[0])
+		return n
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return number(files[i]) < number(files[j])
+	})
+	for _, file := range files {
+		script, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err = pool.Exec(ctx, string(script)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// newTestDatabase returns database with empty tables or skips the test without postgres
+func newTestDatabase(t *testing.T) *PostgresDatabase {
+	t.Helper()
+	if testPool == nil {
+		t.Skip("postgres is not available")
+	}
+	if _, err := testPool.Exec(context.Background(), truncateQuery); err != nil {
+		t.Fatalf("failed to truncate tables: %v", err)
+	}
+	return &PostgresDatabase{pool: testPool}
+}
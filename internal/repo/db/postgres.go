@@ -2,28 +2,51 @@ package db
 
 import (
 	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
 	"BannerFlow/internal/domain/models"
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	callSelectVersionProcedure       = "CALL choose_banner_from_history($1,$2)"
-	selectHistoryQuery               = "SELECT version, featureid, tagids, content FROM banner_history WHERE bannerid = $1 ORDER BY version"
-	selectIdFromFeatureTagQuery      = "SELECT ARRAY_AGG(DISTINCT bannerid) ids FROM feature_tag WHERE"
-	deleteBannersQuery               = "DELETE FROM banners WHERE id = ANY($1)"
+	setActorQuery                    = "SELECT set_config('bannerflow.actor', $1, true)"
+	historyColumns                   = "version, featureid, tagids, content, actor, is_active, created"
+	historyTenantFilter              = " AND EXISTS (SELECT 1 FROM banners b WHERE b.id = h.bannerId AND b.tenant = $"
+	selectHistoryQuery               = "SELECT " + historyColumns + " FROM banner_history h WHERE h.bannerId = $"
+	selectBannerForUpdateQuery       = "SELECT featureId, tagIds, content FROM banners WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	updateContentQuery               = "UPDATE banners SET content = $2 WHERE id = $1"
+	pruneHistoryByAgeQuery           = "DELETE FROM banner_history WHERE created < current_timestamp - make_interval(secs => $1)"
+	previewDeleteQuery               = "SELECT b.id FROM banners b WHERE TRUE"
+	trashTenantBannersQuery          = "UPDATE banners SET deleted_at = current_timestamp WHERE id = ANY($1) AND tenant = $2 AND deleted_at IS NULL RETURNING id"
+	trashBannersQuery                = "UPDATE banners SET deleted_at = current_timestamp WHERE id = ANY($1) AND deleted_at IS NULL"
+	restoreBannerQuery               = "UPDATE banners SET deleted_at = NULL WHERE id = $1 AND tenant = $2 AND deleted_at IS NOT NULL"
+	purgeTrashQuery                  = "DELETE FROM banners WHERE deleted_at < current_timestamp - make_interval(secs => $1)"
 	deleteBannerFromDeactivatedQuery = "DELETE FROM deactivated WHERE bannerid = $1"
-	insertDeactivatedBannerQuery     = "INSERT INTO deactivated (bannerid) VALUES ($1)"
-	insertBannerQuery                = "INSERT INTO banners (content, tagIds, featureId) VALUES ($1, $2, $3) RETURNING id"
-	listBannersQuery                 = `SELECT b.id, b.content, b.created, b.updated, b.featureId, b.tagIds,
+	insertDeactivatedBannerQuery     = "INSERT INTO deactivated (bannerid) VALUES ($1) ON CONFLICT DO NOTHING"
+	insertBannerQuery                = "INSERT INTO banners (content, tagIds, featureId, tenant) VALUES ($1, $2, $3, $4) RETURNING id"
+	selectBannerQuery                = `SELECT b.id, b.content, b.created, b.updated, b.featureId, b.tagIds, b.revision,
+    NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id) is_active, b.deleted_at, ` + stateColumns + `
+	FROM banners b WHERE b.id = $1 AND b.tenant = $2 AND b.deleted_at IS NULL`
+	listBannersQuery = `SELECT b.id, b.content, b.created, b.updated, b.featureId, b.tagIds, b.revision,
+    NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id) is_active, b.deleted_at, ` + stateColumns + `
+	FROM banners b WHERE TRUE`
+	countBannersQuery            = "SELECT COUNT(*) FROM banners b WHERE TRUE"
+	selectRevisionForUpdateQuery = `SELECT b.revision,
     NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id) is_active
-	FROM feature_tag ft 
-    JOIN banners b on b.id = ft.bannerId`
+	FROM banners b WHERE b.id = $1 AND b.tenant = $2 AND b.deleted_at IS NULL FOR UPDATE`
+	selectHistoryVersionQuery = "SELECT " + historyColumns + ` FROM banner_history h WHERE h.bannerId = $1 AND h.version = $2
+	AND EXISTS (SELECT 1 FROM banners b WHERE b.id = h.bannerId AND b.tenant = $3)`
+	pruneHistoryByCountQuery = `DELETE FROM banner_history bh USING (
+    SELECT id, row_number() OVER (PARTITION BY bannerId ORDER BY version DESC) rn FROM banner_history) r
+	WHERE bh.id = r.id AND r.rn > $1`
 )
 
 type IFace interface {
@@ -34,8 +57,19 @@ type IFace interface {
 	Ping(ctx context.Context) error
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
 }
 
+// querier is implemented by the pool and by pgx.Tx, so the same queries run inside InTransaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type txKey struct{}
+
 func NewPostgres(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	pool, err := pgxpool.New(ctx, dsn)
 	if err != nil {
@@ -61,145 +95,466 @@ func (p PostgresDatabase) Stop() {
 	p.pool.Close()
 }
 
+// InTransaction runs fn in one transaction. Calls made with the context passed to fn join it
+// and their own transactions become savepoints, so changes of fn are committed or rolled back together
+func (p PostgresDatabase) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// conn returns transaction started by InTransaction or the pool
+func (p PostgresDatabase) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return p.pool
+}
+
 func (p PostgresDatabase) Add(ctx context.Context, banner *models.Banner) (int, error) {
 	if err := p.pool.Ping(ctx); err != nil {
 		return 0, e.ErrorFailedToConnect
 	}
-	tx, err := p.pool.Begin(ctx)
+	tx, err := p.conn(ctx).Begin(ctx)
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback(ctx)
-	var id int
-	err = tx.QueryRow(ctx, insertBannerQuery, Attrs(banner.Content), banner.TagIds, banner.FeatureId).Scan(&id)
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
-		if pgErr.Code == "23505" {
-			return 0, e.ErrorConflict
-		}
+	if err = setActor(ctx, tx); err != nil {
 		return 0, err
 	}
+	id, err := insertBanner(ctx, tx, banner)
+	if err != nil {
+		return 0, err
+	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookCreated, id); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+func insertBanner(ctx context.Context, tx pgx.Tx, banner *models.Banner) (int, error) {
+	var id int
+	err := tx.QueryRow(ctx, insertBannerQuery, Attrs(banner.Content), banner.TagIds, banner.FeatureId, tenant(ctx)).Scan(&id)
+	if err != nil {
+		return 0, conflictError(err)
+	}
 	if !banner.IsActive {
 		_, err = tx.Exec(ctx, insertDeactivatedBannerQuery, id)
 		if err != nil {
 			return 0, err
 		}
 	}
-	return id, tx.Commit(ctx)
+	return id, nil
 }
 
-func (p PostgresDatabase) Update(ctx context.Context, id int, banner *models.UpdateBanner) error {
+// Update changes fields of the banner set in Flags if its entity tag matches ifMatch, empty ifMatch skips the check.
+// Every statement runs in one transaction, so the banner is either updated completely or not at all.
+// Webhooks are notified about the activation and about any other change as an update
+func (p PostgresDatabase) Update(ctx context.Context, id int, banner *models.UpdateBanner, ifMatch string) error {
 	if p.pool.Ping(ctx) != nil {
 		return e.ErrorFailedToConnect
 	}
-	batch := prepareUpdateBatch(id, banner)
-	br := p.pool.SendBatch(ctx, batch)
-	for i := 0; i < batch.Len(); i++ {
-		if _, err := br.Exec(); err != nil {
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err = setActor(ctx, tx); err != nil {
+		return err
+	}
+	if err = lockBanner(ctx, tx, id, ifMatch); err != nil {
+		return err
+	}
+	var events []models.WebhookEvent
+	if banner.Flags&models.IsActiveBit > 0 {
+		query, event := insertDeactivatedBannerQuery, models.WebhookUpdated
+		if banner.IsActive {
+			query, event = deleteBannerFromDeactivatedQuery, models.WebhookActivated
+		}
+		tag, err := tx.Exec(ctx, query, id)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() > 0 {
+			events = append(events, event)
+		}
+	}
+	if banner.Flags&(models.FeatureBit|models.TagBit|models.ContentBit) > 0 {
+		query, args := buildUpdateQuery(id, banner)
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return conflictError(err)
+		}
+		if !slices.Contains(events, models.WebhookUpdated) {
+			events = append(events, models.WebhookUpdated)
+		}
+	}
+	for _, event := range events {
+		if err = enqueueWebhooks(ctx, tx, event, id); err != nil {
 			return err
 		}
 	}
-	return nil
+	return tx.Commit(ctx)
+}
+
+// UpdateContent locks the banner and replaces its content with the one returned by update,
+// so concurrent edits are applied one after another
+func (p PostgresDatabase) UpdateContent(ctx context.Context, id int, ifMatch string, update func(banner *models.BaseBanner) (map[string]any, error)) error {
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err = setActor(ctx, tx); err != nil {
+		return err
+	}
+	if err = lockBanner(ctx, tx, id, ifMatch); err != nil {
+		return err
+	}
+	banner := &models.BaseBanner{}
+	attr := make(Attrs)
+	err = tx.QueryRow(ctx, selectBannerForUpdateQuery, id).Scan(&banner.FeatureId, &banner.TagIds, &attr)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return e.ErrorNotFound
+	}
+	if err != nil {
+		return err
+	}
+	banner.Content = attr
+	content, err := update(banner)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, updateContentQuery, id, Attrs(content)); err != nil {
+		return err
+	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookUpdated, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (p PostgresDatabase) GetHistoryForId(ctx context.Context, id int) ([]models.HistoryBanner, error) {
-	rows, _ := p.pool.Query(ctx, selectHistoryQuery, id)
-	historyBanners, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.HistoryBanner, error) {
-		res := models.HistoryBanner{}
-		var attr Attrs
-		err := row.Scan(&res.Version, &res.FeatureId, &res.TagIds, attr)
-		res.Content = attr
-		return res, err
-	})
-	return historyBanners, err
+func (p PostgresDatabase) GetHistoryForId(ctx context.Context, id int, options *models.HistoryListOptions) ([]models.HistoryBanner, error) {
+	query, args := buildHistoryQuery(id, tenant(ctx), options)
+	rows, _ := p.conn(ctx).Query(ctx, query, args...)
+	return pgx.CollectRows(rows, scanHistoryBanner)
 }
 
-func (p PostgresDatabase) SelectBannerVersion(ctx context.Context, id, version int) error {
-	_, err := p.pool.Exec(ctx, callSelectVersionProcedure, id, version)
+func (p PostgresDatabase) GetHistoryVersion(ctx context.Context, id, version int) (*models.HistoryBanner, error) {
+	rows, _ := p.conn(ctx).Query(ctx, selectHistoryVersionQuery, id, version, tenant(ctx))
+	banner, err := pgx.CollectOneRow(rows, scanHistoryBanner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
+func (p PostgresDatabase) SelectBannerVersion(ctx context.Context, id, version int, ifMatch string) error {
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err = setActor(ctx, tx); err != nil {
+		return err
+	}
+	if err = lockBanner(ctx, tx, id, ifMatch); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, callSelectVersionProcedure, id, version); err != nil {
+		return conflictError(err)
+	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookVersionRestored, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// PruneHistory removes versions exceeding retention and returns the number of removed ones
+func (p PostgresDatabase) PruneHistory(ctx context.Context, retention *models.HistoryRetention) (int64, error) {
+	var removed int64
+	if retention.MaxVersions > 0 {
+		tag, err := p.conn(ctx).Exec(ctx, pruneHistoryByCountQuery, retention.MaxVersions)
+		if err != nil {
+			return removed, err
+		}
+		removed += tag.RowsAffected()
+	}
+	if retention.MaxAge > 0 {
+		tag, err := p.conn(ctx).Exec(ctx, pruneHistoryByAgeQuery, retention.MaxAge.Seconds())
+		if err != nil {
+			return removed, err
+		}
+		removed += tag.RowsAffected()
+	}
+	return removed, nil
+}
+
+// tenant returns the tenant of the request, queries of banners and registries are filtered by it
+func tenant(ctx context.Context) string {
+	return identity.From(ctx).Tenant
+}
+
+// setActor makes triggers record the actor of the request in the banner and its history
+func setActor(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, setActorQuery, identity.From(ctx).Actor)
 	return err
 }
 
-func (p PostgresDatabase) DeleteByIds(ctx context.Context, ids ...int) error {
-	if p.pool.Ping(ctx) != nil {
-		return e.ErrorFailedToConnect
+// conflictError reports unique violation of feature_tag key, which is taken by another banner, as e.ErrorConflict
+func conflictError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return e.ErrorConflict
+	}
+	return err
+}
+
+// lockBanner locks the banner, so it can't change until the transaction ends, and compares
+// its entity tag with ifMatch. Empty ifMatch skips the comparison
+func lockBanner(ctx context.Context, tx pgx.Tx, id int, ifMatch string) error {
+	var revision int
+	var isActive bool
+	err := tx.QueryRow(ctx, selectRevisionForUpdateQuery, id, tenant(ctx)).Scan(&revision, &isActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return e.ErrorNotFound
 	}
-	tag, err := p.pool.Exec(ctx, deleteBannersQuery, ids)
 	if err != nil {
 		return err
 	}
-	if tag.RowsAffected() == 0 {
-		return e.ErrorNotFound
+	if ifMatch != "" && !models.MatchETag(ifMatch, models.ETag(revision, isActive), false) {
+		return e.ErrorPreconditionFailed
 	}
 	return nil
 }
 
-func (p PostgresDatabase) DeleteByFeatureOrTag(ctx context.Context, options *models.BannerIdentOptions) error {
+func scanHistoryBanner(row pgx.CollectableRow) (models.HistoryBanner, error) {
+	res := models.HistoryBanner{}
+	attr := make(Attrs)
+	err := row.Scan(&res.Version, &res.FeatureId, &res.TagIds, &attr, &res.Actor, &res.IsActive, &res.CreatedAt)
+	res.Content = attr
+	return res, err
+}
+
+func (p PostgresDatabase) DeleteById(ctx context.Context, id int, ifMatch string) error {
 	if p.pool.Ping(ctx) != nil {
 		return e.ErrorFailedToConnect
 	}
-	tx, err := p.pool.Begin(ctx)
+	tx, err := p.conn(ctx).Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
-	query, args := buildDeleteQuery(options)
-	var ids []int
-	err = tx.QueryRow(ctx, query, args...).Scan(&ids)
-	if errors.Is(err, pgx.ErrNoRows) {
+	if err = setActor(ctx, tx); err != nil {
+		return err
+	}
+	if err = lockBanner(ctx, tx, id, ifMatch); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, trashBannersQuery, []int{id})
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
 		return e.ErrorNotFound
 	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookDeleted, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteByIds moves live banners of the tenant having the ids to the trash and returns ids of the moved ones
+// in ascending order, banners deleted meanwhile are skipped
+func (p PostgresDatabase) DeleteByIds(ctx context.Context, ids []int) ([]int, error) {
+	if p.pool.Ping(ctx) != nil {
+		return nil, e.ErrorFailedToConnect
+	}
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	rows, _ := tx.Query(ctx, trashTenantBannersQuery, ids, tenant(ctx))
+	removed, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(removed)
+	if err = enqueueWebhooks(ctx, tx, models.WebhookDeleted, removed...); err != nil {
+		return nil, err
+	}
+	return removed, tx.Commit(ctx)
+}
+
+// PreviewDelete returns ids of live banners of the tenant matching the filter in ascending order
+func (p PostgresDatabase) PreviewDelete(ctx context.Context, filter *models.BulkDeleteFilter) ([]int, error) {
+	if p.pool.Ping(ctx) != nil {
+		return nil, e.ErrorFailedToConnect
+	}
+	query, args := buildDeleteQuery(previewDeleteQuery, tenant(ctx), filter)
+	rows, _ := p.conn(ctx).Query(ctx, query+" ORDER BY b.id", args...)
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []int{}
+	}
+	return ids, nil
+}
+
+// Restore takes the deleted banner out of the trash with its keys, e.ErrorConflict is returned
+// when another banner has taken any of them meanwhile. Webhooks are notified about it as an update
+func (p PostgresDatabase) Restore(ctx context.Context, id int) error {
+	if p.pool.Ping(ctx) != nil {
+		return e.ErrorFailedToConnect
+	}
+	tx, err := p.conn(ctx).Begin(ctx)
 	if err != nil {
 		return err
 	}
-	_, err = tx.Exec(ctx, deleteBannersQuery, ids)
+	defer tx.Rollback(ctx)
+	tag, err := tx.Exec(ctx, restoreBannerQuery, id, tenant(ctx))
 	if err != nil {
+		return conflictError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookUpdated, id); err != nil {
 		return err
 	}
 	return tx.Commit(ctx)
 }
 
+// PurgeTrash removes banners of every tenant deleted earlier than retention ago together with their history
+// and returns the number of removed ones
+func (p PostgresDatabase) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	tag, err := p.conn(ctx).Exec(ctx, purgeTrashQuery, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (p PostgresDatabase) GetById(ctx context.Context, id int) (*models.BannerExt, error) {
+	rows, _ := p.conn(ctx).Query(ctx, selectBannerQuery, id, tenant(ctx))
+	banner, err := pgx.CollectOneRow(rows, scanBannerExt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
 func (p PostgresDatabase) List(ctx context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
 	if p.pool.Ping(ctx) != nil {
 		return nil, e.ErrorFailedToConnect
 	}
-	query, args := buildListQuery(options)
-	rows, _ := p.pool.Query(ctx, query, args...)
-	banners, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.BannerExt, error) {
-		res := models.BannerExt{}
-		attr := make(Attrs)
-		err := row.Scan(&res.BannerId, &attr, &res.CreatedAt, &res.UpdatedAt, &res.FeatureId, &res.TagIds, &res.IsActive)
-		res.Content = attr
-		return res, err
-	})
-	return banners, err
+	query, args := buildListQuery(tenant(ctx), options)
+	rows, _ := p.conn(ctx).Query(ctx, query, args...)
+	return pgx.CollectRows(rows, scanBannerExt)
+}
+
+// Count returns the number of banners matching filters of options
+func (p PostgresDatabase) Count(ctx context.Context, options *models.BannerListOptions) (int, error) {
+	query, args := buildCountQuery(tenant(ctx), options)
+	var count int
+	err := p.conn(ctx).QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// ListEach passes banners matching options to fn one by one instead of collecting them, error of fn stops the listing
+func (p PostgresDatabase) ListEach(ctx context.Context, options *models.BannerListOptions, fn func(banner *models.BannerExt) error) error {
+	if p.pool.Ping(ctx) != nil {
+		return e.ErrorFailedToConnect
+	}
+	query, args := buildListQuery(tenant(ctx), options)
+	rows, err := p.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		banner, err := scanBannerExt(rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(&banner); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanBannerExt(row pgx.CollectableRow) (models.BannerExt, error) {
+	res := models.BannerExt{}
+	attr := make(Attrs)
+	err := row.Scan(&res.BannerId, &attr, &res.CreatedAt, &res.UpdatedAt, &res.FeatureId, &res.TagIds, &res.Revision, &res.IsActive,
+		&res.DeletedAt, &res.State, &res.Live)
+	res.Content = attr
+	return res, err
 }
 
-func buildDeleteQuery(options *models.BannerIdentOptions) (string, []any) {
+// buildDeleteQuery filters live banners of the bulk delete the same way as the listing, so with both
+// feature and tags the banners of the feature having any of the tags are matched
+func buildDeleteQuery(query, tenant string, filter *models.BulkDeleteFilter) (string, []any) {
 	builder := build()
-	builder(selectIdFromFeatureTagQuery, "")
-	if options.FeatureId > models.ZeroValue {
-		builder(" featureId = $", options.FeatureId)
-		builder(" GROUP BY featureId", nil)
-	} else {
-		builder(" tagId = $", options.TagId)
-		builder(" GROUP BY tagId", nil)
+	builder(query, nil)
+	options := &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: filter.FeatureId, TagId: models.ZeroValue},
+		TagIds:             filter.TagIds,
+		UpdatedBefore:      filter.UpdatedBefore,
 	}
+	if filter.InactiveOnly {
+		options.IsActive = new(bool)
+	}
+	buildListFilter(builder, tenant, options)
 	return builder("", nil)
 }
 
-func buildListQuery(options *models.BannerListOptions) (string, []any) {
+// sortColumns are the columns banners are listed by, id breaks ties of the others
+var sortColumns = map[models.ListSort]string{
+	models.SortByCreated: "b.created",
+	models.SortByUpdated: "b.updated",
+}
+
+// buildListQuery pages with LIMIT after the cursor, so pages are not shifted by banners deleted meanwhile
+func buildListQuery(tenant string, options *models.BannerListOptions) (string, []any) {
 	builder := build()
 	builder(listBannersQuery, nil)
-	if options.FeatureId > models.ZeroValue || options.TagId > models.ZeroValue {
-		builder(` WHERE EXISTS (SELECT 1 FROM feature_tag ft2 WHERE ft2.bannerId = b.id`, nil)
+	buildListFilter(builder, tenant, options)
+	column, byTime := sortColumns[options.Sort]
+	direction, compare := " ASC", " > "
+	if options.Desc {
+		direction, compare = " DESC", " < "
 	}
-	if options.FeatureId > models.ZeroValue {
-		builder(" AND featureId = $", options.FeatureId)
+	if after := options.After; after != nil {
+		if byTime {
+			builder(" AND ("+column+", b.id)"+compare+"($", after.Time)
+			builder(", $", after.Id)
+			builder(")", nil)
+		} else {
+			builder(" AND b.id"+compare+"$", after.Id)
+		}
 	}
-	if options.TagId > models.ZeroValue {
-		builder(" AND tagId = $", options.TagId)
+	if byTime {
+		builder(" ORDER BY "+column+direction+", b.id"+direction, nil)
+	} else {
+		builder(" ORDER BY b.id"+direction, nil)
 	}
-	builder(`) group by b.id ORDER BY b.id`, nil)
 	if options.Limit > models.ZeroValue {
 		builder(" LIMIT $", options.Limit)
 	}
@@ -209,7 +564,85 @@ func buildListQuery(options *models.BannerListOptions) (string, []any) {
 	return builder("", nil)
 }
 
-func buildUpdateQuery(banner *models.UpdateBanner) (string, []any) {
+// buildCountQuery counts banners matching filters of the listing regardless of the page
+func buildCountQuery(tenant string, options *models.BannerListOptions) (string, []any) {
+	builder := build()
+	builder(countBannersQuery, nil)
+	buildListFilter(builder, tenant, options)
+	return builder("", nil)
+}
+
+// buildListFilter lists live banners of the tenant unless options ask for the trash. Keys of deleted banners are freed,
+// so the trash is filtered by columns of banners instead of feature_tag
+func buildListFilter(builder func(str string, arg any) (string, []any), tenant string, options *models.BannerListOptions) {
+	builder(" AND b.tenant = $", tenant)
+	if options.Deleted {
+		builder(" AND b.deleted_at IS NOT NULL", nil)
+		if options.FeatureId > models.ZeroValue {
+			builder(" AND b.featureId = $", options.FeatureId)
+		}
+		if options.TagId > models.ZeroValue {
+			builder(" AND $", options.TagId)
+			builder(" = ANY(b.tagIds)", nil)
+		}
+	} else {
+		builder(" AND b.deleted_at IS NULL", nil)
+	}
+	if !options.Deleted && (options.FeatureId > models.ZeroValue || options.TagId > models.ZeroValue) {
+		builder(" AND EXISTS (SELECT 1 FROM feature_tag ft WHERE ft.bannerId = b.id", nil)
+		if options.FeatureId > models.ZeroValue {
+			builder(" AND ft.featureId = $", options.FeatureId)
+		}
+		if options.TagId > models.ZeroValue {
+			builder(" AND ft.tagId = $", options.TagId)
+		}
+		builder(")", nil)
+	}
+	if len(options.FeatureIds) > 0 {
+		builder(" AND b.featureId = ANY($", options.FeatureIds)
+		builder(")", nil)
+	}
+	if len(options.TagIds) > 0 {
+		builder(" AND b.tagIds && $", options.TagIds)
+	}
+	if options.IsActive != nil {
+		builder(" AND", nil)
+		if *options.IsActive {
+			builder(" NOT", nil)
+		}
+		builder(" EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id)", nil)
+	}
+	bounds := []struct {
+		condition string
+		value     time.Time
+	}{
+		{" AND b.created >= $", options.CreatedAfter},
+		{" AND b.created < $", options.CreatedBefore},
+		{" AND b.updated >= $", options.UpdatedAfter},
+		{" AND b.updated < $", options.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		if !bound.value.IsZero() {
+			builder(bound.condition, bound.value)
+		}
+	}
+}
+
+func buildHistoryQuery(id int, tenant string, options *models.HistoryListOptions) (string, []any) {
+	builder := build()
+	builder(selectHistoryQuery, id)
+	builder(historyTenantFilter, tenant)
+	builder(") ORDER BY h.version", nil)
+	if options.Limit > models.ZeroValue {
+		builder(" LIMIT $", options.Limit)
+	}
+	if options.Offset > models.ZeroValue {
+		builder(" OFFSET $", options.Offset)
+	}
+	return builder("", nil)
+}
+
+func buildUpdateQuery(id int, banner *models.UpdateBanner) (string, []any) {
 	builder := build()
 	var args []any
 	addComma := func() {
@@ -218,34 +651,18 @@ func buildUpdateQuery(banner *models.UpdateBanner) (string, []any) {
 		}
 	}
 	builder("UPDATE banners SET", nil)
-	if banner.Flags & ^models.FeatureBit > 0 {
+	if banner.Flags&models.FeatureBit > 0 {
 		_, args = builder(" featureId=$", banner.FeatureId)
 	}
-	addComma()
-	if banner.Flags & ^models.TagBit > 0 {
+	if banner.Flags&models.TagBit > 0 {
+		addComma()
 		_, args = builder(" tagIds=$", banner.TagIds)
 	}
-	addComma()
-	if banner.Flags & ^models.ContentBit > 0 {
+	if banner.Flags&models.ContentBit > 0 {
+		addComma()
 		_, args = builder(" content=$", Attrs(banner.Content))
 	}
-	return builder("", nil)
-}
-
-func prepareUpdateBatch(id int, banner *models.UpdateBanner) *pgx.Batch {
-	batch := &pgx.Batch{}
-	if banner.Flags&models.IsActiveBit > 0 {
-		if banner.IsActive {
-			batch.Queue(deleteBannerFromDeactivatedQuery, id)
-		} else {
-			batch.Queue(insertDeactivatedBannerQuery, id)
-		}
-	}
-	if banner.Flags & ^models.IsActiveBit > 0 {
-		query, args := buildUpdateQuery(banner)
-		batch.Queue(query, args...)
-	}
-	return batch
+	return builder(" WHERE id=$", id)
 }
 
 func build() func(str string, arg any) (string, []any) {
@@ -0,0 +1,348 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addBanner(t *testing.T, db *PostgresDatabase, featureId int, tagIds []int, content map[string]any, isActive bool) int {
+	t.Helper()
+	id, err := db.Add(context.Background(), &models.Banner{
+		BaseBanner: models.BaseBanner{UserBanner: models.UserBanner{Content: content}, FeatureId: featureId, TagIds: tagIds},
+		IsActive:   isActive,
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func updateBanner(flags int, featureId int, tagIds []int, content map[string]any, isActive bool) models.UpdateBanner {
+	return models.UpdateBanner{
+		Banner: models.Banner{
+			BaseBanner: models.BaseBanner{UserBanner: models.UserBanner{Content: content}, FeatureId: featureId, TagIds: tagIds},
+			IsActive:   isActive,
+		},
+		Flags: flags,
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	content := map[string]any{"title": "Sale"}
+	changed := map[string]any{"title": "Black Friday"}
+	tests := []struct {
+		name         string
+		missing      bool
+		update       models.UpdateBanner
+		ifMatch      string
+		wantErr      error
+		wantFeature  int
+		wantTags     []int
+		wantContent  map[string]any
+		wantActive   bool
+		wantRevision int
+	}{
+		{
+			name:         "content",
+			update:       updateBanner(models.ContentBit, 9, []int{9}, changed, false),
+			wantFeature:  1,
+			wantTags:     []int{1, 2},
+			wantContent:  changed,
+			wantActive:   true,
+			wantRevision: 2,
+		},
+		{
+			name:         "feature",
+			update:       updateBanner(models.FeatureBit, 3, []int{9}, changed, false),
+			wantFeature:  3,
+			wantTags:     []int{1, 2},
+			wantContent:  content,
+			wantActive:   true,
+			wantRevision: 2,
+		},
+		{
+			name:         "tags",
+			update:       updateBanner(models.TagBit, 9, []int{3, 4}, changed, false),
+			wantFeature:  1,
+			wantTags:     []int{3, 4},
+			wantContent:  content,
+			wantActive:   true,
+			wantRevision: 2,
+		},
+		{
+			name:         "activity",
+			update:       updateBanner(models.IsActiveBit, 9, []int{9}, changed, false),
+			wantFeature:  1,
+			wantTags:     []int{1, 2},
+			wantContent:  content,
+			wantActive:   false,
+			wantRevision: 1,
+		},
+		{
+			name:         "every field",
+			update:       updateBanner(models.FeatureBit|models.TagBit|models.ContentBit|models.IsActiveBit, 3, []int{4}, changed, false),
+			wantFeature:  3,
+			wantTags:     []int{4},
+			wantContent:  changed,
+			wantActive:   false,
+			wantRevision: 2,
+		},
+		{
+			name:         "matching etag",
+			update:       updateBanner(models.ContentBit, 0, nil, changed, false),
+			ifMatch:      models.ETag(1, true),
+			wantFeature:  1,
+			wantTags:     []int{1, 2},
+			wantContent:  changed,
+			wantActive:   true,
+			wantRevision: 2,
+		},
+		{
+			name:    "stale etag",
+			update:  updateBanner(models.ContentBit, 0, nil, changed, false),
+			ifMatch: models.ETag(1, false),
+			wantErr: e.ErrorPreconditionFailed,
+		},
+		{
+			name:    "missing banner",
+			missing: true,
+			update:  updateBanner(models.ContentBit, 0, nil, changed, false),
+			wantErr: e.ErrorNotFound,
+		},
+		{
+			name:    "feature and tag taken by another banner",
+			update:  updateBanner(models.FeatureBit|models.ContentBit|models.IsActiveBit, 2, nil, changed, false),
+			wantErr: e.ErrorConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			ctx := context.Background()
+			id := addBanner(t, db, 1, []int{1, 2}, content, true)
+			otherId := addBanner(t, db, 2, []int{1, 2}, map[string]any{"title": "Other"}, true)
+			if test.missing {
+				id = otherId + 1
+			}
+
+			err := db.Update(ctx, id, &test.update, test.ifMatch)
+
+			other, getErr := db.GetById(ctx, otherId)
+			require.NoError(t, getErr)
+			assert.Equal(t, 2, other.FeatureId, "another banner must not change")
+			assert.Equal(t, 1, other.Revision, "another banner must not change")
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				if test.missing {
+					return
+				}
+				banner, getErr := db.GetById(ctx, id)
+				require.NoError(t, getErr)
+				assert.Equal(t, content, map[string]any(banner.Content), "failed update must be rolled back")
+				assert.True(t, banner.IsActive, "failed update must be rolled back")
+				assert.Equal(t, 1, banner.Revision)
+				return
+			}
+			require.NoError(t, err)
+			banner, err := db.GetById(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantFeature, banner.FeatureId)
+			assert.Equal(t, test.wantTags, banner.TagIds)
+			assert.Equal(t, test.wantContent, map[string]any(banner.Content))
+			assert.Equal(t, test.wantActive, banner.IsActive)
+			assert.Equal(t, test.wantRevision, banner.Revision)
+		})
+	}
+}
+
+func TestPreviewDelete(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	first := addBanner(t, db, 1, []int{1, 2}, map[string]any{"title": "Sale"}, true)
+	second := addBanner(t, db, 1, []int{3}, map[string]any{"title": "Sale"}, false)
+	third := addBanner(t, db, 2, []int{2}, map[string]any{"title": "Sale"}, true)
+	_, err := db.pool.Exec(ctx, "UPDATE banners SET updated = updated - INTERVAL '2 hours' WHERE id = $1", first)
+	require.NoError(t, err)
+	hourAgo := time.Now().UTC().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  models.BulkDeleteFilter
+		wantIds []int
+	}{
+		{name: "feature", filter: models.BulkDeleteFilter{FeatureId: 1}, wantIds: []int{first, second}},
+		{name: "tag", filter: models.BulkDeleteFilter{FeatureId: models.ZeroValue, TagIds: []int{2}}, wantIds: []int{first, third}},
+		{name: "any of tags", filter: models.BulkDeleteFilter{FeatureId: models.ZeroValue, TagIds: []int{1, 3}}, wantIds: []int{first, second}},
+		{name: "feature and tag", filter: models.BulkDeleteFilter{FeatureId: 1, TagIds: []int{2}}, wantIds: []int{first}},
+		{name: "feature and any of tags", filter: models.BulkDeleteFilter{FeatureId: 1, TagIds: []int{2, 3}}, wantIds: []int{first, second}},
+		{name: "feature and tags of another feature", filter: models.BulkDeleteFilter{FeatureId: 2, TagIds: []int{1, 3}}, wantIds: []int{}},
+		{name: "inactive", filter: models.BulkDeleteFilter{FeatureId: 1, InactiveOnly: true}, wantIds: []int{second}},
+		{name: "updated before", filter: models.BulkDeleteFilter{FeatureId: models.ZeroValue, TagIds: []int{2}, UpdatedBefore: hourAgo}, wantIds: []int{first}},
+		{name: "nothing", filter: models.BulkDeleteFilter{FeatureId: 2, TagIds: []int{3}}, wantIds: []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids, err := db.PreviewDelete(ctx, &test.filter)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantIds, ids)
+		})
+	}
+}
+
+func TestDeleteByIds(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	first := addBanner(t, db, 1, []int{1, 2}, map[string]any{"title": "Sale"}, false)
+	second := addBanner(t, db, 1, []int{3}, map[string]any{"title": "Sale"}, false)
+	active := addBanner(t, db, 1, []int{4}, map[string]any{"title": "Sale"}, true)
+
+	filter := &models.BulkDeleteFilter{FeatureId: 1, TagIds: []int{2, 3, 4}, InactiveOnly: true}
+	previewed, err := db.PreviewDelete(ctx, filter)
+	require.NoError(t, err)
+	later := addBanner(t, db, 1, []int{5, 2}, map[string]any{"title": "Sale"}, false)
+
+	_, err = db.DeleteByIds(tenantContext("other"), previewed)
+	require.NoError(t, err)
+	assert.Empty(t, trashIds(t, db, models.ZeroValue, models.ZeroValue), "banners of another tenant are not deleted")
+	ids, err := db.DeleteByIds(ctx, previewed)
+	require.NoError(t, err)
+	assert.Equal(t, []int{first, second}, ids, "removed banners are reported")
+	assert.Equal(t, []int{first, second}, trashIds(t, db, models.ZeroValue, models.ZeroValue))
+	for _, id := range []int{active, later} {
+		_, err = db.GetById(ctx, id)
+		assert.NoError(t, err, "banners matching the filter after the preview are kept")
+	}
+
+	ids, err = db.DeleteByIds(ctx, previewed)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "deleted banners are not deleted again")
+}
+
+func TestInTransaction(t *testing.T) {
+	content := map[string]any{"title": "Sale"}
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	var committed, failed int
+
+	banner := &models.Banner{BaseBanner: models.BaseBanner{
+		UserBanner: models.UserBanner{Content: content}, FeatureId: 1, TagIds: []int{1},
+	}, IsActive: true}
+	err := db.InTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		committed, err = db.Add(txCtx, banner)
+		require.NoError(t, err)
+		// failed call is rolled back to its savepoint, the transaction goes on
+		_, err = db.Add(txCtx, banner)
+		require.ErrorIs(t, err, e.ErrorConflict)
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = db.GetById(ctx, committed)
+	require.NoError(t, err)
+
+	err = db.InTransaction(ctx, func(txCtx context.Context) error {
+		id, err := db.Add(txCtx, &models.Banner{BaseBanner: models.BaseBanner{
+			UserBanner: models.UserBanner{Content: content}, FeatureId: 2, TagIds: []int{2},
+		}, IsActive: true})
+		require.NoError(t, err)
+		failed = id
+		_, err = db.GetById(txCtx, id)
+		require.NoError(t, err, "changes are visible inside the transaction")
+		return e.ErrorNotFound
+	})
+	require.ErrorIs(t, err, e.ErrorNotFound)
+	_, err = db.GetById(ctx, failed)
+	require.ErrorIs(t, err, e.ErrorNotFound, "changes of failed transaction are rolled back")
+}
+
+func TestListPages(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []int
+	for i := 0; i < 5; i++ {
+		ids = append(ids, addBanner(t, db, 1, []int{i + 1}, map[string]any{"title": "Sale"}, i%2 == 0))
+	}
+	// the first banner is created last, banners are updated in the order of ids
+	for i, id := range ids {
+		_, err := db.pool.Exec(ctx, "UPDATE banners SET created = $2 WHERE id = $1", id, base.Add(time.Duration(len(ids)-i)*time.Hour))
+		require.NoError(t, err)
+	}
+	reversed := []int{ids[4], ids[3], ids[2], ids[1], ids[0]}
+	listOptions := func() models.BannerListOptions {
+		return models.BannerListOptions{
+			BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue},
+			Limit:              models.ZeroValue,
+			Offset:             models.ZeroValue,
+		}
+	}
+
+	for _, test := range []struct {
+		sort models.ListSort
+		desc bool
+		want []int
+	}{
+		{sort: models.SortById, want: ids},
+		{sort: models.SortById, desc: true, want: reversed},
+		{sort: models.SortByCreated, want: reversed},
+		{sort: models.SortByCreated, desc: true, want: ids},
+		{sort: models.SortByUpdated, want: ids},
+	} {
+		t.Run(fmt.Sprintf("%s desc %v", test.sort, test.desc), func(t *testing.T) {
+			options := listOptions()
+			options.Sort, options.Desc, options.Limit = test.sort, test.desc, 2
+			var listed []int
+			for {
+				banners, err := db.List(ctx, &options)
+				require.NoError(t, err)
+				for _, banner := range banners {
+					listed = append(listed, banner.BannerId)
+				}
+				if len(banners) < options.Limit {
+					break
+				}
+				options.After = models.CursorAfter(&banners[len(banners)-1], test.sort, test.desc)
+			}
+			assert.Equal(t, test.want, listed)
+		})
+	}
+
+	active, inactive := true, false
+	for _, test := range []struct {
+		name   string
+		filter func(options *models.BannerListOptions)
+		want   []int
+	}{
+		{name: "active", filter: func(o *models.BannerListOptions) { o.IsActive = &active }, want: []int{ids[0], ids[2], ids[4]}},
+		{name: "inactive", filter: func(o *models.BannerListOptions) { o.IsActive = &inactive }, want: []int{ids[1], ids[3]}},
+		{name: "any of tags", filter: func(o *models.BannerListOptions) { o.TagIds = []int{2, 3} }, want: []int{ids[1], ids[2]}},
+		{name: "any of features", filter: func(o *models.BannerListOptions) { o.FeatureIds = []int{1, 2} }, want: ids},
+		{name: "other features", filter: func(o *models.BannerListOptions) { o.FeatureIds = []int{2} }, want: nil},
+		{name: "created range", filter: func(o *models.BannerListOptions) {
+			o.CreatedAfter, o.CreatedBefore = base.Add(2*time.Hour), base.Add(4*time.Hour)
+		}, want: []int{ids[2], ids[3]}},
+		{name: "tag and activity", filter: func(o *models.BannerListOptions) {
+			o.TagId, o.IsActive = 3, &active
+		}, want: []int{ids[2]}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			options := listOptions()
+			test.filter(&options)
+			banners, err := db.List(ctx, &options)
+			require.NoError(t, err)
+			var listed []int
+			for _, banner := range banners {
+				listed = append(listed, banner.BannerId)
+			}
+			assert.Equal(t, test.want, listed)
+			count, err := db.Count(ctx, &options)
+			require.NoError(t, err)
+			assert.Equal(t, len(test.want), count)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// registry queries are formatted with the table name taken from models.Registry constants, entries belong to tenants.
+// Ids are unique within the tenant and generated as the next one after its largest id
+const (
+	registryColumns           = "id, name, description, owner, created, updated"
+	listRegistryQuery         = "SELECT " + registryColumns + " FROM %s WHERE tenant = $1 ORDER BY id"
+	selectRegistryQuery       = "SELECT " + registryColumns + " FROM %s WHERE id = $1 AND tenant = $2"
+	selectRegistryByNameQuery = "SELECT " + registryColumns + " FROM %s WHERE name = $1 AND tenant = $2"
+	insertRegistryQuery       = "INSERT INTO %[1]s (id, name, description, owner, tenant) SELECT COALESCE(max(id), 0) + 1, $1, $2, $3, $4 FROM %[1]s WHERE tenant = $4 RETURNING id"
+	insertRegistryWithIdQuery = "INSERT INTO %s (id, name, description, owner, tenant) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	lockRegistryQuery         = "SELECT pg_advisory_xact_lock(hashtext($1))"
+	updateRegistryQuery       = "UPDATE %s SET name = $2, description = $3, owner = $4, updated = current_timestamp WHERE id = $1 AND tenant = $5"
+	deleteRegistryQuery       = "DELETE FROM %s WHERE id = $1 AND tenant = $2"
+	missingRegistryIdsQuery   = "SELECT ARRAY_AGG(DISTINCT i) FROM unnest($1::int[]) i WHERE NOT EXISTS (SELECT 1 FROM %s r WHERE r.id = i AND r.tenant = $2)"
+)
+
+func (p PostgresDatabase) ListRegistry(ctx context.Context, registry models.Registry) ([]models.RegistryEntry, error) {
+	rows, _ := p.conn(ctx).Query(ctx, fmt.Sprintf(listRegistryQuery, registry), tenant(ctx))
+	return pgx.CollectRows(rows, scanRegistryEntry)
+}
+
+func (p PostgresDatabase) GetRegistryEntry(ctx context.Context, registry models.Registry, id int) (*models.RegistryEntry, error) {
+	rows, _ := p.conn(ctx).Query(ctx, fmt.Sprintf(selectRegistryQuery, registry), id, tenant(ctx))
+	return collectRegistryEntry(rows)
+}
+
+func (p PostgresDatabase) GetRegistryEntryByName(ctx context.Context, registry models.Registry, name string) (*models.RegistryEntry, error) {
+	rows, _ := p.conn(ctx).Query(ctx, fmt.Sprintf(selectRegistryByNameQuery, registry), name, tenant(ctx))
+	return collectRegistryEntry(rows)
+}
+
+func (p PostgresDatabase) AddRegistryEntry(ctx context.Context, registry models.Registry, entry *models.RegistryEntry) (int, error) {
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	// entries of the tenant are added one after another, so generated ids don't collide
+	if _, err = tx.Exec(ctx, lockRegistryQuery, string(registry)+":"+tenant(ctx)); err != nil {
+		return 0, err
+	}
+	var id int
+	if entry.Id > models.ZeroValue {
+		err = tx.QueryRow(ctx, fmt.Sprintf(insertRegistryWithIdQuery, registry), entry.Id, entry.Name, entry.Description, entry.Owner, tenant(ctx)).Scan(&id)
+	} else {
+		err = tx.QueryRow(ctx, fmt.Sprintf(insertRegistryQuery, registry), entry.Name, entry.Description, entry.Owner, tenant(ctx)).Scan(&id)
+	}
+	if err != nil {
+		return 0, registryError(err)
+	}
+	return id, tx.Commit(ctx)
+}
+
+func (p PostgresDatabase) UpdateRegistryEntry(ctx context.Context, registry models.Registry, id int, entry *models.RegistryEntry) error {
+	tag, err := p.conn(ctx).Exec(ctx, fmt.Sprintf(updateRegistryQuery, registry), id, entry.Name, entry.Description, entry.Owner, tenant(ctx))
+	if err != nil {
+		return registryError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func (p PostgresDatabase) DeleteRegistryEntry(ctx context.Context, registry models.Registry, id int) error {
+	tag, err := p.conn(ctx).Exec(ctx, fmt.Sprintf(deleteRegistryQuery, registry), id, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+// MissingRegistryIds returns ids which are not registered
+func (p PostgresDatabase) MissingRegistryIds(ctx context.Context, registry models.Registry, ids ...int) ([]int, error) {
+	var missing []int
+	err := p.conn(ctx).QueryRow(ctx, fmt.Sprintf(missingRegistryIdsQuery, registry), ids, tenant(ctx)).Scan(&missing)
+	return missing, err
+}
+
+func collectRegistryEntry(rows pgx.Rows) (*models.RegistryEntry, error) {
+	entry, err := pgx.CollectOneRow(rows, scanRegistryEntry)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func scanRegistryEntry(row pgx.CollectableRow) (models.RegistryEntry, error) {
+	res := models.RegistryEntry{}
+	err := row.Scan(&res.Id, &res.Name, &res.Description, &res.Owner, &res.CreatedAt, &res.UpdatedAt)
+	return res, err
+}
+
+func registryError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return e.ErrorNameConflict
+	}
+	return err
+}
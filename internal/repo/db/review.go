@@ -0,0 +1,194 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// stateColumns are the state of the banner in the approval workflow and whether users get it
+	stateColumns = `COALESCE((SELECT r.state FROM banner_reviews r WHERE r.bannerId = b.id),
+    CASE WHEN EXISTS (SELECT 1 FROM unpublished u WHERE u.bannerId = b.id) THEN 'archived' ELSE 'published' END) state,
+    NOT EXISTS (SELECT 1 FROM unpublished u WHERE u.bannerId = b.id) live`
+	reviewColumns     = "r.bannerId, r.featureId, r.tagIds, r.content, r.is_active, r.state, r.author, r.reviewer, r.comment, r.updated"
+	selectReviewQuery = "SELECT " + reviewColumns + ` FROM banner_reviews r JOIN banners b ON b.id = r.bannerId
+	WHERE r.bannerId = $1 AND b.tenant = $2 AND b.deleted_at IS NULL`
+	listReviewsQuery = "SELECT " + reviewColumns + ` FROM banner_reviews r JOIN banners b ON b.id = r.bannerId
+	WHERE b.deleted_at IS NULL AND b.tenant = $`
+	selectCurrentRevisionQuery = `SELECT b.id, b.featureId, b.tagIds, b.content,
+    NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id) is_active,
+    CASE WHEN EXISTS (SELECT 1 FROM unpublished u WHERE u.bannerId = b.id) THEN 'archived' ELSE 'published' END state,
+    b.actor, ''::TEXT, ''::TEXT, b.updated
+	FROM banners b WHERE b.id = $1 AND b.tenant = $2 AND b.deleted_at IS NULL`
+	upsertReviewQuery = `INSERT INTO banner_reviews (bannerId, featureId, tagIds, content, is_active, state, author, reviewer, comment)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (bannerId) DO UPDATE SET featureId = excluded.featureId,
+	tagIds = excluded.tagIds, content = excluded.content, is_active = excluded.is_active, state = excluded.state,
+	author = excluded.author, reviewer = excluded.reviewer, comment = excluded.comment, updated = current_timestamp`
+	deleteReviewQuery      = "DELETE FROM banner_reviews WHERE bannerId = $1"
+	insertUnpublishedQuery = "INSERT INTO unpublished (bannerId) VALUES ($1) ON CONFLICT DO NOTHING"
+	deleteUnpublishedQuery = "DELETE FROM unpublished WHERE bannerId = $1"
+	// the unchanged banner is not updated, so publishing the first draft doesn't add a version to the history
+	publishRevisionQuery = `UPDATE banners SET featureId = $2, tagIds = $3, content = $4
+	WHERE id = $1 AND (featureId IS DISTINCT FROM $2 OR tagIds IS DISTINCT FROM $3 OR content IS DISTINCT FROM $4)`
+)
+
+// AddDraft adds the banner which users don't get until its pending revision is published
+func (p PostgresDatabase) AddDraft(ctx context.Context, banner *models.Banner) (int, error) {
+	if err := p.pool.Ping(ctx); err != nil {
+		return 0, e.ErrorFailedToConnect
+	}
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	if err = setActor(ctx, tx); err != nil {
+		return 0, err
+	}
+	id, err := insertBanner(ctx, tx, banner)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = tx.Exec(ctx, insertUnpublishedQuery, id); err != nil {
+		return 0, err
+	}
+	revision := &models.PendingRevision{BannerId: id, Banner: *banner, State: models.StateDraft, Author: identity.From(ctx).Actor}
+	if err = saveRevision(ctx, tx, revision); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+// GetRevision returns the pending revision of the banner or the revision of its current state when there is none
+func (p PostgresDatabase) GetRevision(ctx context.Context, id int) (*models.PendingRevision, error) {
+	return loadRevision(ctx, p.conn(ctx), id, "")
+}
+
+// ChangeRevision locks the banner and passes its revision to change, the changed revision is stored by its state:
+// pending states are saved for review, published one replaces the banner and archived one hides it from users
+func (p PostgresDatabase) ChangeRevision(ctx context.Context, id int, ifMatch string, change func(revision *models.PendingRevision) error) error {
+	if p.pool.Ping(ctx) != nil {
+		return e.ErrorFailedToConnect
+	}
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err = lockBanner(ctx, tx, id, ifMatch); err != nil {
+		return err
+	}
+	revision, err := loadRevision(ctx, tx, id, " FOR UPDATE OF r")
+	if err != nil {
+		return err
+	}
+	if err = change(revision); err != nil {
+		return err
+	}
+	switch revision.State {
+	case models.StatePublished:
+		err = publishRevision(ctx, tx, revision)
+	case models.StateArchived:
+		if _, err = tx.Exec(ctx, deleteReviewQuery, id); err == nil {
+			_, err = tx.Exec(ctx, insertUnpublishedQuery, id)
+		}
+		if err == nil {
+			err = enqueueWebhooks(ctx, tx, models.WebhookUpdated, id)
+		}
+	default:
+		err = saveRevision(ctx, tx, revision)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ListReviews returns pending revisions of live and unpublished banners of the tenant, deleted banners are skipped
+func (p PostgresDatabase) ListReviews(ctx context.Context, options *models.ReviewListOptions) ([]models.PendingRevision, error) {
+	if p.pool.Ping(ctx) != nil {
+		return nil, e.ErrorFailedToConnect
+	}
+	builder := build()
+	builder(listReviewsQuery, tenant(ctx))
+	builder(" AND r.state = $", options.State)
+	builder(" ORDER BY r.updated, r.bannerId", nil)
+	if options.Limit > 0 {
+		builder(" LIMIT $", options.Limit)
+	}
+	if options.Offset > 0 {
+		builder(" OFFSET $", options.Offset)
+	}
+	query, args := builder("", nil)
+	rows, _ := p.conn(ctx).Query(ctx, query, args...)
+	return pgx.CollectRows(rows, scanRevision)
+}
+
+// loadRevision reads the pending revision of the banner, the revision of the current state is made when there is none.
+// Lock is appended to the query of the pending revision, the banner itself is locked by the caller
+func loadRevision(ctx context.Context, q querier, id int, lock string) (*models.PendingRevision, error) {
+	rows, _ := q.Query(ctx, selectReviewQuery+lock, id, tenant(ctx))
+	revision, err := pgx.CollectOneRow(rows, scanRevision)
+	if errors.Is(err, pgx.ErrNoRows) {
+		rows, _ = q.Query(ctx, selectCurrentRevisionQuery, id, tenant(ctx))
+		revision, err = pgx.CollectOneRow(rows, scanRevision)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func saveRevision(ctx context.Context, tx pgx.Tx, revision *models.PendingRevision) error {
+	_, err := tx.Exec(ctx, upsertReviewQuery, revision.BannerId, revision.FeatureId, revision.TagIds, Attrs(revision.Content),
+		revision.IsActive, revision.State, revision.Author, revision.Reviewer, revision.Comment)
+	return err
+}
+
+// publishRevision replaces the banner with the revision, the version is recorded in the history as made by its author.
+// Webhooks are notified about the update and about the activation of the banner
+func publishRevision(ctx context.Context, tx pgx.Tx, revision *models.PendingRevision) error {
+	if _, err := tx.Exec(ctx, setActorQuery, revision.Author); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, publishRevisionQuery, revision.BannerId, revision.FeatureId, revision.TagIds, Attrs(revision.Content))
+	if err != nil {
+		return conflictError(err)
+	}
+	query := insertDeactivatedBannerQuery
+	if revision.IsActive {
+		query = deleteBannerFromDeactivatedQuery
+	}
+	activated := false
+	for _, query := range []string{query, deleteReviewQuery, deleteUnpublishedQuery} {
+		tag, err := tx.Exec(ctx, query, revision.BannerId)
+		if err != nil {
+			return err
+		}
+		activated = activated || query == deleteBannerFromDeactivatedQuery && tag.RowsAffected() > 0
+	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookUpdated, revision.BannerId); err != nil {
+		return err
+	}
+	if activated {
+		return enqueueWebhooks(ctx, tx, models.WebhookActivated, revision.BannerId)
+	}
+	return nil
+}
+
+func scanRevision(row pgx.CollectableRow) (models.PendingRevision, error) {
+	res := models.PendingRevision{}
+	attr := make(Attrs)
+	err := row.Scan(&res.BannerId, &res.FeatureId, &res.TagIds, &attr, &res.IsActive, &res.State, &res.Author, &res.Reviewer,
+		&res.Comment, &res.UpdatedAt)
+	res.Content = attr
+	return res, err
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func moveRevision(t *testing.T, db *PostgresDatabase, id int, state models.BannerState) {
+	t.Helper()
+	require.NoError(t, db.ChangeRevision(context.Background(), id, "", func(revision *models.PendingRevision) error {
+		revision.State = state
+		return nil
+	}))
+}
+
+func TestApprovalWorkflow(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := identity.With(context.Background(), &identity.Identity{Actor: "author"})
+	draft := &models.Banner{
+		BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{1, 2}, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		IsActive:   true,
+	}
+	id, err := db.AddDraft(ctx, draft)
+	require.NoError(t, err)
+
+	banner, err := db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateDraft, banner.State)
+	assert.False(t, banner.Live, "draft of the new banner is not shown to users")
+	revision, err := db.GetRevision(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "author", revision.Author)
+	assert.Equal(t, draft.Content, revision.Content)
+
+	moveRevision(t, db, id, models.StateInReview)
+	reviews, err := db.ListReviews(ctx, &models.ReviewListOptions{State: models.StateInReview})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, id, reviews[0].BannerId)
+	reviews, err = db.ListReviews(ctx, &models.ReviewListOptions{State: models.StateDraft})
+	require.NoError(t, err)
+	assert.Empty(t, reviews)
+
+	moveRevision(t, db, id, models.StateApproved)
+	moveRevision(t, db, id, models.StatePublished)
+	banner, err = db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatePublished, banner.State)
+	assert.True(t, banner.Live)
+	assert.True(t, banner.IsActive)
+	revision, err = db.GetRevision(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatePublished, revision.State, "nothing is pending after publishing")
+
+	// the edit is pending while users get the published banner
+	require.NoError(t, db.ChangeRevision(ctx, id, "", func(revision *models.PendingRevision) error {
+		revision.Content = map[string]any{"title": "Black Friday"}
+		revision.State = models.StateDraft
+		return nil
+	}))
+	banner, err = db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateDraft, banner.State)
+	assert.True(t, banner.Live)
+	assert.Equal(t, "Sale", banner.Content["title"])
+
+	moveRevision(t, db, id, models.StatePublished)
+	banner, err = db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Black Friday", banner.Content["title"])
+	history, err := db.GetHistoryForId(ctx, id, &models.HistoryListOptions{Limit: models.ZeroValue, Offset: models.ZeroValue})
+	require.NoError(t, err)
+	assert.Len(t, history, 2, "publishing the first draft doesn't add a version")
+
+	moveRevision(t, db, id, models.StateArchived)
+	banner, err = db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateArchived, banner.State)
+	assert.False(t, banner.Live)
+
+	require.NoError(t, db.DeleteById(ctx, id, ""))
+	_, err = db.GetRevision(ctx, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// search words are parsed like in web search engines: quoted phrases, "or" and "-" to exclude a word
+	searchTextQuery    = "WITH q AS (SELECT websearch_to_tsquery('simple', $"
+	searchBannersQuery = `) query) SELECT b.id, b.content, b.created, b.updated, b.featureId, b.tagIds, b.revision,
+    NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id) is_active, ` + stateColumns + `, ts_rank(b.search, q.query) rank
+	FROM banners b, q WHERE b.search @@ q.query`
+	searchAllBannersQuery = `SELECT b.id, b.content, b.created, b.updated, b.featureId, b.tagIds, b.revision,
+    NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id) is_active, ` + stateColumns + `, 0::REAL rank
+	FROM banners b WHERE TRUE`
+	countSearchQuery    = ") query) SELECT COUNT(*) FROM banners b, q WHERE b.search @@ q.query"
+	countAllSearchQuery = "SELECT COUNT(*) FROM banners b WHERE TRUE"
+)
+
+// Search returns banners matching options ordered by rank, banners of the same rank are ordered by id.
+// Without the query every banner matching predicates has the zero rank
+func (p PostgresDatabase) Search(ctx context.Context, options *models.BannerSearchOptions) ([]models.SearchHit, error) {
+	if p.pool.Ping(ctx) != nil {
+		return nil, e.ErrorFailedToConnect
+	}
+	query, args := buildSearchQuery(tenant(ctx), options)
+	rows, _ := p.conn(ctx).Query(ctx, query, args...)
+	return pgx.CollectRows(rows, scanSearchHit)
+}
+
+// CountSearch returns the number of banners matching options regardless of the page
+func (p PostgresDatabase) CountSearch(ctx context.Context, options *models.BannerSearchOptions) (int, error) {
+	builder := build()
+	if options.Query != "" {
+		builder(searchTextQuery, options.Query)
+		builder(countSearchQuery, nil)
+	} else {
+		builder(countAllSearchQuery, nil)
+	}
+	buildSearchFilter(builder, tenant(ctx), options)
+	query, args := builder("", nil)
+	var count int
+	err := p.conn(ctx).QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func scanSearchHit(row pgx.CollectableRow) (models.SearchHit, error) {
+	res := models.SearchHit{}
+	attr := make(Attrs)
+	err := row.Scan(&res.BannerId, &attr, &res.CreatedAt, &res.UpdatedAt, &res.FeatureId, &res.TagIds, &res.Revision,
+		&res.IsActive, &res.State, &res.Live, &res.Rank)
+	res.Content = attr
+	return res, err
+}
+
+// buildSearchQuery pages after the cursor like the listing, ranks are compared as REAL returned by ts_rank
+func buildSearchQuery(tenant string, options *models.BannerSearchOptions) (string, []any) {
+	builder := build()
+	if options.Query != "" {
+		builder(searchTextQuery, options.Query)
+		builder(searchBannersQuery, nil)
+	} else {
+		builder(searchAllBannersQuery, nil)
+	}
+	buildSearchFilter(builder, tenant, options)
+	if after := options.After; after != nil {
+		if options.Query != "" {
+			builder(" AND (ts_rank(b.search, q.query) < $", after.Rank)
+			builder(" OR ts_rank(b.search, q.query) = $", after.Rank)
+			builder(" AND b.id > $", after.Id)
+			builder(")", nil)
+		} else {
+			builder(" AND b.id > $", after.Id)
+		}
+	}
+	if options.Query != "" {
+		builder(" ORDER BY rank DESC, b.id", nil)
+	} else {
+		builder(" ORDER BY b.id", nil)
+	}
+	if options.Limit > models.ZeroValue {
+		builder(" LIMIT $", options.Limit)
+	}
+	return builder("", nil)
+}
+
+// buildSearchFilter adds filters of the listing and predicates on the content. Paths are passed as TEXT[],
+// so keys never get into the query
+func buildSearchFilter(builder func(str string, arg any) (string, []any), tenant string, options *models.BannerSearchOptions) {
+	buildListFilter(builder, tenant, &options.BannerListOptions)
+	for _, predicate := range options.Predicates {
+		switch predicate.Op {
+		case models.PredicateEq:
+			builder(" AND b.content #>> $", predicate.Path)
+			builder("::TEXT[] = $", predicate.Value)
+			builder("::TEXT", nil)
+		case models.PredicateContains:
+			builder(" AND strpos(lower(b.content #>> $", predicate.Path)
+			builder("::TEXT[]), lower($", predicate.Value)
+			builder("::TEXT)) > 0", nil)
+		case models.PredicateExists:
+			builder(" AND b.content #> $", predicate.Path)
+			builder("::TEXT[] IS NOT NULL", nil)
+		}
+	}
+}
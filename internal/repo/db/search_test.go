@@ -0,0 +1,107 @@
+package db
+
+import (
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchOptions(query string, predicates ...models.ContentPredicate) *models.BannerSearchOptions {
+	return &models.BannerSearchOptions{
+		BannerListOptions: models.BannerListOptions{
+			BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue},
+			Limit:              models.ZeroValue,
+			Offset:             models.ZeroValue,
+		},
+		Query:      query,
+		Predicates: predicates,
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	often := addBanner(t, db, 1, []int{1}, map[string]any{
+		"title": "Black Friday",
+		"text":  "Black Friday prices for Black Friday only",
+		"cta":   map[string]any{"url": "https://example.com/black-friday", "count": 3},
+	}, true)
+	once := addBanner(t, db, 2, []int{1}, map[string]any{"title": "Black Friday sale", "count": 2}, false)
+	other := addBanner(t, db, 1, []int{2}, map[string]any{"title": "Cyber Monday", "text": "Friday is over"}, true)
+
+	for _, test := range []struct {
+		name    string
+		options *models.BannerSearchOptions
+		want    []int
+	}{
+		{name: "ranked by words", options: searchOptions("black friday"), want: []int{often, once}},
+		{name: "case and phrase", options: searchOptions(`"BLACK FRIDAY"`), want: []int{often, once}},
+		{name: "excluded word", options: searchOptions("friday -black"), want: []int{other}},
+		{name: "either word", options: searchOptions("sale or monday"), want: []int{once, other}},
+		{name: "nothing found", options: searchOptions("easter"), want: nil},
+		{name: "keys are not words", options: searchOptions("title"), want: nil},
+		{name: "contains ignores case", options: searchOptions("",
+			models.ContentPredicate{Path: []string{"title"}, Op: models.PredicateContains, Value: "friday"}), want: []int{often, once}},
+		{name: "nested value", options: searchOptions("",
+			models.ContentPredicate{Path: []string{"cta", "url"}, Op: models.PredicateEq, Value: "https://example.com/black-friday"}), want: []int{often}},
+		{name: "number as text", options: searchOptions("",
+			models.ContentPredicate{Path: []string{"count"}, Op: models.PredicateEq, Value: "2"}), want: []int{once}},
+		{name: "exists", options: searchOptions("",
+			models.ContentPredicate{Path: []string{"text"}, Op: models.PredicateExists}), want: []int{often, other}},
+		{name: "words and predicates", options: searchOptions("friday",
+			models.ContentPredicate{Path: []string{"text"}, Op: models.PredicateExists},
+			models.ContentPredicate{Path: []string{"title"}, Op: models.PredicateContains, Value: "monday"}), want: []int{other}},
+		{name: "filters of the listing", options: func() *models.BannerSearchOptions {
+			options := searchOptions("friday")
+			options.FeatureId = 1
+			return options
+		}(), want: []int{often, other}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			hits, err := db.Search(ctx, test.options)
+			require.NoError(t, err)
+			var found []int
+			for _, hit := range hits {
+				found = append(found, hit.BannerId)
+			}
+			assert.Equal(t, test.want, found)
+			count, err := db.CountSearch(ctx, test.options)
+			require.NoError(t, err)
+			assert.Equal(t, len(test.want), count)
+		})
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		for _, test := range []struct {
+			options *models.BannerSearchOptions
+			sort    models.ListSort
+			want    []int
+		}{
+			{options: searchOptions("friday"), sort: models.SortByRank, want: []int{often, once, other}},
+			{options: searchOptions("", models.ContentPredicate{Path: []string{"title"}, Op: models.PredicateExists}),
+				sort: models.SortById, want: []int{often, once, other}},
+		} {
+			options := test.options
+			options.Limit = 1
+			var found []int
+			var ranks []float32
+			for {
+				hits, err := db.Search(ctx, options)
+				require.NoError(t, err)
+				for _, hit := range hits {
+					found = append(found, hit.BannerId)
+					ranks = append(ranks, hit.Rank)
+				}
+				if len(hits) < options.Limit {
+					break
+				}
+				options.After = models.CursorAfterHit(&hits[len(hits)-1], test.sort)
+			}
+			assert.Equal(t, test.want, found)
+			assert.IsNonIncreasing(t, ranks)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	targetingColumns     = "t.bannerId, t.priority, t.rule"
+	selectTargetingQuery = "SELECT " + targetingColumns + ` FROM banner_targeting t
+	JOIN banners b ON b.id = t.bannerId WHERE t.bannerId = $1 AND b.tenant = $2 AND b.deleted_at IS NULL`
+	listTargetingQuery = "SELECT " + targetingColumns + ` FROM banner_targeting t
+	JOIN banners b ON b.id = t.bannerId WHERE t.bannerId = ANY($1) AND b.tenant = $2`
+	upsertTargetingQuery = `INSERT INTO banner_targeting (bannerId, priority, rule)
+	SELECT b.id, $2, $3 FROM banners b WHERE b.id = $1 AND b.tenant = $4 AND b.deleted_at IS NULL
+	ON CONFLICT (bannerId) DO UPDATE SET priority = EXCLUDED.priority, rule = EXCLUDED.rule`
+	deleteTargetingQuery = `DELETE FROM banner_targeting t USING banners b
+	WHERE b.id = t.bannerId AND t.bannerId = $1 AND b.tenant = $2 AND b.deleted_at IS NULL`
+)
+
+// GetTargeting returns the targeting of the banner, e.ErrorNotFound is returned for banners without it
+func (p PostgresDatabase) GetTargeting(ctx context.Context, id int) (*models.Targeting, error) {
+	rows, _ := p.conn(ctx).Query(ctx, selectTargetingQuery, id, tenant(ctx))
+	targeting, err := pgx.CollectOneRow(rows, scanTargeting)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &targeting, nil
+}
+
+// ListTargeting returns targeting of the banners having it in no particular order
+func (p PostgresDatabase) ListTargeting(ctx context.Context, ids []int) ([]models.Targeting, error) {
+	rows, _ := p.conn(ctx).Query(ctx, listTargetingQuery, ids, tenant(ctx))
+	return pgx.CollectRows(rows, scanTargeting)
+}
+
+// PutTargeting sets or replaces the targeting of the banner, it doesn't change the revision of the banner
+func (p PostgresDatabase) PutTargeting(ctx context.Context, targeting *models.Targeting) error {
+	tag, err := p.conn(ctx).Exec(ctx, upsertTargetingQuery, targeting.BannerId, targeting.Priority, targeting.Rule, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func (p PostgresDatabase) DeleteTargeting(ctx context.Context, id int) error {
+	tag, err := p.conn(ctx).Exec(ctx, deleteTargetingQuery, id, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func scanTargeting(row pgx.CollectableRow) (models.Targeting, error) {
+	res := models.Targeting{}
+	err := row.Scan(&res.BannerId, &res.Priority, &res.Rule)
+	return res, err
+}
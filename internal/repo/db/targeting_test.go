@@ -0,0 +1,46 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargeting(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx, other := tenantContext(""), tenantContext("other")
+	id, err := db.Add(ctx, &models.Banner{
+		BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{1}, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		IsActive:   true,
+	})
+	require.NoError(t, err)
+	_, err = db.GetTargeting(ctx, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+
+	targeting := &models.Targeting{BannerId: id, Priority: 5, Rule: models.TargetingRule{Locales: []string{"ru"}, MinVersion: "1.2"}}
+	require.NoError(t, db.PutTargeting(ctx, targeting))
+	targeting.Priority = 7
+	require.NoError(t, db.PutTargeting(ctx, targeting), "targeting is replaced")
+	got, err := db.GetTargeting(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, targeting, got)
+	listed, err := db.ListTargeting(ctx, []int{id, id + 1})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Targeting{*targeting}, listed)
+	banner, err := db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, banner.Revision, "targeting is not a version of the banner")
+
+	assert.ErrorIs(t, db.PutTargeting(other, targeting), e.ErrorNotFound)
+	assert.ErrorIs(t, db.DeleteTargeting(other, id), e.ErrorNotFound)
+	listed, err = db.ListTargeting(other, []int{id})
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	require.NoError(t, db.DeleteTargeting(ctx, id))
+	assert.ErrorIs(t, db.DeleteTargeting(ctx, id), e.ErrorNotFound)
+	assert.ErrorIs(t, db.PutTargeting(ctx, &models.Targeting{BannerId: id + 1}), e.ErrorNotFound)
+}
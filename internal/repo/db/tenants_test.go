@@ -0,0 +1,104 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tenantContext(tenant string) context.Context {
+	return identity.With(context.Background(), &identity.Identity{Actor: "admin", IsAdmin: true, Tenant: tenant})
+}
+
+func TestTenantIsolation(t *testing.T) {
+	db := newTestDatabase(t)
+	first, second := tenantContext("first"), tenantContext("second")
+	banner := &models.Banner{
+		BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{1, 2}, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		IsActive:   true,
+	}
+	id, err := db.Add(first, banner)
+	require.NoError(t, err)
+	otherId, err := db.Add(second, banner)
+	require.NoError(t, err, "keys are unique within the tenant")
+
+	listed, err := db.List(second, &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: 1, TagId: 2},
+		Limit:              models.ZeroValue,
+		Offset:             models.ZeroValue,
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, otherId, listed[0].BannerId)
+	assert.Empty(t, findBanner(t, db, 1, 2), "the default tenant has no banners")
+
+	update := updateBanner(models.ContentBit, 0, nil, map[string]any{"title": "Black Friday"}, true)
+	_, err = db.GetById(second, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+	assert.ErrorIs(t, db.Update(second, id, &update, ""), e.ErrorNotFound)
+	assert.ErrorIs(t, db.DeleteById(second, id, ""), e.ErrorNotFound)
+	_, err = db.GetRevision(second, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+	history, err := db.GetHistoryForId(second, id, &models.HistoryListOptions{Limit: models.ZeroValue, Offset: models.ZeroValue})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	ids, err := db.PreviewDelete(second, &models.BulkDeleteFilter{FeatureId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int{otherId}, ids)
+
+	require.NoError(t, db.DeleteById(first, id, ""))
+	assert.ErrorIs(t, db.Restore(second, id), e.ErrorNotFound)
+	require.NoError(t, db.Restore(first, id))
+	count, err := db.Count(first, &models.BannerListOptions{BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestTenantRegistries(t *testing.T) {
+	db := newTestDatabase(t)
+	first, second := tenantContext("first"), tenantContext("second")
+	_, err := db.AddRegistryEntry(first, models.Features, &models.RegistryEntry{Id: models.ZeroValue, Name: "main_page"})
+	require.NoError(t, err)
+	id, err := db.AddRegistryEntry(first, models.Features, &models.RegistryEntry{Id: models.ZeroValue, Name: "catalog"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, id)
+	secondId, err := db.AddRegistryEntry(second, models.Features, &models.RegistryEntry{Id: models.ZeroValue, Name: "main_page"})
+	require.NoError(t, err, "names are unique within the tenant")
+	assert.Equal(t, 1, secondId, "ids are generated within the tenant")
+
+	_, err = db.GetRegistryEntry(second, models.Features, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+	missing, err := db.MissingRegistryIds(second, models.Features, id)
+	require.NoError(t, err)
+	assert.Equal(t, []int{id}, missing)
+	assert.ErrorIs(t, db.DeleteRegistryEntry(second, models.Features, id), e.ErrorNotFound)
+	entries, err := db.ListRegistry(first, models.Features)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, id, entries[1].Id)
+
+	// the id of another tenant is registered as a free one, the taken id of the tenant is a conflict
+	_, err = db.AddRegistryEntry(second, models.Features, &models.RegistryEntry{Id: 5, Name: "profile"})
+	require.NoError(t, err)
+	registered, err := db.AddRegistryEntry(second, models.Features, &models.RegistryEntry{Id: id, Name: "catalog"})
+	require.NoError(t, err)
+	assert.Equal(t, id, registered)
+	_, err = db.AddRegistryEntry(first, models.Features, &models.RegistryEntry{Id: 5, Name: "profile"})
+	require.NoError(t, err)
+	_, err = db.AddRegistryEntry(first, models.Features, &models.RegistryEntry{Id: 5, Name: "search"})
+	assert.ErrorIs(t, err, e.ErrorNameConflict)
+	generated, err := db.AddRegistryEntry(second, models.Features, &models.RegistryEntry{Id: models.ZeroValue, Name: "search"})
+	require.NoError(t, err)
+	assert.Equal(t, 6, generated, "generated ids follow the registered ones")
+
+	schema := &models.FeatureSchema{FeatureId: id, Schema: map[string]any{"type": "object"}}
+	require.NoError(t, db.PutFeatureSchema(first, schema))
+	_, err = db.GetFeatureSchema(second, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+	assert.ErrorIs(t, db.DeleteFeatureSchema(second, id), e.ErrorNotFound)
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	importTable            = "import_banners"
+	importKeysField        = "tag_ids"
+	createImportTableQuery = `CREATE TEMP TABLE import_banners (
+    line INT PRIMARY KEY, featureId INT, tagIds INT[], content JSONB, is_active BOOLEAN, tenant TEXT NOT NULL,
+    bannerId INT, action TEXT NOT NULL DEFAULT 'create') ON COMMIT DROP`
+	selectImportOverlapsQuery = `SELECT i.line, MIN(o.line) FROM import_banners i
+    JOIN import_banners o ON o.featureId = i.featureId AND o.tagIds && i.tagIds AND o.line < i.line
+	GROUP BY i.line`
+	selectImportMatchesQuery = `SELECT i.line, ARRAY_AGG(DISTINCT ft.bannerId ORDER BY ft.bannerId) FROM import_banners i
+    JOIN feature_tag ft ON ft.tenant = i.tenant AND ft.featureId = i.featureId AND ft.tagId = ANY(i.tagIds)
+	GROUP BY i.line ORDER BY i.line`
+	selectImportedQuery = `SELECT bannerId, action FROM import_banners
+    WHERE action <> 'skip' ORDER BY bannerId`
+	setImportActionsQuery = `UPDATE import_banners i SET action = a.action, bannerId = a.bannerId
+    FROM unnest($1::INT[], $2::TEXT[], $3::INT[]) a(line, action, bannerId) WHERE i.line = a.line`
+	importUpdate = "update"
+	importSkip   = "skip"
+)
+
+var importColumns = []string{"line", "featureid", "tagids", "content", "is_active", "tenant"}
+
+// importStatements apply actions planned in import_banners. Ids of created banners are taken
+// from the sequence beforehand, so their activity is set the same way as of updated ones
+var importStatements = []string{
+	`UPDATE import_banners SET bannerId = nextval(pg_get_serial_sequence('banners', 'id')) WHERE action = 'create'`,
+	`INSERT INTO banners (id, content, tagIds, featureId, tenant)
+    SELECT bannerId, content, tagIds, featureId, tenant FROM import_banners WHERE action = 'create' ORDER BY line`,
+	`UPDATE banners b SET content = i.content, tagIds = i.tagIds, featureId = i.featureId FROM import_banners i
+    WHERE i.action = 'update' AND b.id = i.bannerId
+    AND (b.content, b.tagIds, b.featureId) IS DISTINCT FROM (i.content, i.tagIds, i.featureId)`,
+	`DELETE FROM deactivated d USING import_banners i WHERE i.action <> 'skip' AND i.is_active AND d.bannerId = i.bannerId`,
+	`INSERT INTO deactivated (bannerId) SELECT bannerId FROM import_banners WHERE action <> 'skip' AND NOT is_active
+    ON CONFLICT DO NOTHING`,
+}
+
+// Import copies records into a temporary table and applies them with a few statements. next returns nil record
+// after the last one. A record matching an existing banner by feature and any of tags updates or skips it
+// depending on mode, records matching several banners, a banner of a previous record or sharing keys with
+// a previous record are rejected. Nothing is applied if report has failed records or more records than
+// MaxCreated of options would create banners, dry run is rolled back
+func (p PostgresDatabase) Import(ctx context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions, report *models.ImportReport) error {
+	if p.pool.Ping(ctx) != nil {
+		return e.ErrorFailedToConnect
+	}
+	tx, err := p.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err = setActor(ctx, tx); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, createImportTableQuery); err != nil {
+		return err
+	}
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{importTable}, importColumns, pgx.CopyFromFunc(func() ([]any, error) {
+		record, err := next()
+		if record == nil || err != nil {
+			return nil, err
+		}
+		return []any{record.Line, record.FeatureId, record.TagIds, Attrs(record.Content), record.IsActive, tenant(ctx)}, nil
+	}))
+	if err != nil {
+		return err
+	}
+	rejected, err := rejectOverlaps(ctx, tx)
+	if err != nil {
+		return err
+	}
+	lines, actions, bannerIds, err := planImport(ctx, tx, options.Mode, rejected)
+	if err != nil {
+		return err
+	}
+	for _, action := range actions {
+		if action == importUpdate {
+			report.Updated++
+		} else {
+			report.Skipped++
+		}
+	}
+	report.Created = int(copied) - len(rejected) - len(actions)
+	report.Failed += len(rejected)
+	for line, message := range rejected {
+		report.Errors = append(report.Errors, models.RecordError{
+			Line:       line,
+			Violations: []e.FieldViolation{{Field: importKeysField, Message: message}},
+		})
+	}
+	if report.Failed > 0 {
+		return nil
+	}
+	if options.MaxCreated != models.ZeroValue && report.Created > options.MaxCreated {
+		return e.ErrorQuotaExceeded
+	}
+	if _, err = tx.Exec(ctx, setImportActionsQuery, lines, actions, bannerIds); err != nil {
+		return err
+	}
+	for _, statement := range importStatements {
+		if _, err = tx.Exec(ctx, statement); err != nil {
+			return conflictError(err)
+		}
+	}
+	if err = enqueueImported(ctx, tx); err != nil {
+		return err
+	}
+	if options.DryRun {
+		return nil
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+	report.Applied = true
+	return nil
+}
+
+// enqueueImported notifies webhooks about created banners and about every updated one, even if nothing changed
+func enqueueImported(ctx context.Context, tx pgx.Tx) error {
+	var created, updated []int
+	var id int
+	var action string
+	rows, _ := tx.Query(ctx, selectImportedQuery)
+	_, err := pgx.ForEachRow(rows, []any{&id, &action}, func() error {
+		if action == importUpdate {
+			updated = append(updated, id)
+		} else {
+			created = append(created, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = enqueueWebhooks(ctx, tx, models.WebhookCreated, created...); err != nil {
+		return err
+	}
+	return enqueueWebhooks(ctx, tx, models.WebhookUpdated, updated...)
+}
+
+// rejectOverlaps returns messages of records sharing feature and tag with a previous record by their lines
+func rejectOverlaps(ctx context.Context, tx pgx.Tx) (map[int]string, error) {
+	rejected := make(map[int]string)
+	var line, previous int
+	rows, _ := tx.Query(ctx, selectImportOverlapsQuery)
+	_, err := pgx.ForEachRow(rows, []any{&line, &previous}, func() error {
+		rejected[line] = fmt.Sprintf("feature and tag are already used by line %d", previous)
+		return nil
+	})
+	return rejected, err
+}
+
+// planImport chooses action of every record matching an existing banner, records left are created.
+// Records which can not be imported are added to rejected
+func planImport(ctx context.Context, tx pgx.Tx, mode models.ImportMode, rejected map[int]string) ([]int, []string, []int, error) {
+	var lines, bannerIds []int
+	var actions []string
+	claimed := make(map[int]int)
+	var line int
+	var matched []int
+	rows, _ := tx.Query(ctx, selectImportMatchesQuery)
+	_, err := pgx.ForEachRow(rows, []any{&line, &matched}, func() error {
+		if _, ok := rejected[line]; ok {
+			return nil
+		}
+		if len(matched) > 1 {
+			rejected[line] = fmt.Sprintf("feature and tags are used by banners %v", matched)
+			return nil
+		}
+		id := matched[0]
+		if previous, ok := claimed[id]; ok {
+			rejected[line] = fmt.Sprintf("banner %d is already imported by line %d", id, previous)
+			return nil
+		}
+		if mode == models.ImportFailOnConflict {
+			rejected[line] = fmt.Sprintf("feature and tag are used by banner %d", id)
+			return nil
+		}
+		claimed[id] = line
+		action := importUpdate
+		if mode == models.ImportSkipExisting {
+			action = importSkip
+		}
+		lines, actions, bannerIds = append(lines, line), append(actions, action), append(bannerIds, id)
+		return nil
+	})
+	return lines, actions, bannerIds, err
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func importRecords(records ...models.ImportRecord) func() (*models.ImportRecord, error) {
+	return func() (*models.ImportRecord, error) {
+		if len(records) == 0 {
+			return nil, nil
+		}
+		record := records[0]
+		records = records[1:]
+		return &record, nil
+	}
+}
+
+func importRecord(line, featureId int, tagIds []int, title string, isActive bool) models.ImportRecord {
+	return models.ImportRecord{Line: line, Banner: models.Banner{
+		BaseBanner: models.BaseBanner{UserBanner: models.UserBanner{Content: map[string]any{"title": title}}, FeatureId: featureId, TagIds: tagIds},
+		IsActive:   isActive,
+	}}
+}
+
+func TestListEach(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	first := addBanner(t, db, 1, []int{1}, map[string]any{"title": "Sale"}, true)
+	second := addBanner(t, db, 2, []int{1, 2}, map[string]any{"title": "Sale"}, false)
+
+	for _, test := range []struct {
+		name    string
+		options models.BannerIdentOptions
+		want    []int
+	}{
+		{name: "every banner", options: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue}, want: []int{first, second}},
+		{name: "by tag", options: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: 2}, want: []int{second}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var ids []int
+			err := db.ListEach(ctx, &models.BannerListOptions{BannerIdentOptions: test.options, Limit: models.ZeroValue, Offset: models.ZeroValue},
+				func(banner *models.BannerExt) error {
+					ids = append(ids, banner.BannerId)
+					return nil
+				})
+			require.NoError(t, err)
+			assert.Equal(t, test.want, ids)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      models.ImportOptions
+		records      []models.ImportRecord
+		wantReport   models.ImportReport
+		wantLines    []int
+		wantTitle    string
+		wantActive   bool
+		wantNewTitle string
+	}{
+		{
+			name:    "fail on conflict",
+			options: models.ImportOptions{Mode: models.ImportFailOnConflict, MaxCreated: models.ZeroValue},
+			records: []models.ImportRecord{
+				importRecord(1, 2, []int{1}, "New", false),
+				importRecord(2, 1, []int{2, 3}, "Changed", false),
+			},
+			wantReport: models.ImportReport{Created: 1, Failed: 1},
+			wantLines:  []int{2},
+			wantTitle:  "Sale",
+			wantActive: true,
+		},
+		{
+			name:    "skip existing",
+			options: models.ImportOptions{Mode: models.ImportSkipExisting, MaxCreated: models.ZeroValue},
+			records: []models.ImportRecord{
+				importRecord(1, 2, []int{1}, "New", false),
+				importRecord(2, 1, []int{2, 3}, "Changed", false),
+			},
+			wantReport:   models.ImportReport{Created: 1, Skipped: 1, Applied: true},
+			wantTitle:    "Sale",
+			wantActive:   true,
+			wantNewTitle: "New",
+		},
+		{
+			name:    "upsert",
+			options: models.ImportOptions{Mode: models.ImportUpsert, MaxCreated: models.ZeroValue},
+			records: []models.ImportRecord{
+				importRecord(1, 2, []int{1}, "New", false),
+				importRecord(2, 1, []int{2, 3}, "Changed", false),
+			},
+			wantReport:   models.ImportReport{Created: 1, Updated: 1, Applied: true},
+			wantTitle:    "Changed",
+			wantActive:   false,
+			wantNewTitle: "New",
+		},
+		{
+			name:    "dry run",
+			options: models.ImportOptions{Mode: models.ImportUpsert, DryRun: true, MaxCreated: models.ZeroValue},
+			records: []models.ImportRecord{
+				importRecord(1, 2, []int{1}, "New", false),
+				importRecord(2, 1, []int{2, 3}, "Changed", false),
+			},
+			wantReport: models.ImportReport{Created: 1, Updated: 1},
+			wantTitle:  "Sale",
+			wantActive: true,
+		},
+		{
+			name:    "records sharing keys",
+			options: models.ImportOptions{Mode: models.ImportUpsert, MaxCreated: models.ZeroValue},
+			records: []models.ImportRecord{
+				importRecord(1, 2, []int{1, 2}, "New", false),
+				importRecord(2, 2, []int{2}, "Other", true),
+				importRecord(3, 1, []int{1}, "Changed", true),
+				importRecord(4, 1, []int{2}, "Changed", true),
+			},
+			wantReport: models.ImportReport{Created: 1, Updated: 1, Failed: 2},
+			wantLines:  []int{2, 4},
+			wantTitle:  "Sale",
+			wantActive: true,
+		},
+		{
+			name:    "record matching several banners",
+			options: models.ImportOptions{Mode: models.ImportUpsert, MaxCreated: models.ZeroValue},
+			records: []models.ImportRecord{
+				importRecord(1, 1, []int{1, 5}, "Changed", true),
+			},
+			wantReport: models.ImportReport{Failed: 1},
+			wantLines:  []int{1},
+			wantTitle:  "Sale",
+			wantActive: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			ctx := context.Background()
+			id := addBanner(t, db, 1, []int{1, 2}, map[string]any{"title": "Sale"}, true)
+			addBanner(t, db, 1, []int{5}, map[string]any{"title": "Other"}, true)
+
+			report := &models.ImportReport{}
+			err := db.Import(ctx, importRecords(test.records...), &test.options, report)
+			require.NoError(t, err)
+
+			var lines []int
+			for _, recordErr := range report.Errors {
+				lines = append(lines, recordErr.Line)
+			}
+			assert.ElementsMatch(t, test.wantLines, lines)
+			report.Errors = nil
+			assert.Equal(t, test.wantReport, *report)
+
+			banner, err := db.GetById(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantTitle, banner.Content["title"])
+			assert.Equal(t, test.wantActive, banner.IsActive)
+
+			created := findBanner(t, db, 2, 1)
+			if test.wantNewTitle == "" {
+				assert.Empty(t, created, "nothing must be created")
+				return
+			}
+			require.Len(t, created, 1)
+			banner, err = db.GetById(ctx, created[0])
+			require.NoError(t, err)
+			assert.Equal(t, test.wantNewTitle, banner.Content["title"])
+			assert.False(t, banner.IsActive)
+		})
+	}
+}
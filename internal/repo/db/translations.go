@@ -0,0 +1,83 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	translationColumns    = "t.bannerId, t.locale, t.content, t.updated"
+	listTranslationsQuery = "SELECT " + translationColumns + ` FROM banner_translations t
+	JOIN banners b ON b.id = t.bannerId WHERE t.bannerId = $1 AND b.tenant = $2 AND b.deleted_at IS NULL ORDER BY t.locale`
+	findTranslationsQuery = "SELECT " + translationColumns + ` FROM banner_translations t
+	JOIN banners b ON b.id = t.bannerId WHERE t.bannerId = ANY($1) AND t.locale = ANY($2) AND b.tenant = $3`
+	upsertTranslationQuery = `INSERT INTO banner_translations (bannerId, locale, content)
+	SELECT b.id, $2, $3 FROM banners b WHERE b.id = $1 AND b.tenant = $4 AND b.deleted_at IS NULL
+	ON CONFLICT (bannerId, locale) DO UPDATE SET content = EXCLUDED.content, updated = current_timestamp`
+	deleteTranslationQuery = `DELETE FROM banner_translations t USING banners b
+	WHERE b.id = t.bannerId AND t.bannerId = $1 AND t.locale = $2 AND b.tenant = $3 AND b.deleted_at IS NULL`
+	missingTranslationsQuery = `SELECT m.id, m.featureId, m.locales FROM (
+    SELECT b.id, b.featureId, ARRAY(
+        SELECT l.locale FROM unnest($1::TEXT[]) WITH ORDINALITY l(locale, n)
+        WHERE NOT EXISTS (SELECT 1 FROM banner_translations t WHERE t.bannerId = b.id AND t.locale = l.locale)
+        ORDER BY l.n) locales
+    FROM banners b WHERE b.tenant = $2 AND b.deleted_at IS NULL AND ($3 < 0 OR b.featureId = $3)) m
+	WHERE cardinality(m.locales) > 0 ORDER BY m.id`
+)
+
+// ListTranslations returns translations of the banner ordered by locale
+func (p PostgresDatabase) ListTranslations(ctx context.Context, id int) ([]models.Translation, error) {
+	rows, _ := p.conn(ctx).Query(ctx, listTranslationsQuery, id, tenant(ctx))
+	return pgx.CollectRows(rows, scanTranslation)
+}
+
+// FindTranslations returns translations of the banners to any of the locales in no particular order
+func (p PostgresDatabase) FindTranslations(ctx context.Context, ids []int, locales []string) ([]models.Translation, error) {
+	rows, _ := p.conn(ctx).Query(ctx, findTranslationsQuery, ids, locales, tenant(ctx))
+	return pgx.CollectRows(rows, scanTranslation)
+}
+
+// PutTranslation sets or replaces the content of the banner in the locale, it is not a version of the banner
+func (p PostgresDatabase) PutTranslation(ctx context.Context, translation *models.Translation) error {
+	tag, err := p.conn(ctx).Exec(ctx, upsertTranslationQuery, translation.BannerId, translation.Locale,
+		Attrs(translation.Content), tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func (p PostgresDatabase) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	tag, err := p.conn(ctx).Exec(ctx, deleteTranslationQuery, id, locale, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+// MissingTranslations reports banners lacking translations to any of the locales, featureId equal to
+// models.ZeroValue checks banners of every feature
+func (p PostgresDatabase) MissingTranslations(ctx context.Context, featureId int, locales []string) ([]models.MissingTranslations, error) {
+	rows, _ := p.conn(ctx).Query(ctx, missingTranslationsQuery, locales, tenant(ctx), featureId)
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.MissingTranslations, error) {
+		res := models.MissingTranslations{}
+		err := row.Scan(&res.BannerId, &res.FeatureId, &res.Locales)
+		return res, err
+	})
+}
+
+func scanTranslation(row pgx.CollectableRow) (models.Translation, error) {
+	res := models.Translation{}
+	attr := make(Attrs)
+	err := row.Scan(&res.BannerId, &res.Locale, &attr, &res.UpdatedAt)
+	res.Content = attr
+	return res, err
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslations(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx, other := tenantContext(""), tenantContext("other")
+	add := func(featureId int) int {
+		id, err := db.Add(ctx, &models.Banner{
+			BaseBanner: models.BaseBanner{FeatureId: featureId, TagIds: []int{1}, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+			IsActive:   true,
+		})
+		require.NoError(t, err)
+		return id
+	}
+	id, otherId := add(1), add(2)
+
+	require.NoError(t, db.PutTranslation(ctx, &models.Translation{BannerId: id, Locale: "ru", Content: map[string]any{"title": "Скидки"}}))
+	require.NoError(t, db.PutTranslation(ctx, &models.Translation{BannerId: id, Locale: "en-GB", Content: map[string]any{"title": "Offer"}}))
+	require.NoError(t, db.PutTranslation(ctx, &models.Translation{BannerId: id, Locale: "en-GB", Content: map[string]any{"title": "Sale"}}),
+		"translation is replaced")
+	assert.ErrorIs(t, db.PutTranslation(other, &models.Translation{BannerId: id, Locale: "de", Content: map[string]any{}}), e.ErrorNotFound)
+
+	translations, err := db.ListTranslations(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, translations, 2)
+	assert.Equal(t, "en-GB", translations[0].Locale)
+	assert.Equal(t, map[string]any{"title": "Sale"}, translations[0].Content)
+	found, err := db.FindTranslations(ctx, []int{id, otherId}, []string{"ru-RU", "ru"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, map[string]any{"title": "Скидки"}, found[0].Content)
+	found, err = db.FindTranslations(other, []int{id}, []string{"ru"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	missing, err := db.MissingTranslations(ctx, models.ZeroValue, []string{"ru", "de"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.MissingTranslations{
+		{BannerId: id, FeatureId: 1, Locales: []string{"de"}},
+		{BannerId: otherId, FeatureId: 2, Locales: []string{"ru", "de"}},
+	}, missing)
+	missing, err = db.MissingTranslations(ctx, 1, []string{"ru"})
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	assert.ErrorIs(t, db.DeleteTranslation(other, id, "ru"), e.ErrorNotFound)
+	require.NoError(t, db.DeleteTranslation(ctx, id, "ru"))
+	assert.ErrorIs(t, db.DeleteTranslation(ctx, id, "ru"), e.ErrorNotFound)
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trashIds(t *testing.T, db *PostgresDatabase, featureId, tagId int) []int {
+	t.Helper()
+	banners, err := db.List(context.Background(), &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: featureId, TagId: tagId},
+		Deleted:            true,
+		Limit:              models.ZeroValue,
+		Offset:             models.ZeroValue,
+	})
+	require.NoError(t, err)
+	var ids []int
+	for _, banner := range banners {
+		require.NotNil(t, banner.DeletedAt)
+		ids = append(ids, banner.BannerId)
+	}
+	return ids
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	id := addBanner(t, db, 1, []int{1, 2}, map[string]any{"title": "Sale"}, true)
+	update := updateBanner(models.ContentBit, 0, nil, map[string]any{"title": "Black Friday"}, true)
+	require.NoError(t, db.Update(ctx, id, &update, ""))
+	otherId := addBanner(t, db, 2, []int{3}, map[string]any{"title": "Sale"}, true)
+
+	require.NoError(t, db.DeleteById(ctx, id, ""))
+	_, err := db.GetById(ctx, id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+	assert.ErrorIs(t, db.DeleteById(ctx, id, ""), e.ErrorNotFound, "banner is deleted once")
+	assert.ErrorIs(t, db.Update(ctx, id, &update, ""), e.ErrorNotFound, "deleted banner can't be changed")
+	assert.Empty(t, findBanner(t, db, 1, 1))
+	assert.Empty(t, bannerKeys(t, db, id))
+	history, err := db.GetHistoryForId(ctx, id, &models.HistoryListOptions{Limit: models.ZeroValue, Offset: models.ZeroValue})
+	require.NoError(t, err)
+	assert.Len(t, history, 1, "history is kept in the trash")
+
+	assert.Equal(t, []int{id}, trashIds(t, db, models.ZeroValue, models.ZeroValue))
+	assert.Equal(t, []int{id}, trashIds(t, db, 1, 2))
+	assert.Empty(t, trashIds(t, db, 2, models.ZeroValue))
+
+	// keys of the deleted banner are free until it is restored
+	takenId := addBanner(t, db, 1, []int{2}, map[string]any{"title": "New"}, true)
+	assert.ErrorIs(t, db.Restore(ctx, id), e.ErrorConflict)
+	require.NoError(t, db.DeleteById(ctx, takenId, ""))
+	require.NoError(t, db.Restore(ctx, id))
+	assert.ErrorIs(t, db.Restore(ctx, id), e.ErrorNotFound, "banner is not in the trash")
+	assert.ErrorIs(t, db.Restore(ctx, otherId), e.ErrorNotFound)
+	banner, err := db.GetById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Black Friday", banner.Content["title"])
+	assert.Nil(t, banner.DeletedAt)
+	assert.Equal(t, []int{id}, findBanner(t, db, 1, 1))
+	assert.ElementsMatch(t, []string{"1:1", "1:2"}, bannerKeys(t, db, id))
+	assert.Equal(t, []int{takenId}, trashIds(t, db, models.ZeroValue, models.ZeroValue))
+}
+
+func TestPurgeTrash(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	oldId := addBanner(t, db, 1, []int{1}, map[string]any{"title": "Sale"}, true)
+	newId := addBanner(t, db, 1, []int{2}, map[string]any{"title": "Sale"}, true)
+	liveId := addBanner(t, db, 1, []int{3}, map[string]any{"title": "Sale"}, true)
+	_, err := db.DeleteByIds(ctx, []int{oldId})
+	require.NoError(t, err)
+	require.NoError(t, db.DeleteById(ctx, newId, ""))
+	_, err = db.pool.Exec(ctx, "UPDATE banners SET deleted_at = deleted_at - INTERVAL '2 hours' WHERE id = $1", oldId)
+	require.NoError(t, err)
+
+	removed, err := db.PurgeTrash(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	assert.Equal(t, []int{newId}, trashIds(t, db, models.ZeroValue, models.ZeroValue))
+	history, err := db.GetHistoryForId(ctx, oldId, &models.HistoryListOptions{Limit: models.ZeroValue, Offset: models.ZeroValue})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	_, err = db.GetById(ctx, liveId)
+	assert.NoError(t, err)
+}
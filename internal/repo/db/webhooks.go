@@ -0,0 +1,196 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	webhookColumns     = "id, url, events, is_active, created, updated"
+	listWebhooksQuery  = "SELECT " + webhookColumns + " FROM webhooks WHERE tenant = $1 ORDER BY id"
+	selectWebhookQuery = "SELECT " + webhookColumns + " FROM webhooks WHERE id = $1 AND tenant = $2"
+	insertWebhookQuery = "INSERT INTO webhooks (url, events, secret, is_active, tenant) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	// empty secret keeps the current one
+	updateWebhookQuery = `UPDATE webhooks SET url = $2, events = $3, secret = COALESCE(NULLIF($4, ''), secret), is_active = $5,
+    updated = current_timestamp WHERE id = $1 AND tenant = $6`
+	deleteWebhookQuery   = "DELETE FROM webhooks WHERE id = $1 AND tenant = $2"
+	enqueueWebhooksQuery = `INSERT INTO webhook_outbox (webhookId, event, bannerId, payload)
+	SELECT w.id, $2, b.id, jsonb_build_object('event', $2::TEXT, 'banner_id', b.id, 'feature_id', b.featureId,
+    'tag_ids', b.tagIds, 'revision', b.revision, 'is_active', NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = b.id),
+    'actor', $3::TEXT, 'occurred_at', current_timestamp)
+	FROM banners b JOIN webhooks w ON w.tenant = b.tenant AND w.is_active AND $2 = ANY (w.events)
+	WHERE b.id = ANY ($1) ORDER BY b.id, w.id`
+	deliveryColumns = `o.id, o.webhookId, o.event, o.bannerId, o.payload, o.status, o.attempts, o.response_status,
+    o.last_error, o.next_attempt, o.created, o.delivered`
+	listDeliveriesQuery = "SELECT " + deliveryColumns + " FROM webhook_outbox o JOIN webhooks w ON w.id = o.webhookId WHERE w.tenant = $"
+	// claimed deliveries are postponed by the lease, so other instances don't send them meanwhile
+	claimDeliveriesQuery = `UPDATE webhook_outbox o SET next_attempt = current_timestamp + make_interval(secs => $2)
+	FROM webhooks w WHERE w.id = o.webhookId AND o.id IN (
+    SELECT d.id FROM webhook_outbox d JOIN webhooks dw ON dw.id = d.webhookId
+    WHERE d.status = 'pending' AND d.next_attempt <= current_timestamp AND dw.is_active
+    ORDER BY d.id LIMIT $1 FOR UPDATE OF d SKIP LOCKED)
+	RETURNING ` + deliveryColumns + ", w.url, w.secret"
+	deliveredQuery = `UPDATE webhook_outbox SET status = 'delivered', attempts = attempts + 1, response_status = $2,
+    last_error = '', delivered = current_timestamp WHERE id = $1`
+	failedQuery = `UPDATE webhook_outbox SET status = CASE WHEN $4::BOOLEAN THEN 'dead' ELSE 'pending' END,
+    attempts = attempts + 1, response_status = $2, last_error = $3, next_attempt = current_timestamp + make_interval(secs => $5)
+	WHERE id = $1`
+	retryDeliveryQuery = `UPDATE webhook_outbox o SET status = 'pending', attempts = 0, next_attempt = current_timestamp
+	FROM webhooks w WHERE w.id = o.webhookId AND o.id = $1 AND o.webhookId = $2 AND w.tenant = $3 AND o.status = 'dead'`
+	pruneDeliveriesQuery = "DELETE FROM webhook_outbox WHERE status = 'delivered' AND delivered < current_timestamp - make_interval(secs => $1)"
+)
+
+func (p PostgresDatabase) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	rows, _ := p.conn(ctx).Query(ctx, listWebhooksQuery, tenant(ctx))
+	return pgx.CollectRows(rows, scanWebhook)
+}
+
+func (p PostgresDatabase) GetWebhook(ctx context.Context, id int) (*models.Webhook, error) {
+	rows, _ := p.conn(ctx).Query(ctx, selectWebhookQuery, id, tenant(ctx))
+	webhook, err := pgx.CollectOneRow(rows, scanWebhook)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, e.ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (p PostgresDatabase) AddWebhook(ctx context.Context, webhook *models.Webhook) (int, error) {
+	var id int
+	err := p.conn(ctx).QueryRow(ctx, insertWebhookQuery, webhook.Url, eventNames(webhook.Events), webhook.Secret, webhook.IsActive, tenant(ctx)).Scan(&id)
+	return id, err
+}
+
+// UpdateWebhook replaces the webhook, empty Secret keeps the current one
+func (p PostgresDatabase) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	tag, err := p.conn(ctx).Exec(ctx, updateWebhookQuery, webhook.Id, webhook.Url, eventNames(webhook.Events), webhook.Secret, webhook.IsActive, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+// DeleteWebhook removes the webhook with its deliveries
+func (p PostgresDatabase) DeleteWebhook(ctx context.Context, id int) error {
+	tag, err := p.conn(ctx).Exec(ctx, deleteWebhookQuery, id, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns deliveries of webhooks of the tenant, the latest first
+func (p PostgresDatabase) ListDeliveries(ctx context.Context, options *models.DeliveryListOptions) ([]models.WebhookDelivery, error) {
+	builder := build()
+	builder(listDeliveriesQuery, tenant(ctx))
+	if options.WebhookId != models.ZeroValue {
+		builder(" AND o.webhookId = $", options.WebhookId)
+	}
+	if options.Status != "" {
+		builder(" AND o.status = $", options.Status)
+	}
+	builder(" ORDER BY o.id DESC", nil)
+	if options.Limit > 0 {
+		builder(" LIMIT $", options.Limit)
+	}
+	if options.Offset > 0 {
+		builder(" OFFSET $", options.Offset)
+	}
+	query, args := builder("", nil)
+	rows, _ := p.conn(ctx).Query(ctx, query, args...)
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.WebhookDelivery, error) {
+		res := models.WebhookDelivery{}
+		err := row.Scan(deliveryFields(&res)...)
+		return res, err
+	})
+}
+
+// ClaimDeliveries returns at most limit pending deliveries of every tenant due now. They are not claimed again
+// until the lease passes, so a delivery whose attempt was never recorded is sent once more
+func (p PostgresDatabase) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueDelivery, error) {
+	rows, _ := p.conn(ctx).Query(ctx, claimDeliveriesQuery, limit, lease.Seconds())
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.DueDelivery, error) {
+		res := models.DueDelivery{}
+		err := row.Scan(append(deliveryFields(&res.WebhookDelivery), &res.Url, &res.Secret)...)
+		return res, err
+	})
+}
+
+// RecordAttempt stores the result of sending the delivery
+func (p PostgresDatabase) RecordAttempt(ctx context.Context, attempt *models.DeliveryAttempt) error {
+	if attempt.Delivered {
+		_, err := p.conn(ctx).Exec(ctx, deliveredQuery, attempt.DeliveryId, attempt.ResponseStatus)
+		return err
+	}
+	_, err := p.conn(ctx).Exec(ctx, failedQuery, attempt.DeliveryId, attempt.ResponseStatus, attempt.Error, attempt.Dead,
+		attempt.RetryAfter.Seconds())
+	return err
+}
+
+// RetryDelivery makes the dead letter pending again with every attempt available
+func (p PostgresDatabase) RetryDelivery(ctx context.Context, webhookId int, id int64) error {
+	tag, err := p.conn(ctx).Exec(ctx, retryDeliveryQuery, id, webhookId, tenant(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+// PruneDeliveries removes deliveries of every tenant delivered earlier than retention ago, dead letters are kept
+func (p PostgresDatabase) PruneDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	tag, err := p.conn(ctx).Exec(ctx, pruneDeliveriesQuery, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// enqueueWebhooks writes deliveries of the event about the banners to active webhooks of their tenant subscribed
+// to it. It runs in the transaction of the change, so only committed changes are delivered
+func enqueueWebhooks(ctx context.Context, tx pgx.Tx, event models.WebhookEvent, ids ...int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, enqueueWebhooksQuery, ids, string(event), identity.From(ctx).Actor)
+	return err
+}
+
+func eventNames(events []models.WebhookEvent) []string {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, string(event))
+	}
+	return names
+}
+
+func scanWebhook(row pgx.CollectableRow) (models.Webhook, error) {
+	res := models.Webhook{}
+	var events []string
+	err := row.Scan(&res.Id, &res.Url, &events, &res.IsActive, &res.CreatedAt, &res.UpdatedAt)
+	for _, event := range events {
+		res.Events = append(res.Events, models.WebhookEvent(event))
+	}
+	return res, err
+}
+
+func deliveryFields(res *models.WebhookDelivery) []any {
+	return []any{&res.Id, &res.WebhookId, &res.Event, &res.BannerId, &res.Payload, &res.Status, &res.Attempts,
+		&res.ResponseStatus, &res.LastError, &res.NextAttemptAt, &res.CreatedAt, &res.DeliveredAt}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookOutbox(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := tenantContext("acme")
+	webhookId, err := db.AddWebhook(ctx, &models.Webhook{
+		Url:      "https://example.com/hook",
+		Events:   []models.WebhookEvent{models.WebhookCreated, models.WebhookUpdated, models.WebhookDeleted},
+		Secret:   "0123456789abcdef",
+		IsActive: true,
+	})
+	require.NoError(t, err)
+	_, err = db.AddWebhook(tenantContext("other"), &models.Webhook{
+		Url:      "https://example.com/other",
+		Events:   models.WebhookEvents,
+		Secret:   "0123456789abcdef",
+		IsActive: true,
+	})
+	require.NoError(t, err)
+
+	id, err := db.Add(ctx, &models.Banner{
+		BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{1}, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}},
+		IsActive:   true,
+	})
+	require.NoError(t, err)
+	update := updateBanner(models.IsActiveBit, 0, nil, nil, false)
+	require.NoError(t, db.Update(ctx, id, &update, ""))
+	update = updateBanner(models.IsActiveBit, 0, nil, nil, true)
+	require.NoError(t, db.Update(ctx, id, &update, ""), "activation is not subscribed to")
+	require.NoError(t, db.DeleteById(ctx, id, ""))
+
+	deliveries, err := db.ListDeliveries(ctx, &models.DeliveryListOptions{WebhookId: models.ZeroValue})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3, "only the webhook of the tenant gets its events")
+	events := make([]models.WebhookEvent, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		events = append(events, delivery.Event)
+		assert.Equal(t, models.DeliveryPending, delivery.Status)
+	}
+	assert.Equal(t, []models.WebhookEvent{models.WebhookDeleted, models.WebhookUpdated, models.WebhookCreated}, events)
+	payload := map[string]any{}
+	require.NoError(t, json.Unmarshal(deliveries[2].Payload, &payload))
+	assert.Equal(t, "created", payload["event"])
+	assert.EqualValues(t, id, payload["banner_id"])
+	assert.Equal(t, "admin", payload["actor"])
+
+	due, err := db.ClaimDeliveries(ctx, 2, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "https://example.com/hook", due[0].Url)
+	assert.Equal(t, "0123456789abcdef", due[0].Secret)
+	more, err := db.ClaimDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, more, 1, "claimed deliveries are leased")
+
+	require.NoError(t, db.RecordAttempt(ctx, &models.DeliveryAttempt{DeliveryId: due[0].Id, Delivered: true, ResponseStatus: 200}))
+	require.NoError(t, db.RecordAttempt(ctx, &models.DeliveryAttempt{DeliveryId: due[1].Id, Dead: true, ResponseStatus: 500, Error: "failed"}))
+	dead, err := db.ListDeliveries(ctx, &models.DeliveryListOptions{WebhookId: webhookId, Status: models.DeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, due[1].Id, dead[0].Id)
+	assert.Equal(t, 1, dead[0].Attempts)
+	assert.Equal(t, "failed", dead[0].LastError)
+
+	assert.ErrorIs(t, db.RetryDelivery(ctx, webhookId, due[0].Id), e.ErrorNotFound, "delivered one is not retried")
+	assert.ErrorIs(t, db.RetryDelivery(tenantContext("other"), webhookId, due[1].Id), e.ErrorNotFound)
+	require.NoError(t, db.RetryDelivery(ctx, webhookId, due[1].Id))
+	retried, err := db.ClaimDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, retried, 1)
+	assert.Zero(t, retried[0].Attempts)
+
+	removed, err := db.PruneDeliveries(ctx, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, removed, "only delivered ones are pruned")
+	require.NoError(t, db.DeleteWebhook(ctx, webhookId))
+	deliveries, err = db.ListDeliveries(ctx, &models.DeliveryListOptions{WebhookId: models.ZeroValue})
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
@@ -9,61 +9,153 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// maxUserFeatures is the number of features a user gets banners of at once
+const maxUserFeatures = 100
+
+//go:generate mockgen -source=banner.go -package=mocks -destination=./mocks/mock_banner.go
 type Database interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	Add(ctx context.Context, banner *models.Banner) (int, error)
-	Update(ctx context.Context, id int, banner *models.UpdateBanner) error
+	Update(ctx context.Context, id int, banner *models.UpdateBanner, ifMatch string) error
 	List(ctx context.Context, options *models.BannerListOptions) ([]models.BannerExt, error)
-	DeleteByIds(ctx context.Context, ids ...int) error
-	DeleteByFeatureOrTag(ctx context.Context, options *models.BannerIdentOptions) error
-	GetHistoryForId(ctx context.Context, id int) ([]models.HistoryBanner, error)
-	SelectBannerVersion(ctx context.Context, id, version int) error
+	Count(ctx context.Context, options *models.BannerListOptions) (int, error)
+	ListEach(ctx context.Context, options *models.BannerListOptions, fn func(banner *models.BannerExt) error) error
+	Search(ctx context.Context, options *models.BannerSearchOptions) ([]models.SearchHit, error)
+	CountSearch(ctx context.Context, options *models.BannerSearchOptions) (int, error)
+	Import(ctx context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions, report *models.ImportReport) error
+	DeleteById(ctx context.Context, id int, ifMatch string) error
+	DeleteByIds(ctx context.Context, ids []int) ([]int, error)
+	PreviewDelete(ctx context.Context, filter *models.BulkDeleteFilter) ([]int, error)
+	Restore(ctx context.Context, id int) error
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	GetHistoryForId(ctx context.Context, id int, options *models.HistoryListOptions) ([]models.HistoryBanner, error)
+	GetHistoryVersion(ctx context.Context, id, version int) (*models.HistoryBanner, error)
+	PruneHistory(ctx context.Context, retention *models.HistoryRetention) (int64, error)
+	SelectBannerVersion(ctx context.Context, id, version int, ifMatch string) error
+	GetById(ctx context.Context, id int) (*models.BannerExt, error)
+	UpdateContent(ctx context.Context, id int, ifMatch string, update func(banner *models.BaseBanner) (map[string]any, error)) error
+	GetFeatureSchema(ctx context.Context, featureId int) (*models.FeatureSchema, error)
+	ListFeatureSchemas(ctx context.Context) ([]models.FeatureSchema, error)
+	PutFeatureSchema(ctx context.Context, schema *models.FeatureSchema) error
+	DeleteFeatureSchema(ctx context.Context, featureId int) error
+	ListRegistry(ctx context.Context, registry models.Registry) ([]models.RegistryEntry, error)
+	GetRegistryEntry(ctx context.Context, registry models.Registry, id int) (*models.RegistryEntry, error)
+	GetRegistryEntryByName(ctx context.Context, registry models.Registry, name string) (*models.RegistryEntry, error)
+	AddRegistryEntry(ctx context.Context, registry models.Registry, entry *models.RegistryEntry) (int, error)
+	UpdateRegistryEntry(ctx context.Context, registry models.Registry, id int, entry *models.RegistryEntry) error
+	DeleteRegistryEntry(ctx context.Context, registry models.Registry, id int) error
+	MissingRegistryIds(ctx context.Context, registry models.Registry, ids ...int) ([]int, error)
+	AddDraft(ctx context.Context, banner *models.Banner) (int, error)
+	GetRevision(ctx context.Context, id int) (*models.PendingRevision, error)
+	ChangeRevision(ctx context.Context, id int, ifMatch string, change func(revision *models.PendingRevision) error) error
+	ListReviews(ctx context.Context, options *models.ReviewListOptions) ([]models.PendingRevision, error)
+	GetTargeting(ctx context.Context, id int) (*models.Targeting, error)
+	ListTargeting(ctx context.Context, ids []int) ([]models.Targeting, error)
+	PutTargeting(ctx context.Context, targeting *models.Targeting) error
+	DeleteTargeting(ctx context.Context, id int) error
+	ListTranslations(ctx context.Context, id int) ([]models.Translation, error)
+	FindTranslations(ctx context.Context, ids []int, locales []string) ([]models.Translation, error)
+	PutTranslation(ctx context.Context, translation *models.Translation) error
+	DeleteTranslation(ctx context.Context, id int, locale string) error
+	MissingTranslations(ctx context.Context, featureId int, locales []string) ([]models.MissingTranslations, error)
+	ListenBannerEvents(ctx context.Context, fn func(ctx context.Context) error) error
+	ListBannerEvents(ctx context.Context, after int64, limit int) ([]models.BannerEvent, error)
+	LastBannerEventId(ctx context.Context) (int64, error)
+	BannerChangedSince(ctx context.Context, featureId int, tagIds []int, after int64) (bool, error)
+	PruneBannerEvents(ctx context.Context, retention time.Duration) (int64, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (*models.Webhook, error)
+	AddWebhook(ctx context.Context, webhook *models.Webhook) (int, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int) error
+	ListDeliveries(ctx context.Context, options *models.DeliveryListOptions) ([]models.WebhookDelivery, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueDelivery, error)
+	RecordAttempt(ctx context.Context, attempt *models.DeliveryAttempt) error
+	RetryDelivery(ctx context.Context, webhookId int, id int64) error
+	PruneDeliveries(ctx context.Context, retention time.Duration) (int64, error)
 }
 
 type Cache interface {
-	Get(ctx context.Context, options *models.BannerIdentOptions) (*models.UserBanner, error)
-	Put(ctx context.Context, options *models.BannerIdentOptions, banner *models.UserBanner) error
+	GetMany(ctx context.Context, options []models.BannerIdentOptions, locale string) ([]*models.Candidate, error)
+	Put(ctx context.Context, options *models.BannerIdentOptions, locale string, banner *models.Candidate) error
+	Invalidate(ctx context.Context, options []models.BannerIdentOptions) error
 }
 
 type Service struct {
 	wg             sync.WaitGroup
 	timeout        time.Duration
-	logger         *slog.Logger
-	db             Database
-	cache          Cache
-	tasksChan      chan *models.BannerIdentOptions
-	activeRequests int64
+	retention      models.HistoryRetention
+	pruneInterval  time.Duration
+	trashRetention time.Duration
+	purgeInterval  time.Duration
+	// confirmThreshold is the number of banners deleted at once without confirmation
+	confirmThreshold int
+	// approvalRequired makes changes of banners pending revisions, which are published after review
+	approvalRequired bool
+	quotas           config.TenantsConfig
+	eventRetention   time.Duration
+	eventInterval    time.Duration
+	events           *eventHub
+	webhooks         config.WebhooksConfig
+	client           *http.Client
+	done             chan struct{}
+	logger           *slog.Logger
+	db               Database
+	cache            Cache
+	jobs             *deleteJobs
+	tasksChan        chan *models.DeleteJob
+	activeRequests   int64
 }
 
 func New(db Database, cache Cache, logger *slog.Logger, cfg *config.ServiceConfig) *Service {
 	return &Service{
-		timeout:   cfg.Timeout,
-		logger:    logger,
-		db:        db,
-		cache:     cache,
-		tasksChan: make(chan *models.BannerIdentOptions, 100),
+		timeout: cfg.Timeout,
+		retention: models.HistoryRetention{
+			MaxVersions: cfg.History.MaxVersions,
+			MaxAge:      cfg.History.MaxAge,
+		},
+		pruneInterval:    cfg.History.PruneInterval,
+		trashRetention:   cfg.Trash.Retention,
+		purgeInterval:    cfg.Trash.PurgeInterval,
+		confirmThreshold: cfg.BulkDelete.ConfirmThreshold,
+		approvalRequired: cfg.Approval.Required,
+		quotas:           cfg.Tenants,
+		eventRetention:   cfg.Events.Retention,
+		eventInterval:    cfg.Events.PruneInterval,
+		events:           newEventHub(),
+		webhooks:         cfg.Webhooks,
+		client:           &http.Client{Timeout: cfg.Webhooks.Timeout},
+		done:             make(chan struct{}),
+		logger:           logger,
+		db:               db,
+		cache:            cache,
+		jobs:             newDeleteJobs(),
+		tasksChan:        make(chan *models.DeleteJob, 100),
 	}
 }
 
 func (s *Service) MustRun() {
-	for ident := range s.tasksChan {
+	go s.runHistoryPruning()
+	go s.runTrashPurge()
+	go s.runBannerEvents()
+	go s.runEventPruning()
+	go s.runWebhookDeliveries()
+	go s.runDeliveryPruning()
+	for job := range s.tasksChan {
 		for {
 			if atomic.LoadInt64(&s.activeRequests) < 200 {
 				break
 			}
 			time.Sleep(2 * time.Second)
 		}
-		const op = "long deletion"
-		newCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
-		err := s.db.DeleteByFeatureOrTag(newCtx, ident)
-		if err != nil {
-			s.logger.Warn(op, fmt.Sprintf("Failed to delete: tag: %d feature: %d", ident.TagId, ident.FeatureId), err.Error())
-		}
-		cancel()
+		s.runDeleteJob(job)
 	}
 }
 
@@ -71,6 +163,7 @@ func (s *Service) Stop(ctx context.Context) {
 	const op = "banner.Stop"
 	log := s.logger.With(utils.Text(op))
 	log.Info("stopping banner service")
+	close(s.done)
 	done := make(chan struct{})
 	go func() {
 		s.wg.Wait()
@@ -90,12 +183,24 @@ func (s *Service) CreateBanner(ctx context.Context, banner *models.Banner) (int,
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
 	const op = "banner.CreateBanner"
-	log := s.logger.With(op)
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return 0, e.ErrorInternal
 	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
+	if err := s.validateRegistered(newCtx, &models.UpdateBanner{Banner: *banner, Flags: models.FeatureBit | models.TagBit}, log); err != nil {
+		return 0, err
+	}
+	if err := s.validateContent(newCtx, banner.FeatureId, banner.Content, log); err != nil {
+		return 0, err
+	}
+	if err := s.checkQuota(newCtx, log); err != nil {
+		return 0, err
+	}
+	if s.approvalRequired {
+		return s.createDraft(newCtx, banner, log)
+	}
 	id, err := s.db.Add(newCtx, banner)
 	if err != nil {
 		log.Warn(err.Error())
@@ -107,60 +212,70 @@ func (s *Service) CreateBanner(ctx context.Context, banner *models.Banner) (int,
 	return id, nil
 }
 
-func (s *Service) ListBannerHistory(ctx context.Context, id int) ([]models.HistoryBanner, error) {
+func (s *Service) ListBannerHistory(ctx context.Context, id int, options *models.HistoryListOptions) ([]models.HistoryBanner, error) {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
 	const op = "banner.ListBannerHistory"
-	log := s.logger.With(op)
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return nil, e.ErrorInternal
 	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	banners, err := s.db.GetHistoryForId(newCtx, id)
+	banners, err := s.db.GetHistoryForId(newCtx, id, options)
 	if err != nil {
 		log.Warn(err.Error())
 		return nil, e.ErrorInternal
 	}
-	if len(banners) == 0 {
+	// pages after the last one are empty, only missing history is not found
+	if len(banners) == 0 && options.Offset <= 0 {
 		log.Info("no banner found")
 		return nil, e.ErrorNotFound
 	}
 	return banners, nil
 }
 
-func (s *Service) SelectBannerVersion(ctx context.Context, id, version int) error {
+func (s *Service) SelectBannerVersion(ctx context.Context, id, version int, ifMatch string) error {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
-	const op = "banner.ListBannerHistory"
-	log := s.logger.With(op)
+	const op = "banner.SelectBannerVersion"
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return e.ErrorInternal
 	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	err := s.db.SelectBannerVersion(newCtx, id, version)
+	if s.approvalRequired {
+		return s.selectDraftVersion(newCtx, id, version, ifMatch, log)
+	}
+	if err := s.validateVersion(newCtx, id, version, log); err != nil {
+		return err
+	}
+	err := s.db.SelectBannerVersion(newCtx, id, version, ifMatch)
 	if err != nil {
 		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorConflict) || errors.Is(err, e.ErrorPreconditionFailed) {
+			return err
+		}
 		return e.ErrorInternal
 	}
 	return nil
 }
 
-func (s *Service) DeleteBanner(ctx context.Context, id int) error {
+func (s *Service) DeleteBanner(ctx context.Context, id int, ifMatch string) error {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
 	const op = "banner.CreateBanner"
-	log := s.logger.With(op)
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return e.ErrorInternal
 	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	err := s.db.DeleteByIds(newCtx, id)
+	err := s.db.DeleteById(newCtx, id, ifMatch)
 	if err != nil {
 		log.Warn(err.Error())
-		if errors.Is(err, e.ErrorNotFound) {
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorPreconditionFailed) {
 			return err
 		}
 		return e.ErrorInternal
@@ -168,78 +283,232 @@ func (s *Service) DeleteBanner(ctx context.Context, id int) error {
 	return nil
 }
 
-func (s *Service) DeleteBannersByTagOrFeature(ctx context.Context, options *models.BannerIdentOptions) error {
+// DeleteBannersByTagOrFeature previews banners matching options and schedules their deletion unless it is
+// a dry run. Deleting more banners than the threshold requires the token of the preview, which stops matching
+// once the banners change. The job deletes exactly the previewed banners that are still live and records the removed ones
+func (s *Service) DeleteBannersByTagOrFeature(ctx context.Context, options *models.BulkDeleteOptions) (*models.DeletePreview, error) {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
-	const op = "banner.CreateBanner"
-	log := s.logger.With(op)
+	const op = "banner.DeleteBannersByTagOrFeature"
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
-		return e.ErrorInternal
+		return nil, e.ErrorInternal
 	}
-	s.wg.Add(1)
-	s.tasksChan <- options
-	return nil
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	ids, err := s.db.PreviewDelete(newCtx, &options.BulkDeleteFilter)
+	if err != nil {
+		log.Warn("failed to preview deletion", utils.Err(err))
+		return nil, e.ErrorInternal
+	}
+	preview := &models.DeletePreview{
+		Ids:             ids,
+		Token:           models.DeleteToken(&options.BulkDeleteFilter, ids),
+		ConfirmRequired: s.confirmThreshold > 0 && len(ids) > s.confirmThreshold,
+	}
+	if options.DryRun {
+		return preview, nil
+	}
+	if options.ConfirmToken == "" && preview.ConfirmRequired {
+		return nil, e.NewValidationError(e.FieldViolation{
+			Field:   "confirm_token",
+			Message: fmt.Sprintf("is required to delete %d banners, it is returned with dry_run=true", len(ids)),
+		})
+	}
+	if options.ConfirmToken != "" && options.ConfirmToken != preview.Token {
+		return nil, e.ErrorStaleConfirmation
+	}
+	preview.Job = s.scheduleDeletion(ctx, options.BulkDeleteFilter, ids)
+	return preview, nil
 }
 
-func (s *Service) ListBanners(ctx context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
+// ListBanners returns the page of banners. One more banner than the limit is fetched to know
+// whether the next page exists, the cursor of the next page points after the last banner of this one
+func (s *Service) ListBanners(ctx context.Context, options *models.BannerListOptions) (*models.BannerPage, error) {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
 	const op = "banner.ListBanner"
-	log := s.logger.With(op)
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return nil, e.ErrorInternal
 	}
+	if err := validateListOptions(options); err != nil {
+		return nil, err
+	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	list, err := s.db.List(newCtx, options)
+	page := &models.BannerPage{Total: models.ZeroValue}
+	if options.WithTotal {
+		page.Total = 0
+	}
+	if ok, err := s.resolveNames(newCtx, options, log); !ok {
+		if err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+	query := *options
+	if options.Limit > 0 {
+		query.Limit = options.Limit + 1
+	}
+	list, err := s.db.List(newCtx, &query)
 	if err != nil {
-		log.Warn("failed to get data", err.Error())
+		log.Warn("failed to get data", utils.Err(err))
 		return nil, e.ErrorInternal
 	}
-	return list, nil
+	page.Banners = list
+	if options.Limit > 0 && len(list) > options.Limit {
+		page.Banners = list[:options.Limit]
+		page.Next = models.CursorAfter(&page.Banners[options.Limit-1], options.Sort, options.Desc)
+	}
+	if options.WithTotal {
+		if page.Total, err = s.db.Count(newCtx, options); err != nil {
+			log.Warn("failed to count banners", utils.Err(err))
+			return nil, e.ErrorInternal
+		}
+	}
+	return page, nil
+}
+
+// validateListOptions checks the sort and that the cursor continues the same listing
+func validateListOptions(options *models.BannerListOptions) error {
+	var violations []e.FieldViolation
+	switch options.Sort {
+	case "", models.SortById, models.SortByCreated, models.SortByUpdated:
+	default:
+		violations = append(violations, e.FieldViolation{Field: "sort", Message: "must be one of id, created, updated"})
+	}
+	if options.After != nil && (options.After.Sort != options.Sort || options.After.Desc != options.Desc) {
+		violations = append(violations, e.FieldViolation{Field: "cursor", Message: "was issued for another sort"})
+	}
+	if options.After != nil && options.Offset > 0 {
+		violations = append(violations, e.FieldViolation{Field: "offset", Message: "can not be used with cursor"})
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
 }
 
+func (s *Service) GetBanner(ctx context.Context, id int) (*models.BannerExt, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetBanner"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	banner, err := s.db.GetById(newCtx, id)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	return banner, nil
+}
+
+// UserGetBanners gives the banner of the feature matching tags and attributes of the user. Candidates are looked up
+// by every tag of the user and the first one whose targeting matches wins, see models.SortCandidates.
+// Its content is translated to the locale of the user or to the closest one of models.LocaleChain
 func (s *Service) UserGetBanners(ctx context.Context, options *models.BannerUserOptions) (*models.UserBanner, error) {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
 	const op = "banner.UserGetBanner"
-	log := s.logger.With(op)
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return nil, e.ErrorInternal
 	}
+	if err := normalizeAttributes(&options.Attributes); err != nil {
+		return nil, err
+	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	var err error
-	var userBanner *models.UserBanner
-	if !options.UseLastRevision {
-		userBanner, err = s.getBannerFromCache(newCtx, &options.BannerIdentOptions, log)
+	candidates, err := s.userCandidates(newCtx, []int{options.FeatureId}, options.UseLastRevision, &options.Attributes, log)
+	if err != nil {
+		return nil, err
 	}
-	if options.UseLastRevision || err != nil {
-		banner, err := s.getBannerFromDb(newCtx, &options.BannerIdentOptions, log)
-		if err != nil {
-			return nil, err
+	if len(candidates[options.FeatureId]) == 0 {
+		log.Info("missing banner", utils.Text(op))
+		return nil, e.ErrorNotFound
+	}
+	if banner := matchingBanner(candidates[options.FeatureId], &options.Attributes); banner != nil {
+		return banner, nil
+	}
+	log.Info("no banner matches the user", utils.Text(op))
+	return nil, e.ErrorNotFound
+}
+
+// UserGetFeatureBanners gives banners of several features to the same user like UserGetBanners. Features without
+// a banner for the user are nil instead of failing the request. Cached banners of all features are read at once
+// and the missing ones are looked up in the database at once
+func (s *Service) UserGetFeatureBanners(ctx context.Context, options *models.BannersUserOptions) (map[int]*models.UserBanner, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.UserGetFeatureBanners"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	if len(options.FeatureIds) > maxUserFeatures {
+		return nil, e.NewValidationError(e.FieldViolation{
+			Field:   "feature_ids",
+			Message: fmt.Sprintf("must contain at most %d items", maxUserFeatures),
+		})
+	}
+	if err := normalizeAttributes(&options.Attributes); err != nil {
+		return nil, err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	featureIds := uniqueIds(options.FeatureIds)
+	candidates, err := s.userCandidates(newCtx, featureIds, options.UseLastRevision, &options.Attributes, log)
+	if err != nil {
+		return nil, err
+	}
+	banners := make(map[int]*models.UserBanner, len(featureIds))
+	for _, featureId := range featureIds {
+		banners[featureId] = matchingBanner(candidates[featureId], &options.Attributes)
+	}
+	return banners, nil
+}
+
+// matchingBanner returns the first candidate whose targeting matches the user, nil if there is none
+func matchingBanner(candidates []models.Candidate, attrs *models.UserAttributes) *models.UserBanner {
+	for i := range candidates {
+		if len(candidates[i].Mismatches(attrs)) == 0 {
+			return &candidates[i].UserBanner
 		}
-		s.wg.Add(1)
-		go s.SendBannerToCache(newCtx, &options.BannerIdentOptions, banner, log.With(op))
-		userBanner = banner
 	}
-	return userBanner, nil
+	return nil
 }
 
-func (s *Service) UpdateBanner(ctx context.Context, id int, banner *models.UpdateBanner) error {
+func (s *Service) UpdateBanner(ctx context.Context, id int, banner *models.UpdateBanner, ifMatch string) error {
 	atomic.AddInt64(&s.activeRequests, 1)
 	defer atomic.AddInt64(&s.activeRequests, -1)
 	const op = "banner.UpdateBanner"
-	log := s.logger.With(op)
+	log := s.logger.With(utils.Text(op))
 	if s.ctxDone(ctx, log) {
 		return e.ErrorInternal
 	}
 	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	err := s.db.Update(newCtx, id, banner)
+	if s.approvalRequired {
+		return s.updateDraft(newCtx, id, banner, ifMatch, log)
+	}
+	if err := s.validateRegistered(newCtx, banner, log); err != nil {
+		return err
+	}
+	if err := s.validateUpdate(newCtx, id, banner, log); err != nil {
+		return err
+	}
+	err := s.db.Update(newCtx, id, banner, ifMatch)
 	if err != nil {
-		log.Warn(op, err.Error())
-		if errors.Is(err, e.ErrorNotFound) {
+		log.Warn(err.Error(), utils.Text(op))
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorConflict) || errors.Is(err, e.ErrorPreconditionFailed) {
 			return err
 		}
 		return e.ErrorInternal
@@ -247,43 +516,100 @@ func (s *Service) UpdateBanner(ctx context.Context, id int, banner *models.Updat
 	return nil
 }
 
-func (s *Service) getBannerFromDb(newCtx context.Context, options *models.BannerIdentOptions, log *slog.Logger) (*models.UserBanner, error) {
-	const op = "banner.getBannerFromDb"
-	banners, err := s.ListBanners(newCtx, &models.BannerListOptions{
-		BannerIdentOptions: *options,
-		Limit:              models.ZeroValue,
-		Offset:             models.ZeroValue,
-	})
-	if err != nil {
-		return nil, err
+// userCandidates returns banners given to users by each of the features and tags of attrs in the order they are chosen.
+// Cached banners are read at once, feature and tag pairs missing in the cache are looked up in the database at once
+func (s *Service) userCandidates(newCtx context.Context, featureIds []int, useLastRevision bool, attrs *models.UserAttributes, log *slog.Logger) (map[int][]models.Candidate, error) {
+	idents := make([]models.BannerIdentOptions, 0, len(featureIds)*len(attrs.TagIds))
+	for _, featureId := range featureIds {
+		for _, tagId := range attrs.TagIds {
+			idents = append(idents, models.BannerIdentOptions{FeatureId: featureId, TagId: tagId})
+		}
 	}
-	if len(banners) == 0 || !banners[0].IsActive {
-		log.Info(op, "missing banner")
-		return nil, e.ErrorNotFound
+	candidates := make(map[int][]models.Candidate, len(featureIds))
+	missing := idents
+	if !useLastRevision {
+		missing = nil
+		cached := s.getBannersFromCache(newCtx, idents, attrs.Locale, log)
+		for i, candidate := range cached {
+			if candidate == nil {
+				missing = append(missing, idents[i])
+				continue
+			}
+			candidates[idents[i].FeatureId] = append(candidates[idents[i].FeatureId], *candidate)
+		}
+	}
+	if len(missing) > 0 {
+		found, err := s.getCandidatesFromDb(newCtx, missing, attrs.Locale, log)
+		if err != nil {
+			return nil, err
+		}
+		for ident, candidate := range found {
+			ident, candidate := ident, candidate
+			s.wg.Add(1)
+			go s.SendBannerToCache(newCtx, &ident, attrs.Locale, &candidate, log)
+			candidates[ident.FeatureId] = append(candidates[ident.FeatureId], candidate)
+		}
+	}
+	for featureId := range candidates {
+		models.SortCandidates(candidates[featureId])
+		candidates[featureId] = slices.CompactFunc(candidates[featureId], func(a, b models.Candidate) bool {
+			return a.BannerId == b.BannerId
+		})
 	}
-	return &banners[0].UserBanner, nil
+	return candidates, nil
 }
 
-func (s *Service) getBannerFromCache(newCtx context.Context, options *models.BannerIdentOptions, log *slog.Logger) (*models.UserBanner, error) {
-	const op = "banner.getBannerFromCache"
-	banner, err := s.cache.Get(newCtx, options)
+// getCandidatesFromDb returns active published banners translated to the locale by the feature and tag pairs
+// they are found by. Banners of every feature are listed by a single query
+func (s *Service) getCandidatesFromDb(newCtx context.Context, idents []models.BannerIdentOptions, locale string, log *slog.Logger) (map[models.BannerIdentOptions]models.Candidate, error) {
+	var featureIds, tagIds []int
+	for _, ident := range idents {
+		featureIds = append(featureIds, ident.FeatureId)
+		tagIds = append(tagIds, ident.TagId)
+	}
+	banners, err := s.listCandidates(newCtx, uniqueIds(featureIds), uniqueIds(tagIds), locale, log)
 	if err != nil {
-		log.Warn(op, err.Error())
 		return nil, err
 	}
-	if banner == nil {
-		log.Info(op, "no banner found in cache")
-		return nil, e.ErrorNotFound
+	found := make(map[models.BannerIdentOptions]models.Candidate, len(idents))
+	for _, banner := range banners {
+		if !banner.IsActive || !banner.Live {
+			continue
+		}
+		for _, tagId := range banner.TagIds {
+			ident := models.BannerIdentOptions{FeatureId: banner.FeatureId, TagId: tagId}
+			if slices.Contains(idents, ident) {
+				found[ident] = banner.Candidate
+			}
+		}
 	}
-	return banner, nil
+	return found, nil
+}
+
+// uniqueIds returns sorted ids without duplicates leaving ids intact
+func uniqueIds(ids []int) []int {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+// getBannersFromCache returns cached banners in the order of options, all of them are missing if the cache fails
+func (s *Service) getBannersFromCache(newCtx context.Context, options []models.BannerIdentOptions, locale string, log *slog.Logger) []*models.Candidate {
+	const op = "banner.getBannersFromCache"
+	banners, err := s.cache.GetMany(newCtx, options, locale)
+	if err != nil {
+		log.Warn(err.Error(), utils.Text(op))
+		return make([]*models.Candidate, len(options))
+	}
+	return banners
 }
 
-func (s *Service) SendBannerToCache(newCtx context.Context, options *models.BannerIdentOptions, banner *models.UserBanner, log *slog.Logger) {
+func (s *Service) SendBannerToCache(newCtx context.Context, options *models.BannerIdentOptions, locale string, banner *models.Candidate, log *slog.Logger) {
 	const op = "banner.SendBannerToCache"
 	defer s.wg.Done()
-	err := s.cache.Put(newCtx, options, banner)
+	err := s.cache.Put(newCtx, options, locale, banner)
 	if err != nil {
-		log.Warn(op, err.Error())
+		log.Warn(err.Error(), utils.Text(op))
 	}
 }
 
@@ -291,7 +617,7 @@ func (s *Service) ctxDone(ctx context.Context, log *slog.Logger) bool {
 	const op = "banner.ctxDone"
 	select {
 	case <-ctx.Done():
-		log.Info(op, "context done")
+		log.Info("context done", utils.Text(op))
 		return true
 	default:
 		return false
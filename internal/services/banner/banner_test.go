@@ -0,0 +1,224 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectPages makes the database store banners with ids from 1 and list them after the cursor by id recording the options
+func expectPages(db *mocks.MockDatabase, banners int, listed *[]models.BannerListOptions, counted *int) {
+	db.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
+		*listed = append(*listed, *options)
+		id := 1
+		if options.After != nil {
+			id = options.After.Id + 1
+		}
+		var page []models.BannerExt
+		for ; id <= banners && (options.Limit <= 0 || len(page) < options.Limit); id++ {
+			page = append(page, models.BannerExt{BannerId: id})
+		}
+		return page, nil
+	}).AnyTimes()
+	db.EXPECT().Count(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *models.BannerListOptions) (int, error) {
+		*counted++
+		return banners, nil
+	}).AnyTimes()
+}
+
+func TestListBannersPages(t *testing.T) {
+	var listed []models.BannerListOptions
+	counted := 0
+	db := newMockDatabase(t)
+	expectPages(db, 5, &listed, &counted)
+	srv := newTestService(db)
+	options := &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue},
+		Sort:               models.SortById,
+		Limit:              2,
+		Offset:             models.ZeroValue,
+	}
+	var pages [][]int
+	for {
+		page, err := srv.ListBanners(context.Background(), options)
+		require.NoError(t, err)
+		assert.Equal(t, models.ZeroValue, page.Total)
+		var ids []int
+		for _, banner := range page.Banners {
+			ids = append(ids, banner.BannerId)
+		}
+		pages = append(pages, ids)
+		if page.Next == nil {
+			break
+		}
+		options.After = page.Next
+	}
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, pages)
+	assert.Equal(t, 3, listed[0].Limit, "one more banner is fetched to find the next page")
+	assert.Equal(t, 0, counted)
+
+	options.After, options.WithTotal = nil, true
+	page, err := srv.ListBanners(context.Background(), options)
+	require.NoError(t, err)
+	assert.Equal(t, 5, page.Total)
+}
+
+func TestListBannersInvalidOptions(t *testing.T) {
+	cursor := &models.ListCursor{Sort: models.SortByCreated, Id: 3}
+	tests := []struct {
+		name       string
+		options    models.BannerListOptions
+		wantFields []string
+	}{
+		{
+			name:       "cursor of another sort",
+			options:    models.BannerListOptions{Sort: models.SortByCreated, Desc: true, After: cursor},
+			wantFields: []string{"cursor"},
+		},
+		{
+			name:       "cursor with offset",
+			options:    models.BannerListOptions{Sort: models.SortByCreated, After: cursor, Offset: 10},
+			wantFields: []string{"offset"},
+		},
+		{
+			name:       "unknown sort",
+			options:    models.BannerListOptions{Sort: "name"},
+			wantFields: []string{"sort"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// nothing is listed
+			db := newMockDatabase(t)
+			_, err := newTestService(db).ListBanners(context.Background(), &test.options)
+			var validationErr *e.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			assert.Equal(t, test.wantFields, fields)
+		})
+	}
+}
+
+func TestDeleteBannersByTagOrFeature(t *testing.T) {
+	byFeature := models.BulkDeleteFilter{FeatureId: 1, TagIds: []int{10, 11}, InactiveOnly: true}
+	tests := []struct {
+		name          string
+		banners       int
+		options       models.BulkDeleteOptions
+		wantErr       error
+		wantConfirm   bool
+		wantScheduled bool
+	}{
+		{name: "dry run", banners: 5, options: models.BulkDeleteOptions{BulkDeleteFilter: byFeature, DryRun: true}, wantConfirm: true},
+		{name: "below threshold", banners: 3, options: models.BulkDeleteOptions{BulkDeleteFilter: byFeature}, wantScheduled: true},
+		{name: "above threshold without token", banners: 5, options: models.BulkDeleteOptions{BulkDeleteFilter: byFeature}, wantErr: e.ErrorValidation},
+		{
+			name:          "above threshold with token",
+			banners:       5,
+			options:       models.BulkDeleteOptions{BulkDeleteFilter: byFeature, ConfirmToken: models.DeleteToken(&byFeature, []int{1, 2, 3, 4, 5})},
+			wantConfirm:   true,
+			wantScheduled: true,
+		},
+		{
+			name:    "banners changed since preview",
+			banners: 5,
+			options: models.BulkDeleteOptions{BulkDeleteFilter: byFeature, ConfirmToken: models.DeleteToken(&byFeature, []int{1, 2, 3, 4})},
+			wantErr: e.ErrorStaleConfirmation,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newMockDatabase(t)
+			// banners with ids from 1 are found for any filter
+			db.EXPECT().PreviewDelete(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *models.BulkDeleteFilter) ([]int, error) {
+				ids := []int{}
+				for id := 1; id <= test.banners; id++ {
+					ids = append(ids, id)
+				}
+				return ids, nil
+			})
+			srv := newTestService(db)
+			srv.confirmThreshold = 4
+			preview, err := srv.DeleteBannersByTagOrFeature(context.Background(), &test.options)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				assert.Empty(t, srv.tasksChan)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, preview.Ids, test.banners)
+			assert.Equal(t, models.DeleteToken(&byFeature, preview.Ids), preview.Token)
+			assert.Equal(t, test.wantConfirm, preview.ConfirmRequired)
+			if test.wantScheduled {
+				require.Len(t, srv.tasksChan, 1)
+				job := <-srv.tasksChan
+				assert.Equal(t, byFeature, job.Filter)
+				assert.Equal(t, preview.Ids, job.Ids, "only the previewed banners are deleted")
+				require.NotNil(t, preview.Job)
+				assert.Equal(t, job.Id, preview.Job.Id)
+				assert.Equal(t, models.DeleteJobPending, preview.Job.Status)
+			} else {
+				assert.Empty(t, srv.tasksChan)
+				assert.Nil(t, preview.Job)
+			}
+		})
+	}
+}
+
+func TestUpdateBannerErrors(t *testing.T) {
+	deactivate := models.UpdateBanner{Flags: models.IsActiveBit}
+	tests := []struct {
+		name      string
+		banner    models.UpdateBanner
+		ifMatch   string
+		updateErr error
+		wantErr   error
+	}{
+		{name: "updated", banner: deactivate},
+		{name: "missing banner", banner: deactivate, updateErr: e.ErrorNotFound, wantErr: e.ErrorNotFound},
+		{name: "stale etag", banner: deactivate, ifMatch: models.ETag(1, true), updateErr: e.ErrorPreconditionFailed, wantErr: e.ErrorPreconditionFailed},
+		{name: "failed", banner: deactivate, updateErr: errors.New("connection reset"), wantErr: e.ErrorInternal},
+		{
+			name:      "tags of another banner",
+			banner:    models.UpdateBanner{Banner: models.Banner{BaseBanner: models.BaseBanner{TagIds: []int{10, 11}}}, Flags: models.TagBit},
+			updateErr: e.ErrorConflict,
+			wantErr:   e.ErrorConflict,
+		},
+		{
+			name:      "feature of another banner",
+			banner:    models.UpdateBanner{Banner: models.Banner{BaseBanner: models.BaseBanner{FeatureId: 1}}, Flags: models.FeatureBit},
+			updateErr: e.ErrorConflict,
+			wantErr:   e.ErrorConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newMockDatabase(t)
+			expectRegistered(db)
+			expectNoSchemas(db)
+			db.EXPECT().GetById(gomock.Any(), 1).Return(&models.BannerExt{BannerId: 1, Banner: models.Banner{BaseBanner: models.BaseBanner{
+				UserBanner: models.UserBanner{Content: map[string]any{"title": "some_title"}},
+				FeatureId:  1,
+				TagIds:     []int{10},
+			}}}, nil).AnyTimes()
+			db.EXPECT().Update(gomock.Any(), 1, gomock.Any(), test.ifMatch).Return(test.updateErr)
+
+			err := newTestService(db).UpdateBanner(context.Background(), 1, &test.banner, test.ifMatch)
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	maxBatchSize    = 1000
+	operationsField = "operations"
+	bannerField     = "banner"
+)
+
+// BatchBanners applies operations through the same methods as single requests, so validation and history apply
+// to every item. Atomic batch stops at the first failure and rolls back, the rest of items get e.ErrorRolledBack
+func (s *Service) BatchBanners(ctx context.Context, batch *models.Batch) ([]models.BatchResult, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.BatchBanners"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	if len(batch.Operations) > maxBatchSize {
+		return nil, e.NewValidationError(e.FieldViolation{
+			Field:   operationsField,
+			Message: fmt.Sprintf("must contain at most %d items", maxBatchSize),
+		})
+	}
+	results := make([]models.BatchResult, len(batch.Operations))
+	if !batch.Atomic {
+		for i := range batch.Operations {
+			results[i] = s.applyOperation(ctx, &batch.Operations[i])
+		}
+		return results, nil
+	}
+	failed := -1
+	err := s.db.InTransaction(ctx, func(txCtx context.Context) error {
+		for i := range batch.Operations {
+			results[i] = s.applyOperation(txCtx, &batch.Operations[i])
+			if results[i].Err != nil {
+				failed = i
+				return results[i].Err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return results, nil
+	}
+	if failed < 0 {
+		log.Warn("failed to commit batch", utils.Err(err))
+		return nil, e.ErrorInternal
+	}
+	for i := range results {
+		if i != failed {
+			results[i] = models.BatchResult{Op: batch.Operations[i].Op, Id: batch.Operations[i].Id, Err: e.ErrorRolledBack}
+		}
+	}
+	return results, nil
+}
+
+func (s *Service) applyOperation(ctx context.Context, operation *models.BatchOperation) models.BatchResult {
+	result := models.BatchResult{Op: operation.Op, Id: operation.Id}
+	if result.Err = validateOperation(operation); result.Err != nil {
+		return result
+	}
+	switch operation.Op {
+	case models.BatchCreate:
+		result.Id, result.Err = s.CreateBanner(ctx, &operation.Banner.Banner)
+	case models.BatchUpdate:
+		result.Err = s.UpdateBanner(ctx, operation.Id, &operation.Banner, operation.IfMatch)
+	default:
+		result.Err = s.DeleteBanner(ctx, operation.Id, operation.IfMatch)
+	}
+	return result
+}
+
+// validateOperation checks fields required by the kind of operation, fields are relative to the item
+func validateOperation(operation *models.BatchOperation) error {
+	var violations []e.FieldViolation
+	switch operation.Op {
+	case models.BatchCreate:
+		required := []struct {
+			bit  int
+			name string
+		}{
+			{models.TagBit, "tag_ids"},
+			{models.FeatureBit, "feature_id"},
+			{models.ContentBit, "content"},
+			{models.IsActiveBit, "is_active"},
+		}
+		for _, field := range required {
+			if operation.Banner.Flags&field.bit == 0 {
+				violations = append(violations, e.FieldViolation{Field: bannerField + "." + field.name, Message: "is required"})
+			}
+		}
+	case models.BatchUpdate, models.BatchDelete:
+		if operation.Id <= 0 {
+			violations = append(violations, e.FieldViolation{Field: "id", Message: "is required"})
+		}
+		if operation.Op == models.BatchUpdate && operation.Banner.Flags == models.ZeroBit {
+			violations = append(violations, e.FieldViolation{Field: bannerField, Message: "all fields are empty"})
+		}
+	default:
+		violations = append(violations, e.FieldViolation{Field: "op", Message: "must be one of create, update, delete"})
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
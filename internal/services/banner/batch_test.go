@@ -0,0 +1,160 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const missingId = 404
+
+func createOperation(tagIds ...int) models.BatchOperation {
+	return models.BatchOperation{Op: models.BatchCreate, Banner: models.UpdateBanner{
+		Banner: models.Banner{BaseBanner: models.BaseBanner{
+			UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}},
+			FeatureId:  1,
+			TagIds:     tagIds,
+		}},
+		Flags: models.FeatureBit | models.TagBit | models.ContentBit | models.IsActiveBit,
+	}}
+}
+
+func TestBatchBanners(t *testing.T) {
+	activate := models.UpdateBanner{Banner: models.Banner{IsActive: true}, Flags: models.IsActiveBit}
+	tests := []struct {
+		name             string
+		atomic           bool
+		operations       []models.BatchOperation
+		wantErrs         []error
+		wantFields       map[int][]string
+		wantAdded        int
+		wantUpdated      []int
+		wantDeleted      []int
+		wantTransactions int
+	}{
+		{
+			name: "best effort applies every valid operation",
+			operations: []models.BatchOperation{
+				createOperation(10),
+				createOperation(99),
+				{Op: models.BatchUpdate, Id: 5, Banner: activate},
+				{Op: models.BatchDelete, Id: missingId},
+				{Op: models.BatchDelete, Id: 7},
+			},
+			wantErrs:    []error{nil, e.ErrorValidation, nil, e.ErrorNotFound, nil},
+			wantFields:  map[int][]string{1: {"tag_ids[0]"}},
+			wantAdded:   1,
+			wantUpdated: []int{5},
+			wantDeleted: []int{7},
+		},
+		{
+			name:   "atomic success",
+			atomic: true,
+			operations: []models.BatchOperation{
+				createOperation(10),
+				{Op: models.BatchUpdate, Id: 5, Banner: activate},
+				{Op: models.BatchDelete, Id: 7},
+			},
+			wantErrs:         []error{nil, nil, nil},
+			wantAdded:        1,
+			wantUpdated:      []int{5},
+			wantDeleted:      []int{7},
+			wantTransactions: 1,
+		},
+		{
+			name:   "atomic failure rolls back",
+			atomic: true,
+			operations: []models.BatchOperation{
+				createOperation(10),
+				{Op: models.BatchUpdate, Id: missingId, Banner: activate},
+				{Op: models.BatchDelete, Id: 7},
+			},
+			wantErrs:         []error{e.ErrorRolledBack, e.ErrorNotFound, e.ErrorRolledBack},
+			wantTransactions: 1,
+		},
+		{
+			name: "invalid operations",
+			operations: []models.BatchOperation{
+				{Op: models.BatchCreate, Banner: models.UpdateBanner{Flags: models.ContentBit}},
+				{Op: models.BatchUpdate},
+				{Op: "upsert", Id: 1},
+			},
+			wantErrs: []error{e.ErrorValidation, e.ErrorValidation, e.ErrorValidation},
+			wantFields: map[int][]string{
+				0: {"banner.tag_ids", "banner.feature_id", "banner.is_active"},
+				1: {"id", "banner"},
+				2: {"op"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var added []*models.Banner
+			var updated, deleted []int
+			transactions := 0
+			db := newMockDatabase(t)
+			expectRegistered(db)
+			expectNoSchemas(db)
+			// the failed transaction forgets its changes
+			db.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				transactions++
+				addedLen, updatedLen, deletedLen := len(added), len(updated), len(deleted)
+				err := fn(ctx)
+				if err != nil {
+					added, updated, deleted = added[:addedLen], updated[:updatedLen], deleted[:deletedLen]
+				}
+				return err
+			}).AnyTimes()
+			db.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, banner *models.Banner) (int, error) {
+				added = append(added, banner)
+				return len(added), nil
+			}).AnyTimes()
+			db.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int, _ *models.UpdateBanner, _ string) error {
+				if id == missingId {
+					return e.ErrorNotFound
+				}
+				updated = append(updated, id)
+				return nil
+			}).AnyTimes()
+			db.EXPECT().DeleteById(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int, _ string) error {
+				if id == missingId {
+					return e.ErrorNotFound
+				}
+				deleted = append(deleted, id)
+				return nil
+			}).AnyTimes()
+			results, err := newTestService(db).BatchBanners(context.Background(), &models.Batch{
+				Atomic:     test.atomic,
+				Operations: test.operations,
+			})
+			require.NoError(t, err)
+			require.Len(t, results, len(test.wantErrs))
+			for i, want := range test.wantErrs {
+				assert.Equal(t, test.operations[i].Op, results[i].Op)
+				if want == nil {
+					assert.NoError(t, results[i].Err, "operation %d", i)
+					continue
+				}
+				assert.ErrorIs(t, results[i].Err, want, "operation %d", i)
+				var validationErr *e.ValidationError
+				if wantFields := test.wantFields[i]; wantFields != nil {
+					require.ErrorAs(t, results[i].Err, &validationErr)
+					var fields []string
+					for _, violation := range validationErr.Violations {
+						fields = append(fields, violation.Field)
+					}
+					assert.Equal(t, wantFields, fields, "operation %d", i)
+				}
+			}
+			assert.Len(t, added, test.wantAdded)
+			assert.Equal(t, test.wantUpdated, updated)
+			assert.Equal(t, test.wantDeleted, deleted)
+			assert.Equal(t, test.wantTransactions, transactions)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/patch"
+	"BannerFlow/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync/atomic"
+)
+
+const (
+	featureIdField = "feature_id"
+	tagIdsField    = "tag_ids"
+)
+
+// DiffBannerVersions returns JSON Patch and summary of changes between versions of the banner,
+// ZeroValue version means the current state
+func (s *Service) DiffBannerVersions(ctx context.Context, id, from, to int) (*models.BannerDiff, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.DiffBannerVersions"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	fromState, err := s.bannerState(newCtx, id, from, log)
+	if err != nil {
+		return nil, err
+	}
+	toState, err := s.bannerState(newCtx, id, to, log)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BannerDiff{
+		From:    from,
+		To:      to,
+		Patch:   patch.Diff(diffDocument(fromState), diffDocument(toState)),
+		Summary: summarize(fromState, toState),
+	}, nil
+}
+
+func (s *Service) bannerState(ctx context.Context, id, version int, log *slog.Logger) (*models.BaseBanner, error) {
+	var state *models.BaseBanner
+	if version == models.ZeroValue {
+		banner, err := s.db.GetById(ctx, id)
+		if err == nil {
+			state = &banner.BaseBanner
+		}
+		return state, dbError(err, log)
+	}
+	history, err := s.db.GetHistoryVersion(ctx, id, version)
+	if err == nil {
+		state = &history.BaseBanner
+	}
+	return state, dbError(err, log)
+}
+
+// dbError passes not found error to the caller and hides the others
+func dbError(err error, log *slog.Logger) error {
+	if err == nil {
+		return nil
+	}
+	log.Warn(err.Error())
+	if errors.Is(err, e.ErrorNotFound) {
+		return err
+	}
+	return e.ErrorInternal
+}
+
+func diffDocument(banner *models.BaseBanner) map[string]any {
+	return map[string]any{
+		featureIdField: banner.FeatureId,
+		tagIdsField:    banner.TagIds,
+		contentField:   map[string]any(banner.Content),
+	}
+}
+
+// summarize describes changes in the form readable by reviewers
+func summarize(from, to *models.BaseBanner) []string {
+	summary := make([]string, 0)
+	if from.FeatureId != to.FeatureId {
+		summary = append(summary, fmt.Sprintf("%s: %d -> %d", featureIdField, from.FeatureId, to.FeatureId))
+	}
+	added, removed := difference(to.TagIds, from.TagIds), difference(from.TagIds, to.TagIds)
+	if len(added) > 0 {
+		summary = append(summary, fmt.Sprintf("%s: added %v", tagIdsField, added))
+	}
+	if len(removed) > 0 {
+		summary = append(summary, fmt.Sprintf("%s: removed %v", tagIdsField, removed))
+	}
+	for _, operation := range patch.Diff(map[string]any(from.Content), map[string]any(to.Content)) {
+		field := contentField
+		if operation.Path != "" {
+			field += "." + patch.Field(operation.Path)
+		}
+		switch operation.Op {
+		case patch.OpAdd:
+			summary = append(summary, fmt.Sprintf("%s: added %s", field, jsonText(operation.Value)))
+		case patch.OpRemove:
+			summary = append(summary, fmt.Sprintf("%s: removed", field))
+		case patch.OpReplace:
+			old, _ := lookup(map[string]any(from.Content), patch.Split(operation.Path))
+			summary = append(summary, fmt.Sprintf("%s: %s -> %s", field, jsonText(old), jsonText(operation.Value)))
+		}
+	}
+	return summary
+}
+
+// difference returns sorted ids of a which are missing in b
+func difference(a, b []int) []int {
+	exists := make(map[int]bool, len(b))
+	for _, id := range b {
+		exists[id] = true
+	}
+	result := make([]int, 0)
+	for _, id := range a {
+		if !exists[id] {
+			result = append(result, id)
+			exists[id] = true
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+func lookup(value any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[token]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func jsonText(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	const maxLen = 80
+	if text := []rune(string(b)); len(text) > maxLen {
+		return string(text[:maxLen]) + "..."
+	}
+	return string(b)
+}
@@ -0,0 +1,64 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/patch"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDiffBannerVersions(t *testing.T) {
+	current := models.BaseBanner{
+		FeatureId:  2,
+		TagIds:     []int{1, 3},
+		UserBanner: models.UserBanner{Content: map[string]any{"title": "Black Friday", "url": "https://example.com"}},
+	}
+	versions := map[int]models.BaseBanner{
+		1: {
+			FeatureId:  1,
+			TagIds:     []int{1, 2},
+			UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale", "text": "old"}},
+		},
+	}
+	db := newMockDatabase(t)
+	db.EXPECT().GetById(gomock.Any(), 1).Return(&models.BannerExt{BannerId: 1, Banner: models.Banner{BaseBanner: current}}, nil).AnyTimes()
+	db.EXPECT().GetHistoryVersion(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, _, version int) (*models.HistoryBanner, error) {
+		banner, ok := versions[version]
+		if !ok {
+			return nil, e.ErrorNotFound
+		}
+		return &models.HistoryBanner{BaseBanner: banner, Version: version}, nil
+	}).AnyTimes()
+	srv := newTestService(db)
+
+	diff, err := srv.DiffBannerVersions(context.Background(), 1, 1, models.ZeroValue)
+	require.NoError(t, err)
+	assert.Equal(t, []patch.Operation{
+		{Op: patch.OpRemove, Path: "/content/text"},
+		{Op: patch.OpReplace, Path: "/content/title", Value: "Black Friday"},
+		{Op: patch.OpAdd, Path: "/content/url", Value: "https://example.com"},
+		{Op: patch.OpReplace, Path: "/feature_id", Value: 2},
+		{Op: patch.OpReplace, Path: "/tag_ids", Value: []int{1, 3}},
+	}, diff.Patch)
+	assert.Equal(t, []string{
+		"feature_id: 1 -> 2",
+		"tag_ids: added [3]",
+		"tag_ids: removed [2]",
+		"content.text: removed",
+		`content.title: "Sale" -> "Black Friday"`,
+		`content.url: added "https://example.com"`,
+	}, diff.Summary)
+
+	diff, err = srv.DiffBannerVersions(context.Background(), 1, 1, 1)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Patch)
+	assert.Empty(t, diff.Summary)
+
+	_, err = srv.DiffBannerVersions(context.Background(), 1, 5, models.ZeroValue)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+}
@@ -0,0 +1,40 @@
+package banner
+
+import (
+	"BannerFlow/internal/utils"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runHistoryPruning periodically removes banner versions exceeding retention until the service is stopped
+func (s *Service) runHistoryPruning() {
+	const op = "banner.runHistoryPruning"
+	if s.retention.MaxVersions <= 0 && s.retention.MaxAge <= 0 || s.pruneInterval <= 0 {
+		return
+	}
+	log := s.logger.With(utils.Text(op))
+	ticker := time.NewTicker(s.pruneInterval)
+	defer ticker.Stop()
+	for {
+		s.pruneHistory(log)
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) pruneHistory(log *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	removed, err := s.db.PruneHistory(ctx, &s.retention)
+	if err != nil {
+		log.Warn("failed to prune history", utils.Err(err))
+		return
+	}
+	if removed > 0 {
+		log.Info("history pruned", slog.Int64("removed", removed))
+	}
+}
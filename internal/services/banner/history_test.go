@@ -0,0 +1,88 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectHistory makes the database return pages of the history and count prunes
+func expectHistory(db *mocks.MockDatabase, history *[]models.HistoryBanner, pruned *atomic.Int32) {
+	db.EXPECT().GetHistoryForId(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int, options *models.HistoryListOptions) ([]models.HistoryBanner, error) {
+			result := *history
+			if options.Offset > models.ZeroValue {
+				result = result[min(options.Offset, len(result)):]
+			}
+			if options.Limit > models.ZeroValue {
+				result = result[:min(options.Limit, len(result))]
+			}
+			return result, nil
+		}).AnyTimes()
+	db.EXPECT().PruneHistory(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *models.HistoryRetention) (int64, error) {
+		pruned.Add(1)
+		return 1, nil
+	}).AnyTimes()
+}
+
+func TestListBannerHistory(t *testing.T) {
+	history := []models.HistoryBanner{{Version: 1}, {Version: 2}, {Version: 3}}
+	db := newMockDatabase(t)
+	expectHistory(db, &history, &atomic.Int32{})
+	srv := newTestService(db)
+
+	page, err := srv.ListBannerHistory(context.Background(), 1, &models.HistoryListOptions{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, 2, page[0].Version)
+
+	page, err = srv.ListBannerHistory(context.Background(), 1, &models.HistoryListOptions{Limit: 2, Offset: 3})
+	require.NoError(t, err, "page after the last one is empty")
+	assert.Empty(t, page)
+
+	history = nil
+	_, err = srv.ListBannerHistory(context.Background(), 1, &models.HistoryListOptions{Limit: models.ZeroValue, Offset: models.ZeroValue})
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+}
+
+func TestHistoryPruning(t *testing.T) {
+	tests := []struct {
+		name      string
+		history   config.HistoryConfig
+		wantPrune bool
+	}{
+		{name: "unlimited", history: config.HistoryConfig{PruneInterval: time.Millisecond}},
+		{name: "by count", history: config.HistoryConfig{MaxVersions: 3, PruneInterval: time.Millisecond}, wantPrune: true},
+		{name: "by age", history: config.HistoryConfig{MaxAge: time.Hour, PruneInterval: time.Millisecond}, wantPrune: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var pruned atomic.Int32
+			db := newMockDatabase(t)
+			expectHistory(db, &[]models.HistoryBanner{}, &pruned)
+			srv := newConfiguredService(db, nil, config.ServiceConfig{History: test.history})
+			done := make(chan struct{})
+			go func() {
+				srv.runHistoryPruning()
+				close(done)
+			}()
+			time.Sleep(20 * time.Millisecond)
+			srv.Stop(context.Background())
+			<-done
+			if test.wantPrune {
+				assert.Greater(t, pruned.Load(), int32(1))
+			} else {
+				assert.Zero(t, pruned.Load())
+			}
+		})
+	}
+}
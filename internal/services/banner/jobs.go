@@ -0,0 +1,125 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxDeleteJobs limits jobs kept for GetDeleteJob, the oldest finished of them are forgotten first
+const maxDeleteJobs = 1000
+
+// deleteJobs keeps bulk delete jobs of this instance in memory, callers get copies of them
+type deleteJobs struct {
+	mu    sync.Mutex
+	next  int
+	jobs  map[int]*models.DeleteJob
+	order []int
+}
+
+func newDeleteJobs() *deleteJobs {
+	return &deleteJobs{jobs: make(map[int]*models.DeleteJob)}
+}
+
+func (j *deleteJobs) add(tenant string, filter models.BulkDeleteFilter, ids []int) *models.DeleteJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.next++
+	job := &models.DeleteJob{
+		Id:        j.next,
+		Tenant:    tenant,
+		Filter:    filter,
+		Ids:       ids,
+		Status:    models.DeleteJobPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	j.jobs[job.Id] = job
+	j.order = append(j.order, job.Id)
+	if len(j.order) > maxDeleteJobs {
+		for i, id := range j.order {
+			if j.jobs[id].Status != models.DeleteJobPending {
+				delete(j.jobs, id)
+				j.order = slices.Delete(j.order, i, i+1)
+				break
+			}
+		}
+	}
+	copied := *job
+	return &copied
+}
+
+func (j *deleteJobs) finish(id int, removedIds []int, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return
+	}
+	job.Status = models.DeleteJobDone
+	if err != nil {
+		job.Status = models.DeleteJobFailed
+	}
+	job.RemovedIds = removedIds
+	job.FinishedAt = time.Now().UTC()
+}
+
+func (j *deleteJobs) get(id int) (*models.DeleteJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *job
+	return &copied, true
+}
+
+// GetDeleteJob returns the bulk delete job of the request tenant scheduled by this instance with ids of removed banners
+func (s *Service) GetDeleteJob(ctx context.Context, id int) (*models.DeleteJob, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetDeleteJob"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	job, ok := s.jobs.get(id)
+	if !ok || job.Tenant != identity.From(ctx).Tenant {
+		return nil, e.ErrorNotFound
+	}
+	return job, nil
+}
+
+// scheduleDeletion passes the deletion of the banners of the request tenant found by the filter to the job
+// started by MustRun. Banners matching the filter later are not deleted by it
+func (s *Service) scheduleDeletion(ctx context.Context, filter models.BulkDeleteFilter, ids []int) *models.DeleteJob {
+	job := s.jobs.add(identity.From(ctx).Tenant, filter, ids)
+	s.wg.Add(1)
+	s.tasksChan <- job
+	return job
+}
+
+// runDeleteJob deletes banners of the job and records the removed ones
+func (s *Service) runDeleteJob(job *models.DeleteJob) {
+	const op = "banner.runDeleteJob"
+	defer s.wg.Done()
+	log := s.logger.With(utils.Text(op), slog.Int("job", job.Id))
+	// the request has ended, so the job deletes banners of its tenant on behalf of nobody
+	ctx := identity.With(context.Background(), &identity.Identity{Tenant: job.Tenant})
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	ids, err := s.db.DeleteByIds(ctx, job.Ids)
+	s.jobs.finish(job.Id, ids, err)
+	if err != nil {
+		log.Warn("failed to delete banners", utils.Err(err))
+		return
+	}
+	log.Info("banners deleted", slog.Any("ids", ids))
+}
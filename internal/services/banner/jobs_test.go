@@ -0,0 +1,86 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteJob(t *testing.T) {
+	tests := []struct {
+		name        string
+		deleteErr   error
+		wantStatus  models.DeleteJobStatus
+		wantRemoved []int
+	}{
+		{name: "done", wantStatus: models.DeleteJobDone, wantRemoved: []int{3, 7}},
+		{name: "failed", deleteErr: errors.New("connection reset"), wantStatus: models.DeleteJobFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var deleted [][]int
+			db := newMockDatabase(t)
+			// every banner given is removed
+			db.EXPECT().DeleteByIds(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ids []int) ([]int, error) {
+				deleted = append(deleted, ids)
+				if test.deleteErr != nil {
+					return nil, test.deleteErr
+				}
+				return ids, nil
+			})
+			srv := newTestService(db)
+			filter := models.BulkDeleteFilter{FeatureId: 1}
+			scheduled := srv.scheduleDeletion(context.Background(), filter, []int{3, 7})
+
+			job, err := srv.GetDeleteJob(context.Background(), scheduled.Id)
+			require.NoError(t, err)
+			assert.Equal(t, models.DeleteJobPending, job.Status)
+			assert.True(t, job.FinishedAt.IsZero())
+
+			srv.runDeleteJob(<-srv.tasksChan)
+			srv.wg.Wait()
+			assert.Equal(t, [][]int{{3, 7}}, deleted, "only banners of the job are deleted")
+			job, err = srv.GetDeleteJob(context.Background(), scheduled.Id)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantStatus, job.Status)
+			assert.Equal(t, test.wantRemoved, job.RemovedIds)
+			assert.False(t, job.FinishedAt.IsZero())
+			assert.Equal(t, models.DeleteJobPending, scheduled.Status, "callers get copies of jobs")
+		})
+	}
+}
+
+func TestGetDeleteJobNotFound(t *testing.T) {
+	_, err := newTestService(newMockDatabase(t)).GetDeleteJob(context.Background(), 1)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+}
+
+func TestDeleteJobsForgetOldest(t *testing.T) {
+	jobs := newDeleteJobs()
+	pending := jobs.add("", models.BulkDeleteFilter{FeatureId: 1}, []int{1})
+	finished := jobs.add("", models.BulkDeleteFilter{FeatureId: 2}, []int{1})
+	jobs.finish(finished.Id, []int{1}, nil)
+	for i := 0; i < maxDeleteJobs-1; i++ {
+		jobs.add("", models.BulkDeleteFilter{FeatureId: 3}, []int{1})
+	}
+	_, ok := jobs.get(pending.Id)
+	assert.True(t, ok, "pending jobs are kept")
+	_, ok = jobs.get(finished.Id)
+	assert.False(t, ok)
+	assert.Len(t, jobs.jobs, maxDeleteJobs)
+}
+
+func TestGetDeleteJobOfAnotherTenant(t *testing.T) {
+	service := newTestService(newMockDatabase(t))
+	job := service.jobs.add("first", models.BulkDeleteFilter{FeatureId: 1}, []int{1})
+	other := identity.With(context.Background(), &identity.Identity{Tenant: "second"})
+	_, err := service.GetDeleteJob(other, job.Id)
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+}
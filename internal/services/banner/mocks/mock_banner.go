@@ -0,0 +1,976 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: banner.go
+//
+// Generated by this command:
+//
+//	mockgen -source=banner.go -package=mocks -destination=./mocks/mock_banner.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "BannerFlow/internal/domain/models"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDatabase is a mock of Database interface.
+type MockDatabase struct {
+	ctrl     *gomock.Controller
+	recorder *MockDatabaseMockRecorder
+}
+
+// MockDatabaseMockRecorder is the mock recorder for MockDatabase.
+type MockDatabaseMockRecorder struct {
+	mock *MockDatabase
+}
+
+// NewMockDatabase creates a new mock instance.
+func NewMockDatabase(ctrl *gomock.Controller) *MockDatabase {
+	mock := &MockDatabase{ctrl: ctrl}
+	mock.recorder = &MockDatabaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockDatabase) Add(ctx context.Context, banner *models.Banner) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, banner)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockDatabaseMockRecorder) Add(ctx, banner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDatabase)(nil).Add), ctx, banner)
+}
+
+// AddDraft mocks base method.
+func (m *MockDatabase) AddDraft(ctx context.Context, banner *models.Banner) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDraft", ctx, banner)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDraft indicates an expected call of AddDraft.
+func (mr *MockDatabaseMockRecorder) AddDraft(ctx, banner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDraft", reflect.TypeOf((*MockDatabase)(nil).AddDraft), ctx, banner)
+}
+
+// AddRegistryEntry mocks base method.
+func (m *MockDatabase) AddRegistryEntry(ctx context.Context, registry models.Registry, entry *models.RegistryEntry) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRegistryEntry", ctx, registry, entry)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRegistryEntry indicates an expected call of AddRegistryEntry.
+func (mr *MockDatabaseMockRecorder) AddRegistryEntry(ctx, registry, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRegistryEntry", reflect.TypeOf((*MockDatabase)(nil).AddRegistryEntry), ctx, registry, entry)
+}
+
+// AddWebhook mocks base method.
+func (m *MockDatabase) AddWebhook(ctx context.Context, webhook *models.Webhook) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWebhook", ctx, webhook)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWebhook indicates an expected call of AddWebhook.
+func (mr *MockDatabaseMockRecorder) AddWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhook", reflect.TypeOf((*MockDatabase)(nil).AddWebhook), ctx, webhook)
+}
+
+// BannerChangedSince mocks base method.
+func (m *MockDatabase) BannerChangedSince(ctx context.Context, featureId int, tagIds []int, after int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BannerChangedSince", ctx, featureId, tagIds, after)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BannerChangedSince indicates an expected call of BannerChangedSince.
+func (mr *MockDatabaseMockRecorder) BannerChangedSince(ctx, featureId, tagIds, after any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BannerChangedSince", reflect.TypeOf((*MockDatabase)(nil).BannerChangedSince), ctx, featureId, tagIds, after)
+}
+
+// ChangeRevision mocks base method.
+func (m *MockDatabase) ChangeRevision(ctx context.Context, id int, ifMatch string, change func(*models.PendingRevision) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRevision", ctx, id, ifMatch, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeRevision indicates an expected call of ChangeRevision.
+func (mr *MockDatabaseMockRecorder) ChangeRevision(ctx, id, ifMatch, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRevision", reflect.TypeOf((*MockDatabase)(nil).ChangeRevision), ctx, id, ifMatch, change)
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockDatabase) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]models.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockDatabaseMockRecorder) ClaimDeliveries(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockDatabase)(nil).ClaimDeliveries), ctx, limit, lease)
+}
+
+// Count mocks base method.
+func (m *MockDatabase) Count(ctx context.Context, options *models.BannerListOptions) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, options)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockDatabaseMockRecorder) Count(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockDatabase)(nil).Count), ctx, options)
+}
+
+// CountSearch mocks base method.
+func (m *MockDatabase) CountSearch(ctx context.Context, options *models.BannerSearchOptions) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearch", ctx, options)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearch indicates an expected call of CountSearch.
+func (mr *MockDatabaseMockRecorder) CountSearch(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearch", reflect.TypeOf((*MockDatabase)(nil).CountSearch), ctx, options)
+}
+
+// DeleteById mocks base method.
+func (m *MockDatabase) DeleteById(ctx context.Context, id int, ifMatch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", ctx, id, ifMatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockDatabaseMockRecorder) DeleteById(ctx, id, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockDatabase)(nil).DeleteById), ctx, id, ifMatch)
+}
+
+// DeleteByIds mocks base method.
+func (m *MockDatabase) DeleteByIds(ctx context.Context, ids []int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByIds", ctx, ids)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByIds indicates an expected call of DeleteByIds.
+func (mr *MockDatabaseMockRecorder) DeleteByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByIds", reflect.TypeOf((*MockDatabase)(nil).DeleteByIds), ctx, ids)
+}
+
+// DeleteFeatureSchema mocks base method.
+func (m *MockDatabase) DeleteFeatureSchema(ctx context.Context, featureId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeatureSchema", ctx, featureId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeatureSchema indicates an expected call of DeleteFeatureSchema.
+func (mr *MockDatabaseMockRecorder) DeleteFeatureSchema(ctx, featureId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeatureSchema", reflect.TypeOf((*MockDatabase)(nil).DeleteFeatureSchema), ctx, featureId)
+}
+
+// DeleteRegistryEntry mocks base method.
+func (m *MockDatabase) DeleteRegistryEntry(ctx context.Context, registry models.Registry, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRegistryEntry", ctx, registry, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRegistryEntry indicates an expected call of DeleteRegistryEntry.
+func (mr *MockDatabaseMockRecorder) DeleteRegistryEntry(ctx, registry, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRegistryEntry", reflect.TypeOf((*MockDatabase)(nil).DeleteRegistryEntry), ctx, registry, id)
+}
+
+// DeleteTargeting mocks base method.
+func (m *MockDatabase) DeleteTargeting(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTargeting", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTargeting indicates an expected call of DeleteTargeting.
+func (mr *MockDatabaseMockRecorder) DeleteTargeting(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTargeting", reflect.TypeOf((*MockDatabase)(nil).DeleteTargeting), ctx, id)
+}
+
+// DeleteTranslation mocks base method.
+func (m *MockDatabase) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranslation", ctx, id, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranslation indicates an expected call of DeleteTranslation.
+func (mr *MockDatabaseMockRecorder) DeleteTranslation(ctx, id, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranslation", reflect.TypeOf((*MockDatabase)(nil).DeleteTranslation), ctx, id, locale)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockDatabase) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockDatabaseMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockDatabase)(nil).DeleteWebhook), ctx, id)
+}
+
+// FindTranslations mocks base method.
+func (m *MockDatabase) FindTranslations(ctx context.Context, ids []int, locales []string) ([]models.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTranslations", ctx, ids, locales)
+	ret0, _ := ret[0].([]models.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTranslations indicates an expected call of FindTranslations.
+func (mr *MockDatabaseMockRecorder) FindTranslations(ctx, ids, locales any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTranslations", reflect.TypeOf((*MockDatabase)(nil).FindTranslations), ctx, ids, locales)
+}
+
+// GetById mocks base method.
+func (m *MockDatabase) GetById(ctx context.Context, id int) (*models.BannerExt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*models.BannerExt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockDatabaseMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockDatabase)(nil).GetById), ctx, id)
+}
+
+// GetFeatureSchema mocks base method.
+func (m *MockDatabase) GetFeatureSchema(ctx context.Context, featureId int) (*models.FeatureSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureSchema", ctx, featureId)
+	ret0, _ := ret[0].(*models.FeatureSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureSchema indicates an expected call of GetFeatureSchema.
+func (mr *MockDatabaseMockRecorder) GetFeatureSchema(ctx, featureId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureSchema", reflect.TypeOf((*MockDatabase)(nil).GetFeatureSchema), ctx, featureId)
+}
+
+// GetHistoryForId mocks base method.
+func (m *MockDatabase) GetHistoryForId(ctx context.Context, id int, options *models.HistoryListOptions) ([]models.HistoryBanner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoryForId", ctx, id, options)
+	ret0, _ := ret[0].([]models.HistoryBanner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoryForId indicates an expected call of GetHistoryForId.
+func (mr *MockDatabaseMockRecorder) GetHistoryForId(ctx, id, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryForId", reflect.TypeOf((*MockDatabase)(nil).GetHistoryForId), ctx, id, options)
+}
+
+// GetHistoryVersion mocks base method.
+func (m *MockDatabase) GetHistoryVersion(ctx context.Context, id, version int) (*models.HistoryBanner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoryVersion", ctx, id, version)
+	ret0, _ := ret[0].(*models.HistoryBanner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoryVersion indicates an expected call of GetHistoryVersion.
+func (mr *MockDatabaseMockRecorder) GetHistoryVersion(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryVersion", reflect.TypeOf((*MockDatabase)(nil).GetHistoryVersion), ctx, id, version)
+}
+
+// GetRegistryEntry mocks base method.
+func (m *MockDatabase) GetRegistryEntry(ctx context.Context, registry models.Registry, id int) (*models.RegistryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistryEntry", ctx, registry, id)
+	ret0, _ := ret[0].(*models.RegistryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistryEntry indicates an expected call of GetRegistryEntry.
+func (mr *MockDatabaseMockRecorder) GetRegistryEntry(ctx, registry, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistryEntry", reflect.TypeOf((*MockDatabase)(nil).GetRegistryEntry), ctx, registry, id)
+}
+
+// GetRegistryEntryByName mocks base method.
+func (m *MockDatabase) GetRegistryEntryByName(ctx context.Context, registry models.Registry, name string) (*models.RegistryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistryEntryByName", ctx, registry, name)
+	ret0, _ := ret[0].(*models.RegistryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistryEntryByName indicates an expected call of GetRegistryEntryByName.
+func (mr *MockDatabaseMockRecorder) GetRegistryEntryByName(ctx, registry, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistryEntryByName", reflect.TypeOf((*MockDatabase)(nil).GetRegistryEntryByName), ctx, registry, name)
+}
+
+// GetRevision mocks base method.
+func (m *MockDatabase) GetRevision(ctx context.Context, id int) (*models.PendingRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id)
+	ret0, _ := ret[0].(*models.PendingRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockDatabaseMockRecorder) GetRevision(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockDatabase)(nil).GetRevision), ctx, id)
+}
+
+// GetTargeting mocks base method.
+func (m *MockDatabase) GetTargeting(ctx context.Context, id int) (*models.Targeting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargeting", ctx, id)
+	ret0, _ := ret[0].(*models.Targeting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargeting indicates an expected call of GetTargeting.
+func (mr *MockDatabaseMockRecorder) GetTargeting(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargeting", reflect.TypeOf((*MockDatabase)(nil).GetTargeting), ctx, id)
+}
+
+// GetWebhook mocks base method.
+func (m *MockDatabase) GetWebhook(ctx context.Context, id int) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockDatabaseMockRecorder) GetWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockDatabase)(nil).GetWebhook), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MockDatabase) Import(ctx context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions, report *models.ImportReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, next, options, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockDatabaseMockRecorder) Import(ctx, next, options, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockDatabase)(nil).Import), ctx, next, options, report)
+}
+
+// InTransaction mocks base method.
+func (m *MockDatabase) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTransaction indicates an expected call of InTransaction.
+func (mr *MockDatabaseMockRecorder) InTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTransaction", reflect.TypeOf((*MockDatabase)(nil).InTransaction), ctx, fn)
+}
+
+// LastBannerEventId mocks base method.
+func (m *MockDatabase) LastBannerEventId(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastBannerEventId", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastBannerEventId indicates an expected call of LastBannerEventId.
+func (mr *MockDatabaseMockRecorder) LastBannerEventId(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastBannerEventId", reflect.TypeOf((*MockDatabase)(nil).LastBannerEventId), ctx)
+}
+
+// List mocks base method.
+func (m *MockDatabase) List(ctx context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, options)
+	ret0, _ := ret[0].([]models.BannerExt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDatabaseMockRecorder) List(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDatabase)(nil).List), ctx, options)
+}
+
+// ListBannerEvents mocks base method.
+func (m *MockDatabase) ListBannerEvents(ctx context.Context, after int64, limit int) ([]models.BannerEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBannerEvents", ctx, after, limit)
+	ret0, _ := ret[0].([]models.BannerEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBannerEvents indicates an expected call of ListBannerEvents.
+func (mr *MockDatabaseMockRecorder) ListBannerEvents(ctx, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBannerEvents", reflect.TypeOf((*MockDatabase)(nil).ListBannerEvents), ctx, after, limit)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockDatabase) ListDeliveries(ctx context.Context, options *models.DeliveryListOptions) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, options)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockDatabaseMockRecorder) ListDeliveries(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockDatabase)(nil).ListDeliveries), ctx, options)
+}
+
+// ListEach mocks base method.
+func (m *MockDatabase) ListEach(ctx context.Context, options *models.BannerListOptions, fn func(*models.BannerExt) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEach", ctx, options, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListEach indicates an expected call of ListEach.
+func (mr *MockDatabaseMockRecorder) ListEach(ctx, options, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEach", reflect.TypeOf((*MockDatabase)(nil).ListEach), ctx, options, fn)
+}
+
+// ListFeatureSchemas mocks base method.
+func (m *MockDatabase) ListFeatureSchemas(ctx context.Context) ([]models.FeatureSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeatureSchemas", ctx)
+	ret0, _ := ret[0].([]models.FeatureSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeatureSchemas indicates an expected call of ListFeatureSchemas.
+func (mr *MockDatabaseMockRecorder) ListFeatureSchemas(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeatureSchemas", reflect.TypeOf((*MockDatabase)(nil).ListFeatureSchemas), ctx)
+}
+
+// ListRegistry mocks base method.
+func (m *MockDatabase) ListRegistry(ctx context.Context, registry models.Registry) ([]models.RegistryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRegistry", ctx, registry)
+	ret0, _ := ret[0].([]models.RegistryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRegistry indicates an expected call of ListRegistry.
+func (mr *MockDatabaseMockRecorder) ListRegistry(ctx, registry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRegistry", reflect.TypeOf((*MockDatabase)(nil).ListRegistry), ctx, registry)
+}
+
+// ListReviews mocks base method.
+func (m *MockDatabase) ListReviews(ctx context.Context, options *models.ReviewListOptions) ([]models.PendingRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReviews", ctx, options)
+	ret0, _ := ret[0].([]models.PendingRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReviews indicates an expected call of ListReviews.
+func (mr *MockDatabaseMockRecorder) ListReviews(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviews", reflect.TypeOf((*MockDatabase)(nil).ListReviews), ctx, options)
+}
+
+// ListTargeting mocks base method.
+func (m *MockDatabase) ListTargeting(ctx context.Context, ids []int) ([]models.Targeting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTargeting", ctx, ids)
+	ret0, _ := ret[0].([]models.Targeting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTargeting indicates an expected call of ListTargeting.
+func (mr *MockDatabaseMockRecorder) ListTargeting(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTargeting", reflect.TypeOf((*MockDatabase)(nil).ListTargeting), ctx, ids)
+}
+
+// ListTranslations mocks base method.
+func (m *MockDatabase) ListTranslations(ctx context.Context, id int) ([]models.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranslations", ctx, id)
+	ret0, _ := ret[0].([]models.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranslations indicates an expected call of ListTranslations.
+func (mr *MockDatabaseMockRecorder) ListTranslations(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranslations", reflect.TypeOf((*MockDatabase)(nil).ListTranslations), ctx, id)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockDatabase) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockDatabaseMockRecorder) ListWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockDatabase)(nil).ListWebhooks), ctx)
+}
+
+// ListenBannerEvents mocks base method.
+func (m *MockDatabase) ListenBannerEvents(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenBannerEvents", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenBannerEvents indicates an expected call of ListenBannerEvents.
+func (mr *MockDatabaseMockRecorder) ListenBannerEvents(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenBannerEvents", reflect.TypeOf((*MockDatabase)(nil).ListenBannerEvents), ctx, fn)
+}
+
+// MissingRegistryIds mocks base method.
+func (m *MockDatabase) MissingRegistryIds(ctx context.Context, registry models.Registry, ids ...int) ([]int, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, registry}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MissingRegistryIds", varargs...)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MissingRegistryIds indicates an expected call of MissingRegistryIds.
+func (mr *MockDatabaseMockRecorder) MissingRegistryIds(ctx, registry any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, registry}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MissingRegistryIds", reflect.TypeOf((*MockDatabase)(nil).MissingRegistryIds), varargs...)
+}
+
+// MissingTranslations mocks base method.
+func (m *MockDatabase) MissingTranslations(ctx context.Context, featureId int, locales []string) ([]models.MissingTranslations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MissingTranslations", ctx, featureId, locales)
+	ret0, _ := ret[0].([]models.MissingTranslations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MissingTranslations indicates an expected call of MissingTranslations.
+func (mr *MockDatabaseMockRecorder) MissingTranslations(ctx, featureId, locales any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MissingTranslations", reflect.TypeOf((*MockDatabase)(nil).MissingTranslations), ctx, featureId, locales)
+}
+
+// PreviewDelete mocks base method.
+func (m *MockDatabase) PreviewDelete(ctx context.Context, filter *models.BulkDeleteFilter) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewDelete", ctx, filter)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewDelete indicates an expected call of PreviewDelete.
+func (mr *MockDatabaseMockRecorder) PreviewDelete(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewDelete", reflect.TypeOf((*MockDatabase)(nil).PreviewDelete), ctx, filter)
+}
+
+// PruneBannerEvents mocks base method.
+func (m *MockDatabase) PruneBannerEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneBannerEvents", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneBannerEvents indicates an expected call of PruneBannerEvents.
+func (mr *MockDatabaseMockRecorder) PruneBannerEvents(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneBannerEvents", reflect.TypeOf((*MockDatabase)(nil).PruneBannerEvents), ctx, retention)
+}
+
+// PruneDeliveries mocks base method.
+func (m *MockDatabase) PruneDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneDeliveries", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneDeliveries indicates an expected call of PruneDeliveries.
+func (mr *MockDatabaseMockRecorder) PruneDeliveries(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneDeliveries", reflect.TypeOf((*MockDatabase)(nil).PruneDeliveries), ctx, retention)
+}
+
+// PruneHistory mocks base method.
+func (m *MockDatabase) PruneHistory(ctx context.Context, retention *models.HistoryRetention) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneHistory", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneHistory indicates an expected call of PruneHistory.
+func (mr *MockDatabaseMockRecorder) PruneHistory(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneHistory", reflect.TypeOf((*MockDatabase)(nil).PruneHistory), ctx, retention)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockDatabase) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockDatabaseMockRecorder) PurgeTrash(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockDatabase)(nil).PurgeTrash), ctx, retention)
+}
+
+// PutFeatureSchema mocks base method.
+func (m *MockDatabase) PutFeatureSchema(ctx context.Context, schema *models.FeatureSchema) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutFeatureSchema", ctx, schema)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutFeatureSchema indicates an expected call of PutFeatureSchema.
+func (mr *MockDatabaseMockRecorder) PutFeatureSchema(ctx, schema any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutFeatureSchema", reflect.TypeOf((*MockDatabase)(nil).PutFeatureSchema), ctx, schema)
+}
+
+// PutTargeting mocks base method.
+func (m *MockDatabase) PutTargeting(ctx context.Context, targeting *models.Targeting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutTargeting", ctx, targeting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutTargeting indicates an expected call of PutTargeting.
+func (mr *MockDatabaseMockRecorder) PutTargeting(ctx, targeting any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutTargeting", reflect.TypeOf((*MockDatabase)(nil).PutTargeting), ctx, targeting)
+}
+
+// PutTranslation mocks base method.
+func (m *MockDatabase) PutTranslation(ctx context.Context, translation *models.Translation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutTranslation", ctx, translation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutTranslation indicates an expected call of PutTranslation.
+func (mr *MockDatabaseMockRecorder) PutTranslation(ctx, translation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutTranslation", reflect.TypeOf((*MockDatabase)(nil).PutTranslation), ctx, translation)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockDatabase) RecordAttempt(ctx context.Context, attempt *models.DeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockDatabaseMockRecorder) RecordAttempt(ctx, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockDatabase)(nil).RecordAttempt), ctx, attempt)
+}
+
+// Restore mocks base method.
+func (m *MockDatabase) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockDatabaseMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockDatabase)(nil).Restore), ctx, id)
+}
+
+// RetryDelivery mocks base method.
+func (m *MockDatabase) RetryDelivery(ctx context.Context, webhookId int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDelivery", ctx, webhookId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDelivery indicates an expected call of RetryDelivery.
+func (mr *MockDatabaseMockRecorder) RetryDelivery(ctx, webhookId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDelivery", reflect.TypeOf((*MockDatabase)(nil).RetryDelivery), ctx, webhookId, id)
+}
+
+// Search mocks base method.
+func (m *MockDatabase) Search(ctx context.Context, options *models.BannerSearchOptions) ([]models.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, options)
+	ret0, _ := ret[0].([]models.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockDatabaseMockRecorder) Search(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockDatabase)(nil).Search), ctx, options)
+}
+
+// SelectBannerVersion mocks base method.
+func (m *MockDatabase) SelectBannerVersion(ctx context.Context, id, version int, ifMatch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectBannerVersion", ctx, id, version, ifMatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SelectBannerVersion indicates an expected call of SelectBannerVersion.
+func (mr *MockDatabaseMockRecorder) SelectBannerVersion(ctx, id, version, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectBannerVersion", reflect.TypeOf((*MockDatabase)(nil).SelectBannerVersion), ctx, id, version, ifMatch)
+}
+
+// Update mocks base method.
+func (m *MockDatabase) Update(ctx context.Context, id int, banner *models.UpdateBanner, ifMatch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, banner, ifMatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDatabaseMockRecorder) Update(ctx, id, banner, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDatabase)(nil).Update), ctx, id, banner, ifMatch)
+}
+
+// UpdateContent mocks base method.
+func (m *MockDatabase) UpdateContent(ctx context.Context, id int, ifMatch string, update func(*models.BaseBanner) (map[string]any, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContent", ctx, id, ifMatch, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContent indicates an expected call of UpdateContent.
+func (mr *MockDatabaseMockRecorder) UpdateContent(ctx, id, ifMatch, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContent", reflect.TypeOf((*MockDatabase)(nil).UpdateContent), ctx, id, ifMatch, update)
+}
+
+// UpdateRegistryEntry mocks base method.
+func (m *MockDatabase) UpdateRegistryEntry(ctx context.Context, registry models.Registry, id int, entry *models.RegistryEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRegistryEntry", ctx, registry, id, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRegistryEntry indicates an expected call of UpdateRegistryEntry.
+func (mr *MockDatabaseMockRecorder) UpdateRegistryEntry(ctx, registry, id, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRegistryEntry", reflect.TypeOf((*MockDatabase)(nil).UpdateRegistryEntry), ctx, registry, id, entry)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockDatabase) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockDatabaseMockRecorder) UpdateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockDatabase)(nil).UpdateWebhook), ctx, webhook)
+}
+
+// MockCache is a mock of Cache interface.
+type MockCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheMockRecorder
+}
+
+// MockCacheMockRecorder is the mock recorder for MockCache.
+type MockCacheMockRecorder struct {
+	mock *MockCache
+}
+
+// NewMockCache creates a new mock instance.
+func NewMockCache(ctrl *gomock.Controller) *MockCache {
+	mock := &MockCache{ctrl: ctrl}
+	mock.recorder = &MockCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCache) EXPECT() *MockCacheMockRecorder {
+	return m.recorder
+}
+
+// GetMany mocks base method.
+func (m *MockCache) GetMany(ctx context.Context, options []models.BannerIdentOptions, locale string) ([]*models.Candidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", ctx, options, locale)
+	ret0, _ := ret[0].([]*models.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockCacheMockRecorder) GetMany(ctx, options, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockCache)(nil).GetMany), ctx, options, locale)
+}
+
+// Invalidate mocks base method.
+func (m *MockCache) Invalidate(ctx context.Context, options []models.BannerIdentOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate", ctx, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockCacheMockRecorder) Invalidate(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockCache)(nil).Invalidate), ctx, options)
+}
+
+// Put mocks base method.
+func (m *MockCache) Put(ctx context.Context, options *models.BannerIdentOptions, locale string, banner *models.Candidate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, options, locale, banner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockCacheMockRecorder) Put(ctx, options, locale, banner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockCache)(nil).Put), ctx, options, locale, banner)
+}
@@ -0,0 +1,71 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/patch"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+const patchField = "patch"
+
+// PatchBannerContent applies merge patch or JSON Patch to the stored content. The banner is locked
+// until the result is validated and saved, so concurrent patches don't lose each other's changes
+func (s *Service) PatchBannerContent(ctx context.Context, id int, contentPatch *models.ContentPatch, ifMatch string) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.PatchBannerContent"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if s.approvalRequired {
+		return s.patchDraft(newCtx, id, contentPatch, ifMatch, log)
+	}
+	err := s.db.UpdateContent(newCtx, id, ifMatch, func(banner *models.BaseBanner) (map[string]any, error) {
+		content, err := applyContentPatch(map[string]any(banner.Content), contentPatch)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.validateContent(newCtx, banner.FeatureId, content, log); err != nil {
+			return nil, err
+		}
+		return content, nil
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorBadRequest) || errors.Is(err, e.ErrorPreconditionFailed) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+func applyContentPatch(content map[string]any, contentPatch *models.ContentPatch) (map[string]any, error) {
+	var result any
+	if contentPatch.Operations != nil {
+		var err error
+		result, err = patch.Apply(content, contentPatch.Operations)
+		var patchErr *patch.Error
+		if errors.As(err, &patchErr) {
+			return nil, e.NewValidationError(e.FieldViolation{
+				Field:   fmt.Sprintf("%s[%d]", patchField, patchErr.Index),
+				Message: fmt.Sprintf("%s %s: %s", patchErr.Operation.Op, patchErr.Operation.Path, patchErr.Reason),
+			})
+		}
+	} else {
+		result = patch.Merge(content, contentPatch.Merge)
+	}
+	obj, ok := result.(map[string]any)
+	if !ok {
+		return nil, e.NewValidationError(e.FieldViolation{Field: contentField, Message: "must be an object"})
+	}
+	return obj, nil
+}
@@ -0,0 +1,107 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/patch"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectContent makes the database update content of the banner 1 having the etag
+func expectContent(db *mocks.MockDatabase, banner *models.BaseBanner, etag string) {
+	db.EXPECT().UpdateContent(gomock.Any(), 1, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int, ifMatch string, update func(banner *models.BaseBanner) (map[string]any, error)) error {
+			if ifMatch != "" && !models.MatchETag(ifMatch, etag, false) {
+				return e.ErrorPreconditionFailed
+			}
+			content, err := update(banner)
+			if err != nil {
+				return err
+			}
+			banner.Content = content
+			return nil
+		})
+}
+
+func TestPatchBannerContent(t *testing.T) {
+	original := map[string]any{"title": "Sale", "text": "old", "url": "https://example.com"}
+	tests := []struct {
+		name       string
+		patch      models.ContentPatch
+		ifMatch    string
+		want       map[string]any
+		wantErr    error
+		wantFields []string
+	}{
+		{
+			name:  "merge patch",
+			patch: models.ContentPatch{Merge: map[string]any{"title": "Black Friday", "text": nil}},
+			want:  map[string]any{"title": "Black Friday", "url": "https://example.com"},
+		},
+		{
+			name: "json patch",
+			patch: models.ContentPatch{Operations: []patch.Operation{
+				{Op: patch.OpTest, Path: "/title", Value: "Sale"},
+				{Op: patch.OpReplace, Path: "/title", Value: "Black Friday"},
+			}},
+			want: map[string]any{"title": "Black Friday", "text": "old", "url": "https://example.com"},
+		},
+		{
+			name: "failed operation",
+			patch: models.ContentPatch{Operations: []patch.Operation{
+				{Op: patch.OpRemove, Path: "/text"},
+				{Op: patch.OpRemove, Path: "/text"},
+			}},
+			wantFields: []string{"patch[1]"},
+		},
+		{
+			name:    "matching etag",
+			patch:   models.ContentPatch{Merge: map[string]any{"text": nil}},
+			ifMatch: models.ETag(3, true),
+			want:    map[string]any{"title": "Sale", "url": "https://example.com"},
+		},
+		{
+			name:    "stale etag",
+			patch:   models.ContentPatch{Merge: map[string]any{"text": nil}},
+			ifMatch: models.ETag(2, true),
+			wantErr: e.ErrorPreconditionFailed,
+		},
+		{
+			name:       "content replaced by scalar",
+			patch:      models.ContentPatch{Merge: "text"},
+			wantFields: []string{"content"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			banner := models.BaseBanner{FeatureId: 1, UserBanner: models.UserBanner{Content: original}}
+			db := newMockDatabase(t)
+			expectContent(db, &banner, models.ETag(3, true))
+			expectNoSchemas(db)
+			err := newTestService(db).PatchBannerContent(context.Background(), 1, &test.patch, test.ifMatch)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				assert.Equal(t, original, banner.Content)
+				return
+			}
+			if test.wantFields == nil {
+				require.NoError(t, err)
+				assert.Equal(t, test.want, banner.Content)
+				return
+			}
+			var validationErr *e.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Len(t, validationErr.Violations, len(test.wantFields))
+			for i, field := range test.wantFields {
+				assert.Equal(t, field, validationErr.Violations[i].Field)
+			}
+			assert.Equal(t, original, banner.Content)
+		})
+	}
+}
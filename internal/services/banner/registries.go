@@ -0,0 +1,208 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+func (s *Service) ListRegistry(ctx context.Context, registry models.Registry) ([]models.RegistryEntry, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ListRegistry"
+	log := s.logger.With(utils.Text(op), slog.String("registry", string(registry)))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	entries, err := s.db.ListRegistry(newCtx, registry)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return entries, nil
+}
+
+func (s *Service) GetRegistryEntry(ctx context.Context, registry models.Registry, id int) (*models.RegistryEntry, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetRegistryEntry"
+	log := s.logger.With(utils.Text(op), slog.String("registry", string(registry)))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	entry, err := s.db.GetRegistryEntry(newCtx, registry, id)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	return entry, nil
+}
+
+func (s *Service) CreateRegistryEntry(ctx context.Context, registry models.Registry, entry *models.RegistryEntry) (int, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.CreateRegistryEntry"
+	log := s.logger.With(utils.Text(op), slog.String("registry", string(registry)))
+	if s.ctxDone(ctx, log) {
+		return 0, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	id, err := s.db.AddRegistryEntry(newCtx, registry, entry)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNameConflict) {
+			return 0, err
+		}
+		return 0, e.ErrorInternal
+	}
+	return id, nil
+}
+
+func (s *Service) UpdateRegistryEntry(ctx context.Context, registry models.Registry, id int, entry *models.RegistryEntry) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.UpdateRegistryEntry"
+	log := s.logger.With(utils.Text(op), slog.String("registry", string(registry)))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	err := s.db.UpdateRegistryEntry(newCtx, registry, id, entry)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorNameConflict) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// DeleteRegistryEntry removes feature and schedules deletion of its banners. Tag is removed only when no banner
+// has it, banners keep the rest of their tags and are edited by their owners
+func (s *Service) DeleteRegistryEntry(ctx context.Context, registry models.Registry, id int) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.DeleteRegistryEntry"
+	log := s.logger.With(utils.Text(op), slog.String("registry", string(registry)))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	filter := models.BulkDeleteFilter{FeatureId: id}
+	var ids []int
+	err := s.db.InTransaction(newCtx, func(ctx context.Context) error {
+		var err error
+		switch registry {
+		case models.Features:
+			// banners found now are deleted, the feature can't be given to others once it is removed
+			if ids, err = s.db.PreviewDelete(ctx, &filter); err != nil {
+				return err
+			}
+		case models.Tags:
+			var used int
+			used, err = s.db.Count(ctx, &models.BannerListOptions{
+				BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: id},
+				Limit:              models.ZeroValue,
+				Offset:             models.ZeroValue,
+			})
+			if err != nil {
+				return err
+			}
+			if used > 0 {
+				return fmt.Errorf("%w: %d banners have tag %d", e.ErrorTagInUse, used, id)
+			}
+		}
+		return s.db.DeleteRegistryEntry(ctx, registry, id)
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorTagInUse) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	if registry == models.Features {
+		// banners of the removed feature are deleted without confirmation, nothing can match them anymore
+		s.scheduleDeletion(ctx, filter, ids)
+	}
+	return nil
+}
+
+// validateRegistered checks that feature and tags of the banner are registered
+func (s *Service) validateRegistered(ctx context.Context, banner *models.UpdateBanner, log *slog.Logger) error {
+	const op = "banner.validateRegistered"
+	var violations []e.FieldViolation
+	if banner.Flags&models.FeatureBit > 0 {
+		missing, err := s.db.MissingRegistryIds(ctx, models.Features, banner.FeatureId)
+		if err != nil {
+			log.Warn(op, utils.Err(err))
+			return e.ErrorInternal
+		}
+		if len(missing) > 0 {
+			violations = append(violations, e.FieldViolation{Field: "feature_id", Message: "feature is not registered"})
+		}
+	}
+	if banner.Flags&models.TagBit > 0 {
+		missing, err := s.db.MissingRegistryIds(ctx, models.Tags, banner.TagIds...)
+		if err != nil {
+			log.Warn(op, utils.Err(err))
+			return e.ErrorInternal
+		}
+		unknown := make(map[int]bool, len(missing))
+		for _, id := range missing {
+			unknown[id] = true
+		}
+		for i, id := range banner.TagIds {
+			if unknown[id] {
+				violations = append(violations, e.FieldViolation{Field: fmt.Sprintf("tag_ids[%d]", i), Message: "tag is not registered"})
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
+
+// resolveNames replaces feature and tag names in options with their ids, false is returned if nothing can match
+func (s *Service) resolveNames(ctx context.Context, options *models.BannerListOptions, log *slog.Logger) (bool, error) {
+	resolve := func(registry models.Registry, name string, id *int) (bool, error) {
+		if name == "" {
+			return true, nil
+		}
+		entry, err := s.db.GetRegistryEntryByName(ctx, registry, name)
+		if errors.Is(err, e.ErrorNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			log.Warn(err.Error())
+			return false, e.ErrorInternal
+		}
+		if *id > models.ZeroValue && *id != entry.Id {
+			return false, nil
+		}
+		*id = entry.Id
+		return true, nil
+	}
+	ok, err := resolve(models.Features, options.FeatureName, &options.FeatureId)
+	if !ok || err != nil {
+		return ok, err
+	}
+	return resolve(models.Tags, options.TagName, &options.TagId)
+}
@@ -0,0 +1,208 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newConfiguredService returns the service with the config, the timeout is a second unless set
+func newConfiguredService(db Database, cache Cache, cfg config.ServiceConfig) *Service {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second
+	}
+	return New(db, cache, slog.New(slog.NewTextHandler(io.Discard, nil)), &cfg)
+}
+
+func newTestService(db Database) *Service {
+	return newConfiguredService(db, nil, config.ServiceConfig{})
+}
+
+// registered are the features and tags known to the database of expectRegistered
+var registered = map[models.Registry][]models.RegistryEntry{
+	models.Features: {{Id: 1, Name: "main_page"}},
+	models.Tags:     {{Id: 10, Name: "new_users"}, {Id: 11, Name: "premium"}},
+}
+
+// newMockDatabase returns the mock checked when the test ends
+func newMockDatabase(t *testing.T) *mocks.MockDatabase {
+	return mocks.NewMockDatabase(gomock.NewController(t))
+}
+
+// expectRegistered makes the database know the registered features and tags
+func expectRegistered(db *mocks.MockDatabase) {
+	db.EXPECT().MissingRegistryIds(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, registry models.Registry, ids ...int) ([]int, error) {
+			var missing []int
+			for _, id := range ids {
+				if !slices.ContainsFunc(registered[registry], func(entry models.RegistryEntry) bool { return entry.Id == id }) {
+					missing = append(missing, id)
+				}
+			}
+			return missing, nil
+		}).AnyTimes()
+	db.EXPECT().GetRegistryEntryByName(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, registry models.Registry, name string) (*models.RegistryEntry, error) {
+			i := slices.IndexFunc(registered[registry], func(entry models.RegistryEntry) bool { return entry.Name == name })
+			if i < 0 {
+				return nil, e.ErrorNotFound
+			}
+			return &registered[registry][i], nil
+		}).AnyTimes()
+}
+
+// expectNoSchemas makes the database find no content schemas of features
+func expectNoSchemas(db *mocks.MockDatabase) {
+	db.EXPECT().GetFeatureSchema(gomock.Any(), gomock.Any()).Return(nil, e.ErrorNotFound).AnyTimes()
+}
+
+func TestCreateBannerRequiresRegistered(t *testing.T) {
+	tests := []struct {
+		name       string
+		featureId  int
+		tagIds     []int
+		wantFields []string
+	}{
+		{name: "registered", featureId: 1, tagIds: []int{10, 11}},
+		{name: "unknown feature", featureId: 2, tagIds: []int{10}, wantFields: []string{"feature_id"}},
+		{name: "unknown tags", featureId: 1, tagIds: []int{12, 10, 13}, wantFields: []string{"tag_ids[0]", "tag_ids[2]"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newMockDatabase(t)
+			expectRegistered(db)
+			expectNoSchemas(db)
+			if test.wantFields == nil {
+				db.EXPECT().Add(gomock.Any(), gomock.Any()).Return(1, nil)
+			}
+			banner := &models.Banner{BaseBanner: models.BaseBanner{FeatureId: test.featureId, TagIds: test.tagIds}}
+			_, err := newTestService(db).CreateBanner(context.Background(), banner)
+			if test.wantFields == nil {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *e.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			assert.Equal(t, test.wantFields, fields)
+		})
+	}
+}
+
+func TestListBannersByName(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     models.BannerListOptions
+		wantListed  bool
+		wantFeature int
+		wantTag     int
+	}{
+		{
+			name:        "names",
+			options:     models.BannerListOptions{BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue}, FeatureName: "main_page", TagName: "premium"},
+			wantListed:  true,
+			wantFeature: 1,
+			wantTag:     11,
+		},
+		{
+			name:    "unknown name",
+			options: models.BannerListOptions{BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue}, TagName: "old_users"},
+		},
+		{
+			name:    "name and other id",
+			options: models.BannerListOptions{BannerIdentOptions: models.BannerIdentOptions{FeatureId: 2, TagId: models.ZeroValue}, FeatureName: "main_page"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var listed []models.BannerListOptions
+			db := newMockDatabase(t)
+			expectRegistered(db)
+			if test.wantListed {
+				db.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
+					listed = append(listed, *options)
+					return []models.BannerExt{{BannerId: 1}}, nil
+				})
+			}
+			page, err := newTestService(db).ListBanners(context.Background(), &test.options)
+			require.NoError(t, err)
+			if !test.wantListed {
+				assert.Empty(t, page.Banners)
+				return
+			}
+			require.Len(t, listed, 1)
+			assert.Equal(t, test.wantFeature, listed[0].FeatureId)
+			assert.Equal(t, test.wantTag, listed[0].TagId)
+		})
+	}
+}
+
+func TestDeleteRegistryEntry(t *testing.T) {
+	tests := []struct {
+		name          string
+		registry      models.Registry
+		id            int
+		tagged        int
+		wantErr       error
+		wantScheduled []*models.DeleteJob
+	}{
+		{name: "feature", registry: models.Features, id: 1, wantScheduled: []*models.DeleteJob{{Filter: models.BulkDeleteFilter{FeatureId: 1}, Ids: []int{2, 5}}}},
+		{name: "unused tag", registry: models.Tags, id: 10},
+		{name: "used tag", registry: models.Tags, id: 11, tagged: 2, wantErr: e.ErrorTagInUse},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var counted []*models.BannerListOptions
+			var deleted []int
+			db := newMockDatabase(t)
+			db.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+			db.EXPECT().Count(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options *models.BannerListOptions) (int, error) {
+				counted = append(counted, options)
+				return test.tagged, nil
+			}).AnyTimes()
+			db.EXPECT().PreviewDelete(gomock.Any(), gomock.Any()).Return([]int{2, 5}, nil).AnyTimes()
+			db.EXPECT().DeleteRegistryEntry(gomock.Any(), test.registry, gomock.Any()).DoAndReturn(func(_ context.Context, _ models.Registry, id int) error {
+				deleted = append(deleted, id)
+				return nil
+			}).AnyTimes()
+			srv := newTestService(db)
+
+			err := srv.DeleteRegistryEntry(context.Background(), test.registry, test.id)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				assert.Empty(t, deleted)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []int{test.id}, deleted)
+			}
+			if test.registry == models.Tags {
+				require.Len(t, counted, 1, "banners having the tag are counted")
+				assert.Equal(t, test.id, counted[0].TagId)
+			} else {
+				assert.Empty(t, counted)
+			}
+			var scheduled []*models.DeleteJob
+			for len(srv.tasksChan) > 0 {
+				job := <-srv.tasksChan
+				scheduled = append(scheduled, &models.DeleteJob{Filter: job.Filter, Ids: job.Ids})
+			}
+			assert.Equal(t, test.wantScheduled, scheduled, "only banners of features are deleted")
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+const commentField = "comment"
+
+// reviewTransitions are the states each action moves the banner from and to. Edits move published, archived
+// and draft banners to the draft state, banners in review and approved ones are rejected before they are edited
+var reviewTransitions = map[models.ReviewAction]map[models.BannerState]models.BannerState{
+	models.ActionSubmit:  {models.StateDraft: models.StateInReview},
+	models.ActionApprove: {models.StateInReview: models.StateApproved},
+	models.ActionReject:  {models.StateInReview: models.StateDraft, models.StateApproved: models.StateDraft},
+	models.ActionPublish: {models.StateApproved: models.StatePublished},
+	models.ActionArchive: {models.StatePublished: models.StateArchived},
+}
+
+// ReviewBanner moves the banner through the approval workflow. Approval and rejection are made by a reviewer
+// who is not the author of the pending revision, published revision replaces the banner for users
+func (s *Service) ReviewBanner(ctx context.Context, id int, decision *models.ReviewDecision) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ReviewBanner"
+	log := s.logger.With(utils.Text(op), slog.String("action", string(decision.Action)))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	transitions, ok := reviewTransitions[decision.Action]
+	if !ok {
+		return e.NewValidationError(e.FieldViolation{Field: "action", Message: "must be one of submit, approve, reject, publish, archive"})
+	}
+	if decision.Action == models.ActionReject && decision.Comment == "" {
+		return e.NewValidationError(e.FieldViolation{Field: commentField, Message: "is required to reject changes"})
+	}
+	actor := identity.From(ctx)
+	byReviewer := decision.Action == models.ActionApprove || decision.Action == models.ActionReject
+	if byReviewer && !actor.HasRole(identity.RoleReviewer) {
+		return fmt.Errorf("%w: %s requires the %s role", e.ErrorNoPermission, decision.Action, identity.RoleReviewer)
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	err := s.db.ChangeRevision(newCtx, id, "", func(revision *models.PendingRevision) error {
+		next, ok := transitions[revision.State]
+		if !ok {
+			return fmt.Errorf("%w: can't %s %s banner", e.ErrorInvalidState, decision.Action, revision.State)
+		}
+		if byReviewer {
+			if revision.Author == actor.Actor {
+				return fmt.Errorf("%w: changes are reviewed by another user", e.ErrorNoPermission)
+			}
+			revision.Reviewer = actor.Actor
+			revision.Comment = decision.Comment
+		}
+		revision.State = next
+		return nil
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorBadRequest) || errors.Is(err, e.ErrorNoPermission) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// GetBannerRevision returns the pending revision of the banner, or its current state when nothing is pending
+func (s *Service) GetBannerRevision(ctx context.Context, id int) (*models.PendingRevision, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetBannerRevision"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	revision, err := s.db.GetRevision(newCtx, id)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	return revision, nil
+}
+
+// ListReviews lists pending revisions in the state of options, the ones waiting longest first
+func (s *Service) ListReviews(ctx context.Context, options *models.ReviewListOptions) ([]models.PendingRevision, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ListReviews"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	switch options.State {
+	case models.StateDraft, models.StateInReview, models.StateApproved:
+	default:
+		return nil, e.NewValidationError(e.FieldViolation{Field: "state", Message: "must be one of draft, in_review, approved"})
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	revisions, err := s.db.ListReviews(newCtx, options)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return revisions, nil
+}
+
+// createDraft adds the banner which users get once its first revision is published
+func (s *Service) createDraft(ctx context.Context, banner *models.Banner, log *slog.Logger) (int, error) {
+	id, err := s.db.AddDraft(ctx, banner)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorConflict) {
+			return 0, err
+		}
+		return 0, e.ErrorInternal
+	}
+	return id, nil
+}
+
+// updateDraft applies fields of the update to the pending revision instead of the banner
+func (s *Service) updateDraft(ctx context.Context, id int, banner *models.UpdateBanner, ifMatch string, log *slog.Logger) error {
+	if err := s.validateRegistered(ctx, banner, log); err != nil {
+		return err
+	}
+	return s.saveDraft(ctx, id, ifMatch, func(revision *models.PendingRevision) error {
+		if banner.Flags&models.FeatureBit > 0 {
+			revision.FeatureId = banner.FeatureId
+		}
+		if banner.Flags&models.TagBit > 0 {
+			revision.TagIds = banner.TagIds
+		}
+		if banner.Flags&models.ContentBit > 0 {
+			revision.Content = banner.Content
+		}
+		if banner.Flags&models.IsActiveBit > 0 {
+			revision.IsActive = banner.IsActive
+		}
+		if banner.Flags&(models.ContentBit|models.FeatureBit) == 0 {
+			return nil
+		}
+		return s.validateContent(ctx, revision.FeatureId, revision.Content, log)
+	}, log)
+}
+
+// patchDraft applies the patch to the content of the pending revision
+func (s *Service) patchDraft(ctx context.Context, id int, contentPatch *models.ContentPatch, ifMatch string, log *slog.Logger) error {
+	return s.saveDraft(ctx, id, ifMatch, func(revision *models.PendingRevision) error {
+		content, err := applyContentPatch(map[string]any(revision.Content), contentPatch)
+		if err != nil {
+			return err
+		}
+		if err = s.validateContent(ctx, revision.FeatureId, content, log); err != nil {
+			return err
+		}
+		revision.Content = content
+		return nil
+	}, log)
+}
+
+// selectDraftVersion makes the version of the history the pending revision
+func (s *Service) selectDraftVersion(ctx context.Context, id, version int, ifMatch string, log *slog.Logger) error {
+	history, err := s.db.GetHistoryVersion(ctx, id, version)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	if err = s.validateContent(ctx, history.FeatureId, history.Content, log); err != nil {
+		return err
+	}
+	return s.saveDraft(ctx, id, ifMatch, func(revision *models.PendingRevision) error {
+		revision.BaseBanner = history.BaseBanner
+		return nil
+	}, log)
+}
+
+// saveDraft passes the pending revision to edit and saves it as the draft of the current actor,
+// the current state of the banner is edited when nothing is pending
+func (s *Service) saveDraft(ctx context.Context, id int, ifMatch string, edit func(revision *models.PendingRevision) error, log *slog.Logger) error {
+	err := s.db.ChangeRevision(ctx, id, ifMatch, func(revision *models.PendingRevision) error {
+		if revision.State == models.StateInReview || revision.State == models.StateApproved {
+			return fmt.Errorf("%w: %s banner is rejected before it is edited", e.ErrorInvalidState, revision.State)
+		}
+		if err := edit(revision); err != nil {
+			return err
+		}
+		revision.State = models.StateDraft
+		revision.Author = identity.From(ctx).Actor
+		return nil
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorBadRequest) || errors.Is(err, e.ErrorPreconditionFailed) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newRevisionDatabase keeps the revision of the banner 1, it is left as it was by failed changes
+func newRevisionDatabase(t *testing.T, revision *models.PendingRevision) *mocks.MockDatabase {
+	db := newMockDatabase(t)
+	expectRegistered(db)
+	expectNoSchemas(db)
+	db.EXPECT().ChangeRevision(gomock.Any(), 1, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int, _ string, change func(revision *models.PendingRevision) error) error {
+			changed := *revision
+			if err := change(&changed); err != nil {
+				return err
+			}
+			*revision = changed
+			return nil
+		}).AnyTimes()
+	return db
+}
+
+func newReviewService(db Database) *Service {
+	return newConfiguredService(db, nil, config.ServiceConfig{Approval: config.ApprovalConfig{Required: true}})
+}
+
+func as(actor string, roles ...string) context.Context {
+	return identity.With(context.Background(), &identity.Identity{Actor: actor, Roles: roles})
+}
+
+func TestReviewBanner(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     models.BannerState
+		decision  models.ReviewDecision
+		ctx       context.Context
+		wantState models.BannerState
+		wantErr   error
+	}{
+		{
+			name:      "submit draft",
+			state:     models.StateDraft,
+			decision:  models.ReviewDecision{Action: models.ActionSubmit},
+			ctx:       as("author"),
+			wantState: models.StateInReview,
+		},
+		{
+			name:      "approve by reviewer",
+			state:     models.StateInReview,
+			decision:  models.ReviewDecision{Action: models.ActionApprove},
+			ctx:       as("reviewer", identity.RoleReviewer),
+			wantState: models.StateApproved,
+		},
+		{
+			name:     "approve without role",
+			state:    models.StateInReview,
+			decision: models.ReviewDecision{Action: models.ActionApprove},
+			ctx:      as("reviewer"),
+			wantErr:  e.ErrorNoPermission,
+		},
+		{
+			name:     "approve own changes",
+			state:    models.StateInReview,
+			decision: models.ReviewDecision{Action: models.ActionApprove},
+			ctx:      as("author", identity.RoleReviewer),
+			wantErr:  e.ErrorNoPermission,
+		},
+		{
+			name:      "reject approved",
+			state:     models.StateApproved,
+			decision:  models.ReviewDecision{Action: models.ActionReject, Comment: "wrong title"},
+			ctx:       as("reviewer", identity.RoleReviewer),
+			wantState: models.StateDraft,
+		},
+		{
+			name:     "publish not approved",
+			state:    models.StateInReview,
+			decision: models.ReviewDecision{Action: models.ActionPublish},
+			ctx:      as("author"),
+			wantErr:  e.ErrorInvalidState,
+		},
+		{
+			name:      "publish approved",
+			state:     models.StateApproved,
+			decision:  models.ReviewDecision{Action: models.ActionPublish},
+			ctx:       as("author"),
+			wantState: models.StatePublished,
+		},
+		{
+			name:      "archive published",
+			state:     models.StatePublished,
+			decision:  models.ReviewDecision{Action: models.ActionArchive},
+			ctx:       as("author"),
+			wantState: models.StateArchived,
+		},
+		{
+			name:     "submit archived",
+			state:    models.StateArchived,
+			decision: models.ReviewDecision{Action: models.ActionSubmit},
+			ctx:      as("author"),
+			wantErr:  e.ErrorInvalidState,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			revision := models.PendingRevision{BannerId: 1, State: test.state, Author: "author"}
+			err := newReviewService(newRevisionDatabase(t, &revision)).ReviewBanner(test.ctx, 1, &test.decision)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				assert.Equal(t, test.state, revision.State)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantState, revision.State)
+		})
+	}
+}
+
+func TestRejectBannerRequiresComment(t *testing.T) {
+	revision := models.PendingRevision{BannerId: 1, State: models.StateInReview, Author: "author"}
+	service := newReviewService(newRevisionDatabase(t, &revision))
+	ctx := as("reviewer", identity.RoleReviewer)
+
+	err := service.ReviewBanner(ctx, 1, &models.ReviewDecision{Action: models.ActionReject})
+	var validationErr *e.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, commentField, validationErr.Violations[0].Field)
+
+	require.NoError(t, service.ReviewBanner(ctx, 1, &models.ReviewDecision{Action: models.ActionReject, Comment: "wrong title"}))
+	assert.Equal(t, models.StateDraft, revision.State)
+	assert.Equal(t, "reviewer", revision.Reviewer)
+	assert.Equal(t, "wrong title", revision.Comment)
+}
+
+func TestUpdateBannerMakesDraft(t *testing.T) {
+	revision := models.PendingRevision{
+		BannerId: 1,
+		Banner:   models.Banner{BaseBanner: models.BaseBanner{FeatureId: 1, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}}, IsActive: true},
+		State:    models.StatePublished,
+		Author:   "author",
+	}
+	update := &models.UpdateBanner{
+		Banner: models.Banner{BaseBanner: models.BaseBanner{UserBanner: models.UserBanner{Content: map[string]any{"title": "Black Friday"}}}},
+		Flags:  models.ContentBit,
+	}
+
+	service := newReviewService(newRevisionDatabase(t, &revision))
+	require.NoError(t, service.UpdateBanner(as("editor"), 1, update, ""))
+	assert.Equal(t, models.StateDraft, revision.State)
+	assert.Equal(t, "editor", revision.Author)
+	assert.Equal(t, map[string]any{"title": "Black Friday"}, revision.Content)
+	assert.True(t, revision.IsActive, "fields missing in the update are kept")
+
+	revision.State = models.StateInReview
+	require.ErrorIs(t, service.UpdateBanner(as("editor"), 1, update, ""), e.ErrorInvalidState)
+}
+
+func TestListReviewsInvalidState(t *testing.T) {
+	_, err := newReviewService(newMockDatabase(t)).ListReviews(context.Background(), &models.ReviewListOptions{State: models.StatePublished})
+	var validationErr *e.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,184 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"BannerFlow/internal/validation"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+const contentField = "content"
+
+func (s *Service) GetFeatureSchema(ctx context.Context, featureId int) (*models.FeatureSchema, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetFeatureSchema"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	schema, err := s.db.GetFeatureSchema(newCtx, featureId)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	return schema, nil
+}
+
+func (s *Service) ListFeatureSchemas(ctx context.Context) ([]models.FeatureSchema, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ListFeatureSchemas"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	schemas, err := s.db.ListFeatureSchemas(newCtx)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return schemas, nil
+}
+
+// PutFeatureSchema saves the schema unless dryRun is set and reports existing banners of the feature which don't match it
+func (s *Service) PutFeatureSchema(ctx context.Context, schema *models.FeatureSchema, dryRun bool) (*models.SchemaReport, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.PutFeatureSchema"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	parsed, err := validation.ParseSchema(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", e.ErrorInvalidSchema, err)
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if !dryRun {
+		if err = s.db.PutFeatureSchema(newCtx, schema); err != nil {
+			log.Warn(err.Error())
+			return nil, e.ErrorInternal
+		}
+	}
+	banners, err := s.db.List(newCtx, &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: schema.FeatureId, TagId: models.ZeroValue},
+		Limit:              models.ZeroValue,
+		Offset:             models.ZeroValue,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	report := &models.SchemaReport{FeatureId: schema.FeatureId, DryRun: dryRun, Checked: len(banners)}
+	validator := validation.NewSchemaValidator(parsed)
+	for _, banner := range banners {
+		if violations := validator.Validate(contentField, parsed, toJSONValue(banner.Content)); len(violations) > 0 {
+			report.Invalid = append(report.Invalid, models.InvalidBanner{BannerId: banner.BannerId, Violations: violations})
+		}
+	}
+	return report, nil
+}
+
+func (s *Service) DeleteFeatureSchema(ctx context.Context, featureId int) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.DeleteFeatureSchema"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	err := s.db.DeleteFeatureSchema(newCtx, featureId)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// validateContent checks content against the schema of the feature. Features without schema accept any content
+func (s *Service) validateContent(ctx context.Context, featureId int, content map[string]any, log *slog.Logger) error {
+	const op = "banner.validateContent"
+	schema, err := s.db.GetFeatureSchema(ctx, featureId)
+	if errors.Is(err, e.ErrorNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Warn(op, utils.Err(err))
+		return e.ErrorInternal
+	}
+	parsed, err := validation.ParseSchema(schema.Schema)
+	if err != nil {
+		log.Warn(op, utils.Err(err))
+		return e.ErrorInternal
+	}
+	violations := validation.NewSchemaValidator(parsed).Validate(contentField, parsed, toJSONValue(content))
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
+
+// validateUpdate validates resulting content when content or feature of the banner is changed
+func (s *Service) validateUpdate(ctx context.Context, id int, banner *models.UpdateBanner, log *slog.Logger) error {
+	if banner.Flags&(models.ContentBit|models.FeatureBit) == 0 {
+		return nil
+	}
+	featureId, content := banner.FeatureId, banner.Content
+	if banner.Flags&models.ContentBit == 0 || banner.Flags&models.FeatureBit == 0 {
+		current, err := s.db.GetById(ctx, id)
+		if err != nil {
+			log.Warn(err.Error())
+			if errors.Is(err, e.ErrorNotFound) {
+				return err
+			}
+			return e.ErrorInternal
+		}
+		if banner.Flags&models.ContentBit == 0 {
+			content = current.Content
+		}
+		if banner.Flags&models.FeatureBit == 0 {
+			featureId = current.FeatureId
+		}
+	}
+	return s.validateContent(ctx, featureId, content, log)
+}
+
+// validateVersion validates content of the version which is going to be activated
+func (s *Service) validateVersion(ctx context.Context, id, version int, log *slog.Logger) error {
+	history, err := s.db.GetHistoryVersion(ctx, id, version)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return s.validateContent(ctx, history.FeatureId, history.Content, log)
+}
+
+// toJSONValue converts map to the form produced by encoding/json, so it can be validated
+func toJSONValue(content map[string]any) any {
+	if content == nil {
+		return map[string]any{}
+	}
+	return map[string]any(content)
+}
@@ -0,0 +1,153 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// titleSchema requires the string title of banners
+var titleSchema = map[string]any{
+	"type":       "object",
+	"required":   []any{"title"},
+	"properties": map[string]any{"title": map[string]any{"type": "string"}},
+}
+
+// expectTitleSchema makes the database have titleSchema for the feature 1
+func expectTitleSchema(db *mocks.MockDatabase) {
+	db.EXPECT().GetFeatureSchema(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, featureId int) (*models.FeatureSchema, error) {
+		if featureId != 1 {
+			return nil, e.ErrorNotFound
+		}
+		return &models.FeatureSchema{FeatureId: 1, Schema: titleSchema}, nil
+	}).AnyTimes()
+}
+
+func requireFields(t *testing.T, err error, fields ...string) {
+	t.Helper()
+	var validationErr *e.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	var got []string
+	for _, violation := range validationErr.Violations {
+		got = append(got, violation.Field)
+	}
+	assert.Equal(t, fields, got)
+}
+
+func TestCreateBannerValidatesSchema(t *testing.T) {
+	db := newMockDatabase(t)
+	expectRegistered(db)
+	expectTitleSchema(db)
+	// only the banner matching the schema is added
+	db.EXPECT().Add(gomock.Any(), gomock.Any()).Return(1, nil)
+	srv := newTestService(db)
+	banner := func(content map[string]any) *models.Banner {
+		return &models.Banner{BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{10}, UserBanner: models.UserBanner{Content: content}}}
+	}
+
+	_, err := srv.CreateBanner(context.Background(), banner(map[string]any{"title": 5}))
+	requireFields(t, err, "content.title")
+
+	_, err = srv.CreateBanner(context.Background(), banner(map[string]any{"title": "Sale"}))
+	require.NoError(t, err)
+}
+
+func TestUpdateBannerValidatesSchema(t *testing.T) {
+	db := newMockDatabase(t)
+	expectRegistered(db)
+	expectTitleSchema(db)
+	db.EXPECT().GetById(gomock.Any(), 1).Return(&models.BannerExt{BannerId: 1, Banner: models.Banner{BaseBanner: models.BaseBanner{
+		FeatureId:  1,
+		TagIds:     []int{10},
+		UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}},
+	}}}, nil).AnyTimes()
+	// only the update matching the schema is saved
+	db.EXPECT().Update(gomock.Any(), 1, gomock.Any(), "").Return(nil)
+	srv := newTestService(db)
+
+	err := srv.UpdateBanner(context.Background(), 1, &models.UpdateBanner{
+		Banner: models.Banner{BaseBanner: models.BaseBanner{UserBanner: models.UserBanner{Content: map[string]any{"text": "old"}}}},
+		Flags:  models.ContentBit,
+	}, "")
+	requireFields(t, err, "content.title")
+
+	err = srv.UpdateBanner(context.Background(), 1, &models.UpdateBanner{
+		Banner: models.Banner{BaseBanner: models.BaseBanner{FeatureId: 1}},
+		Flags:  models.FeatureBit,
+	}, "")
+	require.NoError(t, err, "current content is checked against the schema of the new feature")
+}
+
+func TestPatchBannerContentValidatesSchema(t *testing.T) {
+	banner := models.BaseBanner{FeatureId: 1, UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}}}
+	db := newMockDatabase(t)
+	expectContent(db, &banner, models.ETag(1, true))
+	expectTitleSchema(db)
+	srv := newTestService(db)
+
+	err := srv.PatchBannerContent(context.Background(), 1, &models.ContentPatch{Merge: map[string]any{"title": nil}}, "")
+	requireFields(t, err, "content.title")
+	assert.Equal(t, map[string]any{"title": "Sale"}, banner.Content)
+}
+
+func TestSelectBannerVersionValidatesSchema(t *testing.T) {
+	db := newMockDatabase(t)
+	expectTitleSchema(db)
+	db.EXPECT().GetHistoryVersion(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, _, version int) (*models.HistoryBanner, error) {
+		content := map[string]any{"title": "Sale"}
+		if version == 1 {
+			content = map[string]any{"title": []any{"Sale"}}
+		}
+		return &models.HistoryBanner{BaseBanner: models.BaseBanner{FeatureId: 1, UserBanner: models.UserBanner{Content: content}}, Version: version}, nil
+	}).AnyTimes()
+	// only the matching version is selected
+	db.EXPECT().SelectBannerVersion(gomock.Any(), 1, 2, "").Return(nil)
+	srv := newTestService(db)
+
+	requireFields(t, srv.SelectBannerVersion(context.Background(), 1, 1, ""), "content.title")
+	require.NoError(t, srv.SelectBannerVersion(context.Background(), 1, 2, ""))
+}
+
+func TestPutFeatureSchemaDryRun(t *testing.T) {
+	banner := func(id int, content map[string]any) models.BannerExt {
+		return models.BannerExt{BannerId: id, Banner: models.Banner{BaseBanner: models.BaseBanner{FeatureId: 1, UserBanner: models.UserBanner{Content: content}}}}
+	}
+	db := newMockDatabase(t)
+	// banners of the feature are checked, the schema is not saved
+	db.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
+		assert.Equal(t, 1, options.FeatureId)
+		return []models.BannerExt{
+			banner(1, map[string]any{"title": "Sale"}),
+			banner(2, map[string]any{"title": 5}),
+			banner(3, map[string]any{"text": "old"}),
+		}, nil
+	})
+
+	report, err := newTestService(db).PutFeatureSchema(context.Background(), &models.FeatureSchema{FeatureId: 1, Schema: titleSchema}, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Checked)
+	var invalid []int
+	for _, banner := range report.Invalid {
+		invalid = append(invalid, banner.BannerId)
+		assert.NotEmpty(t, banner.Violations)
+	}
+	assert.Equal(t, []int{2, 3}, invalid)
+}
+
+func TestPutFeatureSchemaRejectsUnsupportedKeywords(t *testing.T) {
+	// the schema is neither saved nor checked against banners
+	db := newMockDatabase(t)
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"kind": map[string]any{"oneOf": []any{map[string]any{"const": "promo"}}}},
+	}
+	_, err := newTestService(db).PutFeatureSchema(context.Background(), &models.FeatureSchema{FeatureId: 1, Schema: schema}, false)
+	assert.ErrorIs(t, err, e.ErrorInvalidSchema)
+}
@@ -0,0 +1,68 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"strings"
+	"sync/atomic"
+)
+
+// SearchBanners returns the page of banners found by words of the content and predicates on it. Results are
+// ordered by rank when words are searched and by id otherwise, pages follow each other like in the listing
+func (s *Service) SearchBanners(ctx context.Context, options *models.BannerSearchOptions) (*models.SearchPage, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.SearchBanners"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	query := *options
+	query.Query = strings.TrimSpace(options.Query)
+	query.Sort = models.SortById
+	if query.Query != "" {
+		query.Sort = models.SortByRank
+	}
+	if err := validateSearchOptions(&query); err != nil {
+		return nil, err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if options.Limit > 0 {
+		query.Limit = options.Limit + 1
+	}
+	hits, err := s.db.Search(newCtx, &query)
+	if err != nil {
+		log.Warn("failed to search banners", utils.Err(err))
+		return nil, e.ErrorInternal
+	}
+	page := &models.SearchPage{Hits: hits, Total: models.ZeroValue}
+	if options.Limit > 0 && len(hits) > options.Limit {
+		page.Hits = hits[:options.Limit]
+		page.Next = models.CursorAfterHit(&page.Hits[options.Limit-1], query.Sort)
+	}
+	if options.WithTotal {
+		if page.Total, err = s.db.CountSearch(newCtx, &query); err != nil {
+			log.Warn("failed to count found banners", utils.Err(err))
+			return nil, e.ErrorInternal
+		}
+	}
+	return page, nil
+}
+
+// validateSearchOptions requires something to search and the cursor of the same search
+func validateSearchOptions(options *models.BannerSearchOptions) error {
+	var violations []e.FieldViolation
+	if options.Query == "" && len(options.Predicates) == 0 {
+		violations = append(violations, e.FieldViolation{Field: "q", Message: "or where is required"})
+	}
+	if options.After != nil && options.After.Sort != options.Sort {
+		violations = append(violations, e.FieldViolation{Field: "cursor", Message: "was issued for another search"})
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
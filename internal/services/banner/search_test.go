@@ -0,0 +1,142 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectSearch makes the database find banners with ids from 1 recording the options, ranks go down with ids
+func expectSearch(db *mocks.MockDatabase, banners int, searched *[]models.BannerSearchOptions) {
+	db.EXPECT().Search(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options *models.BannerSearchOptions) ([]models.SearchHit, error) {
+		*searched = append(*searched, *options)
+		id := 1
+		if options.After != nil {
+			id = options.After.Id + 1
+		}
+		var hits []models.SearchHit
+		for ; id <= banners && (options.Limit <= 0 || len(hits) < options.Limit); id++ {
+			hits = append(hits, models.SearchHit{BannerExt: models.BannerExt{BannerId: id}, Rank: 1 / float32(id)})
+		}
+		return hits, nil
+	}).AnyTimes()
+	db.EXPECT().CountSearch(gomock.Any(), gomock.Any()).Return(banners, nil).AnyTimes()
+}
+
+func searchOptions(query string, limit int) *models.BannerSearchOptions {
+	return &models.BannerSearchOptions{
+		BannerListOptions: models.BannerListOptions{
+			BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue},
+			Limit:              limit,
+			Offset:             models.ZeroValue,
+		},
+		Query: query,
+	}
+}
+
+func TestSearchBannersPages(t *testing.T) {
+	var searched []models.BannerSearchOptions
+	db := newMockDatabase(t)
+	expectSearch(db, 3, &searched)
+	srv := newTestService(db)
+	options := searchOptions(" black friday ", 2)
+	var pages [][]int
+	for {
+		page, err := srv.SearchBanners(context.Background(), options)
+		require.NoError(t, err)
+		assert.Equal(t, models.ZeroValue, page.Total)
+		var ids []int
+		for _, hit := range page.Hits {
+			ids = append(ids, hit.BannerId)
+		}
+		pages = append(pages, ids)
+		if page.Next == nil {
+			break
+		}
+		assert.Equal(t, models.SortByRank, page.Next.Sort)
+		assert.Equal(t, page.Hits[len(page.Hits)-1].Rank, page.Next.Rank)
+		options.After = page.Next
+	}
+	assert.Equal(t, [][]int{{1, 2}, {3}}, pages)
+	assert.Equal(t, "black friday", searched[0].Query)
+	assert.Equal(t, 3, searched[0].Limit, "one more banner is fetched to find the next page")
+
+	options = searchOptions("", models.ZeroValue)
+	options.Predicates = []models.ContentPredicate{{Path: []string{"title"}, Op: models.PredicateExists}}
+	options.WithTotal = true
+	page, err := srv.SearchBanners(context.Background(), options)
+	require.NoError(t, err)
+	assert.Len(t, page.Hits, 3)
+	assert.Nil(t, page.Next)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, models.SortById, searched[len(searched)-1].Sort)
+}
+
+func TestSearchBannersInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    *models.BannerSearchOptions
+		wantFields []string
+	}{
+		{
+			name:       "nothing to search",
+			options:    searchOptions("  ", 2),
+			wantFields: []string{"q"},
+		},
+		{
+			name: "cursor of the search without words",
+			options: func() *models.BannerSearchOptions {
+				options := searchOptions("sale", 2)
+				options.After = &models.ListCursor{Sort: models.SortById, Id: 2}
+				return options
+			}(),
+			wantFields: []string{"cursor"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// nothing is searched
+			_, err := newTestService(newMockDatabase(t)).SearchBanners(context.Background(), test.options)
+			var validationErr *e.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			assert.Equal(t, test.wantFields, fields)
+		})
+	}
+}
+
+func TestSearchBannersDatabaseErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchErr error
+		countErr  error
+	}{
+		{name: "search failed", searchErr: errors.New("canceling statement due to statement timeout")},
+		{name: "count failed", countErr: errors.New("connection reset")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newMockDatabase(t)
+			db.EXPECT().Search(gomock.Any(), gomock.Any()).Return([]models.SearchHit{{BannerExt: models.BannerExt{BannerId: 1}}}, test.searchErr)
+			if test.searchErr == nil {
+				db.EXPECT().CountSearch(gomock.Any(), gomock.Any()).Return(0, test.countErr)
+			}
+			options := searchOptions("sale", 2)
+			options.WithTotal = true
+
+			page, err := newTestService(db).SearchBanners(context.Background(), options)
+			assert.Nil(t, page)
+			assert.ErrorIs(t, err, e.ErrorInternal)
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// eventsPageSize is the number of banner events read at once
+	eventsPageSize = 1000
+	// eventGapTimeout is how long a missing event is awaited. Ids are taken before transactions commit,
+	// so an event may become visible after the following ones, ids of rolled back events never do
+	eventGapTimeout = 10 * time.Second
+	// listenRetryDelay is the pause before listening again after the connection is lost
+	listenRetryDelay = 5 * time.Second
+)
+
+// watcher is woken up by events that may change the banner it watches. Wake-ups of a busy watcher
+// are merged, so a slow reader gets the current banner once instead of every change
+type watcher struct {
+	tenant    string
+	featureId int
+	tagIds    []int
+	wake      chan struct{}
+	// eventId is the last event affecting the banner
+	eventId atomic.Int64
+}
+
+func newWatcher(tenant string, featureId int, tagIds []int) *watcher {
+	return &watcher{tenant: tenant, featureId: featureId, tagIds: tagIds, wake: make(chan struct{}, 1)}
+}
+
+func (w *watcher) notify(eventId int64) {
+	for current := w.eventId.Load(); eventId > current; current = w.eventId.Load() {
+		if w.eventId.CompareAndSwap(current, eventId) {
+			break
+		}
+	}
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// eventHub delivers banner events to watchers, it never blocks on them
+type eventHub struct {
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+	// last is the id of the last published event
+	last int64
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{watchers: make(map[*watcher]struct{})}
+}
+
+// subscribe adds the watcher and returns the id of the last event published before
+func (h *eventHub) subscribe(w *watcher) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.watchers[w] = struct{}{}
+	return h.last
+}
+
+func (h *eventHub) unsubscribe(w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.watchers, w)
+}
+
+// skip makes the hub start after the event, watchers are woken up by the following ones
+func (h *eventHub) skip(eventId int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = max(h.last, eventId)
+}
+
+// publish wakes up watchers affected by the events
+func (h *eventHub) publish(events []models.BannerEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range events {
+		ev := &events[i]
+		h.last = max(h.last, ev.Id)
+		for w := range h.watchers {
+			if ev.Affects(w.tenant, w.featureId, w.tagIds) {
+				w.notify(ev.Id)
+			}
+		}
+	}
+}
+
+// eventCursor remembers read events. It stays before a missing event until it shows up or eventGapTimeout passes,
+// events following it are read once
+type eventCursor struct {
+	after int64
+	// last is the id of the last read event
+	last int64
+	seen map[int64]bool
+	gaps map[int64]time.Time
+}
+
+func newEventCursor(after int64) *eventCursor {
+	return &eventCursor{after: after, last: after, seen: make(map[int64]bool), gaps: make(map[int64]time.Time)}
+}
+
+// fresh returns the events not read before and moves the cursor past the ones read without gaps
+func (c *eventCursor) fresh(events []models.BannerEvent, now time.Time) []models.BannerEvent {
+	var res []models.BannerEvent
+	for _, ev := range events {
+		if ev.Id <= c.after || c.seen[ev.Id] {
+			continue
+		}
+		c.seen[ev.Id] = true
+		delete(c.gaps, ev.Id)
+		res = append(res, ev)
+		c.last = max(c.last, ev.Id)
+	}
+	blocked := false
+	for id := c.after + 1; id <= c.last; id++ {
+		if c.seen[id] {
+			if !blocked {
+				delete(c.seen, id)
+				c.after = id
+			}
+			continue
+		}
+		noticed, ok := c.gaps[id]
+		if !ok {
+			noticed = now
+			c.gaps[id] = now
+		}
+		if !blocked && now.Sub(noticed) >= eventGapTimeout {
+			delete(c.gaps, id)
+			c.after = id
+			continue
+		}
+		blocked = true
+	}
+	return res
+}
+
+// WatchUserBanner sends the banner the user gets, see UserGetBanners, whenever an event may change it. The current
+// banner comes first unless the watch is resumed and nothing changed since LastEventId. Changes are merged while
+// the reader is busy and the ones leaving the banner as it was are skipped. The channel is closed once ctx is done,
+// the service is stopped or the banner can't be read
+func (s *Service) WatchUserBanner(ctx context.Context, options *models.BannerWatchOptions) (<-chan models.BannerChange, error) {
+	const op = "banner.WatchUserBanner"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	if err := normalizeAttributes(&options.Attributes); err != nil {
+		return nil, err
+	}
+	w := newWatcher(identity.From(ctx).Tenant, options.FeatureId, options.Attributes.TagIds)
+	last := s.events.subscribe(w)
+	changed := options.LastEventId == 0
+	if !changed {
+		var err error
+		if changed, err = s.bannerChangedSince(ctx, options); err != nil {
+			s.events.unsubscribe(w)
+			log.Warn("failed to check banner events", utils.Err(err))
+			return nil, e.ErrorInternal
+		}
+	}
+	if changed {
+		w.notify(max(last, options.LastEventId))
+	}
+
+	userOptions := models.BannerUserOptions{
+		BannerIdentOptions: options.BannerIdentOptions,
+		UseLastRevision:    true,
+		Attributes:         options.Attributes,
+	}
+	changes := make(chan models.BannerChange)
+	go func() {
+		defer close(changes)
+		defer s.events.unsubscribe(w)
+		sent, sentETag := false, ""
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			case <-w.wake:
+			}
+			change := models.BannerChange{EventId: w.eventId.Load()}
+			banner, err := s.UserGetBanners(ctx, &userOptions)
+			switch {
+			case errors.Is(err, e.ErrorNotFound):
+			case err != nil:
+				log.Warn("failed to get banner", utils.Err(err))
+				return
+			default:
+				change.Banner = banner
+			}
+			etag := ""
+			if change.Banner != nil {
+				etag = models.ContentETag(change.Banner.Content)
+			}
+			if sent && etag == sentETag {
+				continue
+			}
+			select {
+			case changes <- change:
+				sent, sentETag = true, etag
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+func (s *Service) bannerChangedSince(ctx context.Context, options *models.BannerWatchOptions) (bool, error) {
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.db.BannerChangedSince(newCtx, options.FeatureId, options.Attributes.TagIds, options.LastEventId)
+}
+
+// runBannerEvents reads banner events the database notifies about and wakes up their watchers until the service
+// is stopped. The connection is restored once lost, events raised meanwhile are read then
+func (s *Service) runBannerEvents() {
+	const op = "banner.runBannerEvents"
+	log := s.logger.With(utils.Text(op))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var cursor *eventCursor
+	for {
+		err := s.db.ListenBannerEvents(ctx, func(ctx context.Context) error {
+			if cursor == nil {
+				last, err := s.lastBannerEventId(ctx)
+				if err != nil {
+					return err
+				}
+				cursor = newEventCursor(last)
+				s.events.skip(last)
+			}
+			return s.readBannerEvents(ctx, cursor, log)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("failed to listen to banner events", utils.Err(err))
+		select {
+		case <-s.done:
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// invalidateCache removes cached banners of features and tags of the events of every tenant. Every instance reads
+// the events, so local caches are invalidated too. Failures are logged only, the banners expire with the cache ttl then
+func (s *Service) invalidateCache(ctx context.Context, events []models.BannerEvent, log *slog.Logger) {
+	const op = "banner.invalidateCache"
+	byTenant := make(map[string][]models.BannerIdentOptions)
+	for _, ev := range events {
+		for _, tagId := range ev.TagIds {
+			byTenant[ev.Tenant] = append(byTenant[ev.Tenant], models.BannerIdentOptions{FeatureId: ev.FeatureId, TagId: tagId})
+		}
+	}
+	for tenant, options := range byTenant {
+		newCtx, cancel := context.WithTimeout(identity.With(ctx, &identity.Identity{Tenant: tenant}), s.timeout)
+		err := s.cache.Invalidate(newCtx, options)
+		cancel()
+		if err != nil {
+			log.Warn("failed to invalidate cached banners", utils.Text(op), slog.String("tenant", tenant), utils.Err(err))
+		}
+	}
+}
+
+func (s *Service) lastBannerEventId(ctx context.Context) (int64, error) {
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.db.LastBannerEventId(newCtx)
+}
+
+// readBannerEvents publishes events following the cursor and drops cached banners of their features and tags
+func (s *Service) readBannerEvents(ctx context.Context, cursor *eventCursor, log *slog.Logger) error {
+	from := cursor.after
+	for {
+		newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		events, err := s.db.ListBannerEvents(newCtx, from, eventsPageSize)
+		cancel()
+		if err != nil {
+			return err
+		}
+		fresh := cursor.fresh(events, time.Now())
+		s.events.publish(fresh)
+		s.invalidateCache(ctx, fresh, log)
+		if len(events) < eventsPageSize {
+			return nil
+		}
+		from = events[len(events)-1].Id
+	}
+}
+
+// runEventPruning periodically removes banner events older than the retention until the service is stopped
+func (s *Service) runEventPruning() {
+	const op = "banner.runEventPruning"
+	if s.eventRetention <= 0 || s.eventInterval <= 0 {
+		return
+	}
+	log := s.logger.With(utils.Text(op))
+	ticker := time.NewTicker(s.eventInterval)
+	defer ticker.Stop()
+	for {
+		s.pruneBannerEvents(log)
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) pruneBannerEvents(log *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	removed, err := s.db.PruneBannerEvents(ctx, s.eventRetention)
+	if err != nil {
+		log.Warn("failed to prune banner events", utils.Err(err))
+		return
+	}
+	if removed > 0 {
+		log.Info("banner events pruned", slog.Int64("removed", removed))
+	}
+}
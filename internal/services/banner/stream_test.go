@@ -0,0 +1,165 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func receiveChange(t *testing.T, changes <-chan models.BannerChange) models.BannerChange {
+	t.Helper()
+	select {
+	case change, ok := <-changes:
+		require.True(t, ok, "changes are closed")
+		return change
+	case <-time.After(time.Second):
+		require.FailNow(t, "no change received")
+		return models.BannerChange{}
+	}
+}
+
+func TestWatchUserBanner(t *testing.T) {
+	db, data := newTargetingDatabase(t)
+	cache := &mapCache{entries: map[cacheKey]models.Candidate{}}
+	service := newConfiguredService(db, cache, config.ServiceConfig{})
+	service.events.skip(3)
+	event := func(id int64, featureId int) models.BannerEvent {
+		return models.BannerEvent{Id: id, BannerId: 1, FeatureId: featureId, TagIds: []int{10}}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := models.BannerUserOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: 1, TagId: 10},
+		Attributes:         models.UserAttributes{TagIds: []int{10}},
+	}
+	changes, err := service.WatchUserBanner(ctx, &models.BannerWatchOptions{BannerUserOptions: options})
+	require.NoError(t, err)
+
+	change := receiveChange(t, changes)
+	assert.Equal(t, models.BannerChange{EventId: 3, Banner: &models.UserBanner{Content: map[string]any{"id": 1}}}, change)
+
+	data.banners[0].Content = map[string]any{"id": 1, "title": "Sale"}
+	service.events.publish([]models.BannerEvent{event(4, 2), event(5, 1)})
+	change = receiveChange(t, changes)
+	assert.Equal(t, int64(5), change.EventId)
+	assert.Equal(t, map[string]any{"id": 1, "title": "Sale"}, change.Banner.Content)
+
+	// the event leaving the banner as it was is skipped
+	service.events.publish([]models.BannerEvent{event(6, 1)})
+	data.banners[0].IsActive = false
+	service.events.publish([]models.BannerEvent{event(7, 1)})
+	assert.Equal(t, models.BannerChange{EventId: 7}, receiveChange(t, changes))
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "changes are not closed")
+	}
+}
+
+func TestWatchUserBannerResumed(t *testing.T) {
+	for _, changed := range []bool{false, true} {
+		db, _ := newTargetingDatabase(t)
+		// the watched banner changed since the event or not
+		db.EXPECT().BannerChangedSince(gomock.Any(), 1, []int{10}, int64(8)).Return(changed, nil)
+		cache := &mapCache{entries: map[cacheKey]models.Candidate{}}
+		service := newConfiguredService(db, cache, config.ServiceConfig{})
+		ctx, cancel := context.WithCancel(context.Background())
+		changes, err := service.WatchUserBanner(ctx, &models.BannerWatchOptions{
+			BannerUserOptions: models.BannerUserOptions{
+				BannerIdentOptions: models.BannerIdentOptions{FeatureId: 1, TagId: 10},
+				Attributes:         models.UserAttributes{TagIds: []int{10}},
+			},
+			LastEventId: 8,
+		})
+		require.NoError(t, err)
+		if changed {
+			assert.Equal(t, int64(8), receiveChange(t, changes).EventId, "changed banner is sent at once")
+		}
+		service.events.publish([]models.BannerEvent{{Id: 9, FeatureId: 1, TagIds: []int{10}}})
+		if changed {
+			// the content is the same as the one sent
+			select {
+			case change := <-changes:
+				assert.Fail(t, "unchanged banner is sent", change)
+			case <-time.After(50 * time.Millisecond):
+			}
+		} else {
+			assert.Equal(t, int64(9), receiveChange(t, changes).EventId, "banner is sent after the event")
+		}
+		cancel()
+	}
+}
+
+func TestEventCursor(t *testing.T) {
+	events := func(ids ...int64) []models.BannerEvent {
+		res := make([]models.BannerEvent, len(ids))
+		for i, id := range ids {
+			res[i].Id = id
+		}
+		return res
+	}
+	ids := func(events []models.BannerEvent) []int64 {
+		res := make([]int64, 0, len(events))
+		for _, ev := range events {
+			res = append(res, ev.Id)
+		}
+		return res
+	}
+	now := time.Now()
+	cursor := newEventCursor(10)
+
+	assert.Equal(t, []int64{11, 13}, ids(cursor.fresh(events(11, 13), now)))
+	assert.Equal(t, int64(11), cursor.after, "cursor waits for the missing event")
+
+	assert.Equal(t, []int64{12, 15}, ids(cursor.fresh(events(12, 13, 15), now.Add(time.Second))))
+	assert.Equal(t, int64(13), cursor.after)
+
+	assert.Empty(t, cursor.fresh(events(15), now.Add(eventGapTimeout)))
+	assert.Equal(t, int64(13), cursor.after, "gap noticed later is awaited")
+
+	assert.Empty(t, cursor.fresh(events(15), now.Add(time.Second+eventGapTimeout)))
+	assert.Equal(t, int64(15), cursor.after, "rolled back event is skipped")
+	assert.Empty(t, cursor.seen)
+	assert.Empty(t, cursor.gaps)
+}
+
+func TestReadBannerEventsInvalidatesCache(t *testing.T) {
+	db := newMockDatabase(t)
+	db.EXPECT().ListBannerEvents(gomock.Any(), int64(10), eventsPageSize).Return([]models.BannerEvent{
+		{Id: 11, Tenant: "acme", FeatureId: 1, TagIds: []int{10, 11}},
+		{Id: 12, Tenant: "globex", FeatureId: 2, TagIds: []int{10}},
+		{Id: 13, Tenant: "acme", FeatureId: 3, TagIds: []int{12}},
+	}, nil)
+	cache := mocks.NewMockCache(gomock.NewController(t))
+	invalidated := map[string][]models.BannerIdentOptions{}
+	cache.EXPECT().Invalidate(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, options []models.BannerIdentOptions) error {
+		tenant := identity.From(ctx).Tenant
+		invalidated[tenant] = append(invalidated[tenant], options...)
+		if tenant == "globex" {
+			return errors.New("redis is down")
+		}
+		return nil
+	}).Times(2)
+	service := newConfiguredService(db, cache, config.ServiceConfig{})
+
+	err := service.readBannerEvents(context.Background(), newEventCursor(10), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err, "failed invalidation does not stop reading events")
+	assert.Equal(t, map[string][]models.BannerIdentOptions{
+		"acme":   {{FeatureId: 1, TagId: 10}, {FeatureId: 1, TagId: 11}, {FeatureId: 3, TagId: 12}},
+		"globex": {{FeatureId: 2, TagId: 10}},
+	}, invalidated)
+}
@@ -0,0 +1,249 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// candidateBanner is a banner found by the candidate lookup with the state deciding whether users get it
+type candidateBanner struct {
+	models.Candidate
+	FeatureId int
+	TagIds    []int
+	IsActive  bool
+	Live      bool
+}
+
+func (s *Service) GetTargeting(ctx context.Context, id int) (*models.Targeting, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetTargeting"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	targeting, err := s.db.GetTargeting(newCtx, id)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	return targeting, nil
+}
+
+// PutTargeting sets the targeting of the banner. It is not a change of the banner, so it is applied without review
+// and users get it once cached banners expire
+func (s *Service) PutTargeting(ctx context.Context, targeting *models.Targeting) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.PutTargeting"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	if err := validateRule(&targeting.Rule); err != nil {
+		return err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.db.PutTargeting(newCtx, targeting); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// DeleteTargeting removes the targeting, so the banner matches every user again
+func (s *Service) DeleteTargeting(ctx context.Context, id int) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.DeleteTargeting"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.db.DeleteTargeting(newCtx, id); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// EvaluateBanner explains which banner the user would get now. Every banner of the feature having any tag
+// of the user is a candidate, including inactive and unpublished ones, which are reported but never served
+func (s *Service) EvaluateBanner(ctx context.Context, options *models.EvaluationOptions) (*models.Evaluation, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.EvaluateBanner"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	if err := normalizeAttributes(&options.Attributes); err != nil {
+		return nil, err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	banners, err := s.listCandidates(newCtx, []int{options.FeatureId}, options.Attributes.TagIds, options.Attributes.Locale, log)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]models.Candidate, 0, len(banners))
+	states := make(map[int]*candidateBanner, len(banners))
+	for i := range banners {
+		candidates = append(candidates, banners[i].Candidate)
+		states[banners[i].BannerId] = &banners[i]
+	}
+	models.SortCandidates(candidates)
+	evaluation := &models.Evaluation{BannerId: models.ZeroValue, Candidates: make([]models.CandidateResult, 0, len(candidates))}
+	for _, candidate := range candidates {
+		state := states[candidate.BannerId]
+		result := models.CandidateResult{
+			BannerId: candidate.BannerId,
+			Priority: candidate.Priority(),
+			IsActive: state.IsActive,
+			Live:     state.Live,
+			Reasons:  candidate.Mismatches(&options.Attributes),
+		}
+		result.Matched = len(result.Reasons) == 0
+		if !state.IsActive {
+			result.Reasons = append(result.Reasons, "banner is inactive")
+		}
+		if !state.Live {
+			result.Reasons = append(result.Reasons, "banner is not published")
+		}
+		if len(result.Reasons) == 0 {
+			if evaluation.BannerId == models.ZeroValue {
+				result.Served = true
+				evaluation.BannerId = candidate.BannerId
+				evaluation.Content = candidate.Content
+				evaluation.Locale = candidate.Locale
+			} else {
+				result.Reasons = append(result.Reasons, fmt.Sprintf("banner %d is chosen before it", evaluation.BannerId))
+			}
+		}
+		evaluation.Candidates = append(evaluation.Candidates, result)
+	}
+	return evaluation, nil
+}
+
+// listCandidates returns banners of any of the features having any of the tags with their targeting and content
+// translated to the locale
+func (s *Service) listCandidates(newCtx context.Context, featureIds []int, tagIds []int, locale string, log *slog.Logger) ([]candidateBanner, error) {
+	const op = "banner.listCandidates"
+	page, err := s.ListBanners(newCtx, &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue},
+		FeatureIds:         featureIds,
+		TagIds:             tagIds,
+		Limit:              models.ZeroValue,
+		Offset:             models.ZeroValue,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Banners) == 0 {
+		return nil, nil
+	}
+	banners := make([]candidateBanner, 0, len(page.Banners))
+	ids := make([]int, 0, len(page.Banners))
+	for _, banner := range page.Banners {
+		banners = append(banners, candidateBanner{
+			Candidate: models.Candidate{BannerId: banner.BannerId, UserBanner: banner.UserBanner},
+			FeatureId: banner.FeatureId,
+			TagIds:    banner.TagIds,
+			IsActive:  banner.IsActive,
+			Live:      banner.Live,
+		})
+		ids = append(ids, banner.BannerId)
+	}
+	targeting, err := s.db.ListTargeting(newCtx, ids)
+	if err != nil {
+		log.Warn(err.Error(), utils.Text(op))
+		return nil, e.ErrorInternal
+	}
+	for i := range targeting {
+		for j := range banners {
+			if banners[j].BannerId == targeting[i].BannerId {
+				banners[j].Targeting = &targeting[i]
+			}
+		}
+	}
+	if locale == models.DefaultLocale {
+		return banners, nil
+	}
+	if err = s.translateCandidates(newCtx, banners, locale); err != nil {
+		log.Warn(err.Error(), utils.Text(op))
+		return nil, e.ErrorInternal
+	}
+	return banners, nil
+}
+
+// validateRule requires comparable app versions forming a range and normalizes locales of the rule
+func validateRule(rule *models.TargetingRule) error {
+	var violations []e.FieldViolation
+	for i, locale := range rule.Locales {
+		normalized, err := models.NormalizeLocale(locale)
+		if err != nil {
+			violations = append(violations, e.FieldViolation{Field: fmt.Sprintf("rule.locales[%d]", i), Message: err.Error()})
+			continue
+		}
+		rule.Locales[i] = normalized
+	}
+	versions := []struct {
+		field   string
+		version string
+	}{
+		{"rule.min_version", rule.MinVersion},
+		{"rule.max_version", rule.MaxVersion},
+	}
+	for _, version := range versions {
+		if version.version != "" && !models.ValidVersion(version.version) {
+			violations = append(violations, e.FieldViolation{Field: version.field, Message: models.ErrInvalidVersion.Error()})
+		}
+	}
+	if len(violations) == 0 && rule.MinVersion != "" && rule.MaxVersion != "" && models.CompareVersions(rule.MinVersion, rule.MaxVersion) > 0 {
+		violations = append(violations, e.FieldViolation{Field: "rule.max_version", Message: "is lower than min_version"})
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
+
+// normalizeAttributes requires the app version of the user to be comparable with the ones of rules
+// and normalizes the locale, so it is matched and cached regardless of its case
+func normalizeAttributes(attrs *models.UserAttributes) error {
+	var violations []e.FieldViolation
+	if attrs.AppVersion != "" && !models.ValidVersion(attrs.AppVersion) {
+		violations = append(violations, e.FieldViolation{Field: "app_version", Message: models.ErrInvalidVersion.Error()})
+	}
+	if attrs.Locale != models.DefaultLocale {
+		locale, err := models.NormalizeLocale(attrs.Locale)
+		if err != nil {
+			violations = append(violations, e.FieldViolation{Field: "locale", Message: err.Error()})
+		}
+		attrs.Locale = locale
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// targetingBanners are banners with their targeting and translations found by the targeting database
+// and the options banners were listed with
+type targetingBanners struct {
+	banners      []models.BannerExt
+	targeting    []models.Targeting
+	translations []models.Translation
+	listed       []models.BannerListOptions
+}
+
+// cacheKey is the feature, the tag and the locale candidates are cached by
+type cacheKey struct {
+	featureId int
+	tagId     int
+	locale    string
+}
+
+// mapCache keeps candidates by feature, tag and locale and counts reads
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]models.Candidate
+	reads   int
+}
+
+func (m *mapCache) GetMany(_ context.Context, options []models.BannerIdentOptions, locale string) ([]*models.Candidate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	banners := make([]*models.Candidate, len(options))
+	for i := range options {
+		if candidate, ok := m.entries[cacheKey{featureId: options[i].FeatureId, tagId: options[i].TagId, locale: locale}]; ok {
+			banners[i] = &candidate
+		}
+	}
+	return banners, nil
+}
+
+func (m *mapCache) Put(_ context.Context, options *models.BannerIdentOptions, locale string, banner *models.Candidate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[cacheKey{featureId: options.FeatureId, tagId: options.TagId, locale: locale}] = *banner
+	return nil
+}
+
+func (m *mapCache) Invalidate(_ context.Context, options []models.BannerIdentOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.entries {
+		for _, option := range options {
+			if key.featureId == option.FeatureId && key.tagId == option.TagId {
+				delete(m.entries, key)
+			}
+		}
+	}
+	return nil
+}
+
+// newTargetingDatabase lists banners of the feature having any of the tags with their targeting and translations
+func newTargetingDatabase(t *testing.T) (*mocks.MockDatabase, *targetingBanners) {
+	banner := func(id int, tagIds []int, isActive bool) models.BannerExt {
+		return models.BannerExt{
+			BannerId: id,
+			Banner: models.Banner{
+				BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: tagIds, UserBanner: models.UserBanner{Content: map[string]any{"id": id}}},
+				IsActive:   isActive,
+			},
+			Live: true,
+		}
+	}
+	data := &targetingBanners{
+		banners: []models.BannerExt{banner(1, []int{10}, true), banner(2, []int{11}, true), banner(3, []int{12}, false)},
+		targeting: []models.Targeting{
+			{BannerId: 2, Priority: 5, Rule: models.TargetingRule{Locales: []string{"ru"}, MinVersion: "2.0"}},
+			{BannerId: 3, Priority: 9},
+		},
+	}
+	db := newMockDatabase(t)
+	expectRegistered(db)
+	db.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, options *models.BannerListOptions) ([]models.BannerExt, error) {
+		data.listed = append(data.listed, *options)
+		var banners []models.BannerExt
+		for _, banner := range data.banners {
+			if slices.Contains(options.FeatureIds, banner.FeatureId) && slices.ContainsFunc(banner.TagIds, func(id int) bool {
+				return slices.Contains(options.TagIds, id)
+			}) {
+				banners = append(banners, banner)
+			}
+		}
+		return banners, nil
+	}).AnyTimes()
+	db.EXPECT().ListTargeting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ids []int) ([]models.Targeting, error) {
+		var targeting []models.Targeting
+		for _, item := range data.targeting {
+			if slices.Contains(ids, item.BannerId) {
+				targeting = append(targeting, item)
+			}
+		}
+		return targeting, nil
+	}).AnyTimes()
+	db.EXPECT().FindTranslations(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ids []int, locales []string) ([]models.Translation, error) {
+		var translations []models.Translation
+		for _, translation := range data.translations {
+			if slices.Contains(ids, translation.BannerId) && slices.Contains(locales, translation.Locale) {
+				translations = append(translations, translation)
+			}
+		}
+		return translations, nil
+	}).AnyTimes()
+	return db, data
+}
+
+func TestUserGetBannersTargeting(t *testing.T) {
+	db, data := newTargetingDatabase(t)
+	cache := &mapCache{entries: map[cacheKey]models.Candidate{}}
+	service := newConfiguredService(db, cache, config.ServiceConfig{})
+	get := func(attrs models.UserAttributes) (any, error) {
+		banner, err := service.UserGetBanners(context.Background(), &models.BannerUserOptions{
+			BannerIdentOptions: models.BannerIdentOptions{FeatureId: 1, TagId: attrs.TagIds[0]},
+			Attributes:         attrs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return banner.Content["id"], nil
+	}
+
+	id, err := get(models.UserAttributes{TagIds: []int{10, 11, 12}, Locale: "ru", AppVersion: "2.1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, id, "the matching banner of the highest priority is given, inactive ones are skipped")
+	id, err = get(models.UserAttributes{TagIds: []int{10, 11}, Locale: "ru", AppVersion: "1.9"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+	_, err = get(models.UserAttributes{TagIds: []int{11}, Locale: "en", AppVersion: "2.1"})
+	assert.ErrorIs(t, err, e.ErrorNotFound)
+	_, err = get(models.UserAttributes{TagIds: []int{10}, AppVersion: "2.x"})
+	assert.ErrorIs(t, err, e.ErrorValidation)
+
+	service.wg.Wait()
+	listed := len(data.listed)
+	id, err = get(models.UserAttributes{TagIds: []int{11, 10}, Locale: "ru", AppVersion: "2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, id, "targeting is cached with the banner")
+	assert.Equal(t, listed, len(data.listed), "cached tags are not looked up")
+}
+
+func TestUserGetFeatureBanners(t *testing.T) {
+	db, data := newTargetingDatabase(t)
+	data.banners = append(data.banners, models.BannerExt{
+		BannerId: 4,
+		Banner: models.Banner{
+			BaseBanner: models.BaseBanner{FeatureId: 2, TagIds: []int{11}, UserBanner: models.UserBanner{Content: map[string]any{"id": 4}}},
+			IsActive:   true,
+		},
+		Live: true,
+	})
+	cache := &mapCache{entries: map[cacheKey]models.Candidate{}}
+	service := newConfiguredService(db, cache, config.ServiceConfig{})
+	get := func(featureIds ...int) map[int]any {
+		banners, err := service.UserGetFeatureBanners(context.Background(), &models.BannersUserOptions{
+			FeatureIds: featureIds,
+			Attributes: models.UserAttributes{TagIds: []int{10, 11}, Locale: "en"},
+		})
+		require.NoError(t, err)
+		ids := make(map[int]any, len(banners))
+		for featureId, banner := range banners {
+			ids[featureId] = nil
+			if banner != nil {
+				ids[featureId] = banner.Content["id"]
+			}
+		}
+		return ids
+	}
+
+	assert.Equal(t, map[int]any{1: 1, 2: 4, 3: nil}, get(1, 2, 3, 2), "features without banners are not found")
+	assert.Len(t, data.listed, 1, "banners of every feature are listed at once")
+	assert.Equal(t, []int{1, 2, 3}, data.listed[0].FeatureIds)
+
+	service.wg.Wait()
+	reads := cache.reads
+	assert.Equal(t, map[int]any{1: 1, 2: 4}, get(1, 2))
+	require.Len(t, data.listed, 2)
+	assert.Equal(t, []int{2}, data.listed[1].FeatureIds, "only the feature having an uncached tag is looked up")
+	assert.Equal(t, []int{10}, data.listed[1].TagIds)
+	assert.Equal(t, reads+1, cache.reads, "banners of every feature are read from the cache at once")
+
+	_, err := service.UserGetFeatureBanners(context.Background(), &models.BannersUserOptions{
+		FeatureIds: make([]int, maxUserFeatures+1),
+		Attributes: models.UserAttributes{TagIds: []int{10}},
+	})
+	assert.ErrorIs(t, err, e.ErrorValidation)
+}
+
+func TestEvaluateBanner(t *testing.T) {
+	db, _ := newTargetingDatabase(t)
+	service := newTestService(db)
+	evaluation, err := service.EvaluateBanner(context.Background(), &models.EvaluationOptions{
+		FeatureId:  1,
+		Attributes: models.UserAttributes{TagIds: []int{10, 11, 12}, Locale: "en", AppVersion: "2.0"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, evaluation.BannerId)
+	assert.Equal(t, map[string]any{"id": 1}, evaluation.Content)
+	assert.Equal(t, []models.CandidateResult{
+		{BannerId: 3, Priority: 9, Live: true, Matched: true, Reasons: []string{"banner is inactive"}},
+		{BannerId: 2, Priority: 5, IsActive: true, Live: true, Reasons: []string{`locale "en" is not one of [ru]`}},
+		{BannerId: 1, IsActive: true, Live: true, Matched: true, Served: true},
+	}, evaluation.Candidates)
+
+	evaluation, err = service.EvaluateBanner(context.Background(), &models.EvaluationOptions{
+		FeatureId:  1,
+		Attributes: models.UserAttributes{TagIds: []int{10, 11}, Locale: "ru", AppVersion: "2.0"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, evaluation.BannerId)
+	assert.Equal(t, []string{"banner 2 is chosen before it"}, evaluation.Candidates[1].Reasons)
+}
+
+func TestPutTargetingValidatesVersions(t *testing.T) {
+	db, _ := newTargetingDatabase(t)
+	service := newTestService(db)
+	err := service.PutTargeting(context.Background(), &models.Targeting{BannerId: 1, Rule: models.TargetingRule{MinVersion: "2.0", MaxVersion: "1.5"}})
+	var validationErr *e.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []e.FieldViolation{{Field: "rule.max_version", Message: "is lower than min_version"}}, validationErr.Violations)
+}
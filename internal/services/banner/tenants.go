@@ -0,0 +1,76 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// GetTenantQuota returns the number of banners of the request tenant and its quota
+func (s *Service) GetTenantQuota(ctx context.Context) (*models.TenantQuota, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetTenantQuota"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	banners, err := s.countTenantBanners(newCtx)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	tenant := identity.From(ctx).Tenant
+	return &models.TenantQuota{Tenant: tenant, Banners: banners, MaxBanners: s.maxBanners(tenant)}, nil
+}
+
+// maxBanners returns the quota of the tenant, zero does not limit banners
+func (s *Service) maxBanners(tenant string) int {
+	if quota, ok := s.quotas.Quotas[tenant]; ok {
+		return quota
+	}
+	return s.quotas.MaxBanners
+}
+
+// remainingQuota returns the number of banners the request tenant may add, ZeroValue when they are not limited.
+// Banners are counted before they are added, so concurrent requests may exceed the quota by a few banners
+func (s *Service) remainingQuota(ctx context.Context, log *slog.Logger) (int, error) {
+	quota := s.maxBanners(identity.From(ctx).Tenant)
+	if quota <= 0 {
+		return models.ZeroValue, nil
+	}
+	banners, err := s.countTenantBanners(ctx)
+	if err != nil {
+		log.Warn("failed to count banners", utils.Err(err))
+		return 0, e.ErrorInternal
+	}
+	return max(quota-banners, 0), nil
+}
+
+// checkQuota returns e.ErrorQuotaExceeded when the request tenant can't add one more banner
+func (s *Service) checkQuota(ctx context.Context, log *slog.Logger) error {
+	remaining, err := s.remainingQuota(ctx, log)
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return fmt.Errorf("%w: %d banners", e.ErrorQuotaExceeded, s.maxBanners(identity.From(ctx).Tenant))
+	}
+	return nil
+}
+
+// countTenantBanners counts banners of the request tenant except deleted ones, the database filters them by the tenant
+func (s *Service) countTenantBanners(ctx context.Context) (int, error) {
+	return s.db.Count(ctx, &models.BannerListOptions{
+		BannerIdentOptions: models.BannerIdentOptions{FeatureId: models.ZeroValue, TagId: models.ZeroValue},
+		Limit:              models.ZeroValue,
+		Offset:             models.ZeroValue,
+	})
+}
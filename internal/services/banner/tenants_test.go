@@ -0,0 +1,55 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/identity"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTenantQuota(t *testing.T) {
+	// banners added by every tenant are counted
+	banners := map[string]int{}
+	added := 0
+	db := newMockDatabase(t)
+	expectRegistered(db)
+	expectNoSchemas(db)
+	db.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *models.Banner) (int, error) {
+		banners[identity.From(ctx).Tenant]++
+		added++
+		return added, nil
+	}).AnyTimes()
+	db.EXPECT().Count(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ *models.BannerListOptions) (int, error) {
+		return banners[identity.From(ctx).Tenant], nil
+	}).AnyTimes()
+	service := newConfiguredService(db, nil, config.ServiceConfig{
+		Tenants: config.TenantsConfig{MaxBanners: 2, Quotas: map[string]int{"large": 3, "unlimited": 0}},
+	})
+	banner := &models.Banner{BaseBanner: models.BaseBanner{FeatureId: 1, TagIds: []int{10}}}
+	create := func(tenant string, times int) error {
+		ctx := identity.With(context.Background(), &identity.Identity{Tenant: tenant})
+		for i := 0; i < times; i++ {
+			if _, err := service.CreateBanner(ctx, banner); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	require.NoError(t, create("small", 2))
+	assert.ErrorIs(t, create("small", 1), e.ErrorQuotaExceeded)
+	require.NoError(t, create("large", 3), "quota of the tenant overrides max_banners")
+	assert.ErrorIs(t, create("large", 1), e.ErrorQuotaExceeded)
+	require.NoError(t, create("unlimited", 5))
+	assert.Equal(t, 10, added)
+
+	quota, err := service.GetTenantQuota(identity.With(context.Background(), &identity.Identity{Tenant: "large"}))
+	require.NoError(t, err)
+	assert.Equal(t, &models.TenantQuota{Tenant: "large", Banners: 3, MaxBanners: 3}, quota)
+}
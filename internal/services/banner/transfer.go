@@ -0,0 +1,137 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"sync/atomic"
+)
+
+// maxReportedErrors limits rejected records listed in the import report, all of them are counted
+const maxReportedErrors = 100
+
+// ExportBanners passes every banner matching options to fn in the order of ids without loading them all.
+// Export lasts as long as the client reads it, so it is limited by ctx instead of the service timeout.
+// Errors of fn are returned as is
+func (s *Service) ExportBanners(ctx context.Context, options *models.BannerListOptions, fn func(banner *models.BannerExt) error) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ExportBanners"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	var fnErr error
+	err := s.db.ListEach(ctx, options, func(banner *models.BannerExt) error {
+		fnErr = fn(banner)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		log.Warn("failed to export banners", utils.Err(err))
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// ImportBanners reads records with next until io.EOF. Records failing validation and *models.RecordError
+// returned by next are rejected, other errors of next stop the import. Nothing is imported when any record
+// is rejected, the report lists them instead. Like export, import is limited by ctx only.
+// When approval is required only the dry run is allowed, banners created beyond the quota of the tenant fail the import
+func (s *Service) ImportBanners(ctx context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions) (*models.ImportReport, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ImportBanners"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	switch options.Mode {
+	case models.ImportUpsert, models.ImportSkipExisting, models.ImportFailOnConflict:
+	default:
+		return nil, e.NewValidationError(e.FieldViolation{Field: "mode", Message: "must be one of upsert, skip_existing, fail_on_conflict"})
+	}
+	// imported banners would reach users without review, so only the dry run is allowed then
+	if s.approvalRequired && !options.DryRun {
+		return nil, e.ErrorReviewRequired
+	}
+	maxCreated, err := s.remainingQuota(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+	options = &models.ImportOptions{Mode: options.Mode, DryRun: options.DryRun, MaxCreated: maxCreated}
+	report := &models.ImportReport{Mode: options.Mode, DryRun: options.DryRun}
+	// records come in the order of lines, so the first rejected of them are kept
+	reject := func(recordErr models.RecordError) {
+		report.Failed++
+		if len(report.Errors) < maxReportedErrors {
+			report.Errors = append(report.Errors, recordErr)
+		}
+	}
+	var readErr error
+	err = s.db.Import(ctx, func() (*models.ImportRecord, error) {
+		for {
+			record, err := next()
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			var recordErr *models.RecordError
+			if errors.As(err, &recordErr) {
+				report.Total++
+				reject(*recordErr)
+				continue
+			}
+			if err != nil {
+				readErr = err
+				return nil, err
+			}
+			report.Total++
+			if readErr = s.validateRecord(ctx, record, log); readErr == nil {
+				return record, nil
+			}
+			var validationErr *e.ValidationError
+			if !errors.As(readErr, &validationErr) {
+				return nil, readErr
+			}
+			reject(models.RecordError{Line: record.Line, Violations: validationErr.Violations})
+			readErr = nil
+		}
+	}, options, report)
+	if readErr != nil {
+		if errors.Is(readErr, e.ErrorBadRequest) {
+			return nil, readErr
+		}
+		log.Warn("failed to read records", utils.Err(readErr))
+		return nil, e.ErrorInternal
+	}
+	if err != nil {
+		log.Warn("failed to import banners", utils.Err(err))
+		if errors.Is(err, e.ErrorConflict) || errors.Is(err, e.ErrorQuotaExceeded) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+	if len(report.Errors) > maxReportedErrors {
+		report.Errors = report.Errors[:maxReportedErrors]
+	}
+	return report, nil
+}
+
+// validateRecord checks the record the same way as a created banner
+func (s *Service) validateRecord(ctx context.Context, record *models.ImportRecord, log *slog.Logger) error {
+	update := &models.UpdateBanner{Banner: record.Banner, Flags: models.FeatureBit | models.TagBit}
+	if err := s.validateRegistered(ctx, update, log); err != nil {
+		return err
+	}
+	return s.validateContent(ctx, record.FeatureId, record.Content, log)
+}
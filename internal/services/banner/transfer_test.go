@@ -0,0 +1,142 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/services/banner/mocks"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectImport makes the database copy every record it is given into copied and reject the ones listed in conflicts
+func expectImport(db *mocks.MockDatabase, copied *[]int, conflicts []int) {
+	db.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, next func() (*models.ImportRecord, error), _ *models.ImportOptions, report *models.ImportReport) error {
+		for {
+			record, err := next()
+			if err != nil {
+				return err
+			}
+			if record == nil {
+				break
+			}
+			*copied = append(*copied, record.Line)
+		}
+		for _, line := range conflicts {
+			report.Failed++
+			report.Errors = append(report.Errors, models.RecordError{Line: line, Violations: []e.FieldViolation{{Field: "tag_ids"}}})
+		}
+		report.Created = len(*copied) - len(conflicts)
+		report.Applied = report.Failed == 0
+		return nil
+	}).AnyTimes()
+}
+
+func importRecord(line int, tagIds ...int) models.ImportRecord {
+	return models.ImportRecord{Line: line, Banner: models.Banner{BaseBanner: models.BaseBanner{
+		UserBanner: models.UserBanner{Content: map[string]any{"title": "Sale"}},
+		FeatureId:  1,
+		TagIds:     tagIds,
+	}}}
+}
+
+// records returns next reading records, error items are returned as errors of next
+func records(items ...any) func() (*models.ImportRecord, error) {
+	return func() (*models.ImportRecord, error) {
+		if len(items) == 0 {
+			return nil, io.EOF
+		}
+		item := items[0]
+		items = items[1:]
+		if err, ok := item.(error); ok {
+			return nil, err
+		}
+		record := item.(models.ImportRecord)
+		return &record, nil
+	}
+}
+
+func TestImportBanners(t *testing.T) {
+	readErr := errors.New("connection reset")
+	tests := []struct {
+		name        string
+		mode        models.ImportMode
+		records     []any
+		conflicts   []int
+		wantErr     error
+		wantCopied  []int
+		wantLines   []int
+		wantTotal   int
+		wantApplied bool
+	}{
+		{
+			name:        "valid records",
+			mode:        models.ImportUpsert,
+			records:     []any{importRecord(1, 10), importRecord(2, 11)},
+			wantCopied:  []int{1, 2},
+			wantTotal:   2,
+			wantApplied: true,
+		},
+		{
+			name: "rejected records are reported in order of lines",
+			mode: models.ImportSkipExisting,
+			records: []any{
+				importRecord(1, 10),
+				&models.RecordError{Line: 2, Violations: []e.FieldViolation{{Field: "record"}}},
+				importRecord(3, 99),
+				importRecord(4, 11),
+			},
+			conflicts:  []int{1},
+			wantCopied: []int{1, 4},
+			wantLines:  []int{1, 2, 3},
+			wantTotal:  4,
+		},
+		{
+			name:    "unknown mode",
+			mode:    "replace",
+			wantErr: e.ErrorValidation,
+		},
+		{
+			name:    "bad request stops import",
+			mode:    models.ImportUpsert,
+			records: []any{importRecord(1, 10), e.NewValidationError(e.FieldViolation{Field: "body"})},
+			wantErr: e.ErrorValidation,
+		},
+		{
+			name:    "failed reading",
+			mode:    models.ImportUpsert,
+			records: []any{readErr},
+			wantErr: e.ErrorInternal,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var copied []int
+			db := newMockDatabase(t)
+			expectRegistered(db)
+			expectNoSchemas(db)
+			expectImport(db, &copied, test.conflicts)
+			report, err := newTestService(db).ImportBanners(context.Background(), records(test.records...), &models.ImportOptions{Mode: test.mode})
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantCopied, copied)
+			assert.Equal(t, test.mode, report.Mode)
+			assert.Equal(t, test.wantTotal, report.Total)
+			assert.Equal(t, len(test.wantLines), report.Failed)
+			var lines []int
+			for _, recordErr := range report.Errors {
+				lines = append(lines, recordErr.Line)
+			}
+			assert.Equal(t, test.wantLines, lines)
+			assert.Equal(t, test.wantApplied, report.Applied)
+		})
+	}
+}
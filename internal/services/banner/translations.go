@@ -0,0 +1,178 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ListTranslations returns translations of the banner ordered by locale, the banner itself has the default content
+func (s *Service) ListTranslations(ctx context.Context, id int) ([]models.Translation, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ListTranslations"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if _, err := s.db.GetById(newCtx, id); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	translations, err := s.db.ListTranslations(newCtx, id)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return translations, nil
+}
+
+// PutTranslation sets the content of the banner in the locale, it is validated by the schema of the feature
+// like the default content. Translations have no drafts, so they are rejected while changes require review
+func (s *Service) PutTranslation(ctx context.Context, translation *models.Translation) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.PutTranslation"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	// the translation would reach users without review
+	if s.approvalRequired {
+		return e.ErrorReviewRequired
+	}
+	locale, err := normalizeLocale(translation.Locale)
+	if err != nil {
+		return err
+	}
+	translation.Locale = locale
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	banner, err := s.db.GetById(newCtx, translation.BannerId)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	if err = s.validateContent(newCtx, banner.FeatureId, translation.Content, log); err != nil {
+		return err
+	}
+	if err = s.db.PutTranslation(newCtx, translation); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// DeleteTranslation removes the translation of the banner, like PutTranslation it is rejected while changes require review
+func (s *Service) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.DeleteTranslation"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	if s.approvalRequired {
+		return e.ErrorReviewRequired
+	}
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err = s.db.DeleteTranslation(newCtx, id, locale); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// MissingTranslations reports banners lacking translations to any of the locales. Only exact translations count,
+// so a banner translated to ru misses ru-RU even though users of ru-RU get its ru content
+func (s *Service) MissingTranslations(ctx context.Context, featureId int, locales []string) ([]models.MissingTranslations, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.MissingTranslations"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	normalized := make([]string, 0, len(locales))
+	var violations []e.FieldViolation
+	for i, locale := range locales {
+		locale, err := models.NormalizeLocale(locale)
+		if err != nil {
+			violations = append(violations, e.FieldViolation{Field: fmt.Sprintf("locales[%d]", i), Message: err.Error()})
+			continue
+		}
+		normalized = append(normalized, locale)
+	}
+	if len(violations) > 0 {
+		return nil, e.NewValidationError(violations...)
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	missing, err := s.db.MissingTranslations(newCtx, featureId, normalized)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return missing, nil
+}
+
+// translateCandidates replaces content of the banners with their translation to the closest locale of the chain
+func (s *Service) translateCandidates(newCtx context.Context, banners []candidateBanner, locale string) error {
+	chain := models.LocaleChain(locale)
+	ids := make([]int, 0, len(banners))
+	for _, banner := range banners {
+		ids = append(ids, banner.BannerId)
+	}
+	translations, err := s.db.FindTranslations(newCtx, ids, chain[:len(chain)-1])
+	if err != nil {
+		return err
+	}
+	for i := range banners {
+		best := len(chain) - 1
+		for _, translation := range translations {
+			if translation.BannerId != banners[i].BannerId {
+				continue
+			}
+			for rank, candidate := range chain[:best] {
+				if translation.Locale == candidate {
+					best = rank
+					banners[i].Content = translation.Content
+					banners[i].Locale = translation.Locale
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeLocale(locale string) (string, error) {
+	normalized, err := models.NormalizeLocale(locale)
+	if err != nil {
+		return "", e.NewValidationError(e.FieldViolation{Field: "locale", Message: err.Error()})
+	}
+	return normalized, nil
+}
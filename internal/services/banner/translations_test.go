@@ -0,0 +1,62 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserGetBannersTranslations(t *testing.T) {
+	db, data := newTargetingDatabase(t)
+	data.translations = []models.Translation{
+		{BannerId: 1, Locale: "ru", Content: map[string]any{"id": "ru"}},
+		{BannerId: 1, Locale: "ru-RU", Content: map[string]any{"id": "ru-RU"}},
+		{BannerId: 1, Locale: "de", Content: map[string]any{"id": "de"}},
+	}
+	cache := &mapCache{entries: map[cacheKey]models.Candidate{}}
+	service := newConfiguredService(db, cache, config.ServiceConfig{})
+	get := func(locale string) (any, error) {
+		banner, err := service.UserGetBanners(context.Background(), &models.BannerUserOptions{
+			BannerIdentOptions: models.BannerIdentOptions{FeatureId: 1, TagId: 10},
+			Attributes:         models.UserAttributes{TagIds: []int{10}, Locale: locale},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return banner.Content["id"], nil
+	}
+
+	for locale, want := range map[string]any{"ru_ru": "ru-RU", "ru-BY": "ru", "de-AT": "de", "en": 1, "": 1} {
+		id, err := get(locale)
+		require.NoError(t, err, locale)
+		assert.Equal(t, want, id, "locale %q falls back to the closest translation", locale)
+	}
+	_, err := get("r")
+	assert.ErrorIs(t, err, e.ErrorValidation)
+
+	service.wg.Wait()
+	assert.Equal(t, "ru", cache.entries[cacheKey{featureId: 1, tagId: 10, locale: "ru-BY"}].Locale, "banners are cached by the requested locale")
+	assert.Equal(t, models.DefaultLocale, cache.entries[cacheKey{featureId: 1, tagId: 10, locale: "en"}].Locale)
+}
+
+func TestMissingTranslationsValidatesLocales(t *testing.T) {
+	db, _ := newTargetingDatabase(t)
+	service := newTestService(db)
+	_, err := service.MissingTranslations(context.Background(), models.ZeroValue, []string{"ru", "en-", "de"})
+	var validationErr *e.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []e.FieldViolation{{Field: "locales[1]", Message: models.ErrInvalidLocale.Error()}}, validationErr.Violations)
+}
+
+func TestTranslationsRequireReview(t *testing.T) {
+	// translations are not changed
+	service := newReviewService(newMockDatabase(t))
+	err := service.PutTranslation(context.Background(), &models.Translation{BannerId: 1, Locale: "ru", Content: map[string]any{"title": "Распродажа"}})
+	assert.ErrorIs(t, err, e.ErrorReviewRequired, "translations have no drafts")
+	assert.ErrorIs(t, service.DeleteTranslation(context.Background(), 1, "ru"), e.ErrorReviewRequired)
+}
@@ -0,0 +1,69 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/utils"
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// RestoreBanner takes the deleted banner out of the trash. The banner gets its feature and tags back,
+// so it conflicts with a banner created with any of them after the deletion
+func (s *Service) RestoreBanner(ctx context.Context, id int) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.RestoreBanner"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.checkQuota(newCtx, log); err != nil {
+		return err
+	}
+	err := s.db.Restore(newCtx, id)
+	if err != nil {
+		log.Warn("failed to restore banner", utils.Err(err))
+		if errors.Is(err, e.ErrorNotFound) || errors.Is(err, e.ErrorConflict) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// runTrashPurge periodically removes banners deleted earlier than the retention until the service is stopped
+func (s *Service) runTrashPurge() {
+	const op = "banner.runTrashPurge"
+	if s.trashRetention <= 0 || s.purgeInterval <= 0 {
+		return
+	}
+	log := s.logger.With(utils.Text(op))
+	ticker := time.NewTicker(s.purgeInterval)
+	defer ticker.Stop()
+	for {
+		s.purgeTrash(log)
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) purgeTrash(log *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	removed, err := s.db.PurgeTrash(ctx, s.trashRetention)
+	if err != nil {
+		log.Warn("failed to purge trash", utils.Err(err))
+		return
+	}
+	if removed > 0 {
+		log.Info("trash purged", slog.Int64("removed", removed))
+	}
+}
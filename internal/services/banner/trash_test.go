@@ -0,0 +1,106 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRestoreBanner(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		restoreErr error
+		wantErr    error
+	}{
+		{name: "restored"},
+		{name: "not in trash", restoreErr: e.ErrorNotFound, wantErr: e.ErrorNotFound},
+		{name: "keys are taken", restoreErr: e.ErrorConflict, wantErr: e.ErrorConflict},
+		{name: "failed", restoreErr: errors.New("connection reset"), wantErr: e.ErrorInternal},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			db := newMockDatabase(t)
+			db.EXPECT().Restore(gomock.Any(), 1).Return(test.restoreErr)
+			err := newTestService(db).RestoreBanner(context.Background(), 1)
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestDeleteBannerErrors(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		ifMatch   string
+		deleteErr error
+		wantErr   error
+	}{
+		{name: "moved to trash"},
+		{name: "missing banner", deleteErr: e.ErrorNotFound, wantErr: e.ErrorNotFound},
+		{name: "stale etag", ifMatch: `"1-active"`, deleteErr: e.ErrorPreconditionFailed, wantErr: e.ErrorPreconditionFailed},
+		{name: "failed", deleteErr: errors.New("connection reset"), wantErr: e.ErrorInternal},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			db := newMockDatabase(t)
+			db.EXPECT().DeleteById(gomock.Any(), 1, test.ifMatch).Return(test.deleteErr)
+			err := newTestService(db).DeleteBanner(context.Background(), 1, test.ifMatch)
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestTrashPurge(t *testing.T) {
+	tests := []struct {
+		name      string
+		trash     config.TrashConfig
+		wantPurge bool
+		// failFirst makes the first purge fail, the following ones still run
+		failFirst bool
+	}{
+		{name: "kept forever", trash: config.TrashConfig{PurgeInterval: time.Millisecond}},
+		{name: "retention", trash: config.TrashConfig{Retention: time.Hour, PurgeInterval: time.Millisecond}, wantPurge: true},
+		{name: "failed purge", trash: config.TrashConfig{Retention: time.Hour, PurgeInterval: time.Millisecond}, wantPurge: true, failFirst: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var retention atomic.Int64
+			var purged atomic.Int32
+			db := newMockDatabase(t)
+			db.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, trashRetention time.Duration) (int64, error) {
+				retention.Store(int64(trashRetention))
+				if purged.Add(1) == 1 && test.failFirst {
+					return 0, errors.New("connection reset")
+				}
+				return 1, nil
+			}).AnyTimes()
+			srv := newConfiguredService(db, nil, config.ServiceConfig{Trash: test.trash})
+			done := make(chan struct{})
+			go func() {
+				srv.runTrashPurge()
+				close(done)
+			}()
+			time.Sleep(20 * time.Millisecond)
+			srv.Stop(context.Background())
+			<-done
+			if test.wantPurge {
+				assert.Greater(t, purged.Load(), int32(1))
+				assert.Equal(t, int64(time.Hour), retention.Load())
+			} else {
+				assert.Zero(t, purged.Load())
+			}
+		})
+	}
+}
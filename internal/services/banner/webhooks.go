@@ -0,0 +1,362 @@
+package banner
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// headers of deliveries, clients verify them with api.VerifyWebhookSignature
+const (
+	eventHeader     = "X-BannerFlow-Event"
+	deliveryHeader  = "X-BannerFlow-Delivery"
+	timestampHeader = "X-BannerFlow-Timestamp"
+	signatureHeader = "X-BannerFlow-Signature"
+)
+
+const (
+	// minSecretLength makes secrets of webhooks hard to guess
+	minSecretLength = 16
+	// maxErrorLength is the length of the error of the attempt kept in the delivery log
+	maxErrorLength = 512
+)
+
+func (s *Service) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ListWebhooks"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	webhooks, err := s.db.ListWebhooks(newCtx)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return webhooks, nil
+}
+
+func (s *Service) GetWebhook(ctx context.Context, id int) (*models.Webhook, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.GetWebhook"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	webhook, err := s.db.GetWebhook(newCtx, id)
+	if err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return nil, err
+		}
+		return nil, e.ErrorInternal
+	}
+	return webhook, nil
+}
+
+// CreateWebhook registers the endpoint of the tenant, it gets deliveries of events changed afterwards
+func (s *Service) CreateWebhook(ctx context.Context, webhook *models.Webhook) (int, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.CreateWebhook"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return 0, e.ErrorInternal
+	}
+	if err := validateWebhook(webhook, true); err != nil {
+		return 0, err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	id, err := s.db.AddWebhook(newCtx, webhook)
+	if err != nil {
+		log.Warn(err.Error())
+		return 0, e.ErrorInternal
+	}
+	return id, nil
+}
+
+// UpdateWebhook replaces the webhook, empty Secret keeps the current one. Pending deliveries are sent
+// with the new endpoint and secret
+func (s *Service) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.UpdateWebhook"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	if err := validateWebhook(webhook, false); err != nil {
+		return err
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.db.UpdateWebhook(newCtx, webhook); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// DeleteWebhook removes the webhook, its deliveries are never sent
+func (s *Service) DeleteWebhook(ctx context.Context, id int) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.DeleteWebhook"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.db.DeleteWebhook(newCtx, id); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log, ZeroValue WebhookId lists deliveries of every webhook of the tenant
+func (s *Service) ListDeliveries(ctx context.Context, options *models.DeliveryListOptions) ([]models.WebhookDelivery, error) {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.ListDeliveries"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return nil, e.ErrorInternal
+	}
+	switch options.Status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, e.NewValidationError(e.FieldViolation{Field: "status", Message: "is unknown"})
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if options.WebhookId != models.ZeroValue {
+		// deliveries of webhooks of other tenants are never listed, but the missing webhook is reported
+		if _, err := s.db.GetWebhook(newCtx, options.WebhookId); err != nil {
+			log.Warn(err.Error())
+			if errors.Is(err, e.ErrorNotFound) {
+				return nil, err
+			}
+			return nil, e.ErrorInternal
+		}
+	}
+	deliveries, err := s.db.ListDeliveries(newCtx, options)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, e.ErrorInternal
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery sends the dead letter again with every attempt available
+func (s *Service) RetryDelivery(ctx context.Context, webhookId int, id int64) error {
+	atomic.AddInt64(&s.activeRequests, 1)
+	defer atomic.AddInt64(&s.activeRequests, -1)
+	const op = "banner.RetryDelivery"
+	log := s.logger.With(utils.Text(op))
+	if s.ctxDone(ctx, log) {
+		return e.ErrorInternal
+	}
+	newCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.db.RetryDelivery(newCtx, webhookId, id); err != nil {
+		log.Warn(err.Error())
+		if errors.Is(err, e.ErrorNotFound) {
+			return err
+		}
+		return e.ErrorInternal
+	}
+	return nil
+}
+
+// validateWebhook requires an http endpoint, known events and, unless the webhook keeps its secret, a long secret
+func validateWebhook(webhook *models.Webhook, create bool) error {
+	var violations []e.FieldViolation
+	if target, err := url.Parse(webhook.Url); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		violations = append(violations, e.FieldViolation{Field: "url", Message: "is not an http or https url"})
+	}
+	if len(webhook.Events) == 0 {
+		violations = append(violations, e.FieldViolation{Field: "events", Message: "is empty"})
+	}
+	for i, event := range webhook.Events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			violations = append(violations, e.FieldViolation{Field: fmt.Sprintf("events[%d]", i), Message: "is unknown"})
+		}
+	}
+	if (create || webhook.Secret != "") && len(webhook.Secret) < minSecretLength {
+		violations = append(violations, e.FieldViolation{
+			Field:   "secret",
+			Message: fmt.Sprintf("is shorter than %d characters", minSecretLength),
+		})
+	}
+	if len(violations) > 0 {
+		return e.NewValidationError(violations...)
+	}
+	return nil
+}
+
+// runWebhookDeliveries sends due deliveries of every tenant until the service stops
+func (s *Service) runWebhookDeliveries() {
+	const op = "banner.runWebhookDeliveries"
+	if s.webhooks.PollInterval <= 0 || s.webhooks.BatchSize <= 0 {
+		return
+	}
+	log := s.logger.With(utils.Text(op))
+	ticker := time.NewTicker(s.webhooks.PollInterval)
+	defer ticker.Stop()
+	for {
+		// full batches are followed by the next one at once
+		for s.deliverWebhooks(log) == s.webhooks.BatchSize {
+			select {
+			case <-s.done:
+				return
+			default:
+			}
+		}
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverWebhooks sends a batch of due deliveries at once and returns the number of them
+func (s *Service) deliverWebhooks(log *slog.Logger) int {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	// the lease outlasts every attempt of the batch, so deliveries are not claimed twice meanwhile
+	deliveries, err := s.db.ClaimDeliveries(ctx, s.webhooks.BatchSize, 2*s.webhooks.Timeout+s.timeout)
+	if err != nil {
+		log.Warn("failed to claim webhook deliveries", utils.Err(err))
+		return 0
+	}
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.DueDelivery) {
+			defer wg.Done()
+			attempt := s.sendDelivery(delivery)
+			ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+			defer cancel()
+			if err := s.db.RecordAttempt(ctx, attempt); err != nil {
+				log.Warn("failed to record webhook delivery", slog.Int64("delivery", delivery.Id), utils.Err(err))
+				return
+			}
+			if attempt.Dead {
+				log.Warn("webhook delivery is dead", slog.Int64("delivery", delivery.Id),
+					slog.Int("webhook", delivery.WebhookId), slog.String("error", attempt.Error))
+			}
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+// sendDelivery posts the signed payload to the webhook, any 2xx response delivers it
+func (s *Service) sendDelivery(delivery *models.DueDelivery) *models.DeliveryAttempt {
+	attempt := &models.DeliveryAttempt{DeliveryId: delivery.Id}
+	ctx, cancel := context.WithTimeout(context.Background(), s.webhooks.Timeout)
+	defer cancel()
+	err := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+		if err != nil {
+			return err
+		}
+		timestamp := time.Now().Unix()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(eventHeader, string(delivery.Event))
+		req.Header.Set(deliveryHeader, strconv.FormatInt(delivery.Id, 10))
+		req.Header.Set(timestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(signatureHeader, models.SignWebhook(delivery.Secret, timestamp, delivery.Payload))
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		attempt.ResponseStatus = resp.StatusCode
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+		}
+		return nil
+	}()
+	if err == nil {
+		attempt.Delivered = true
+		return attempt
+	}
+	attempt.Error = err.Error()
+	if len(attempt.Error) > maxErrorLength {
+		attempt.Error = attempt.Error[:maxErrorLength]
+	}
+	attempts := delivery.Attempts + 1
+	attempt.Dead = attempts >= s.webhooks.MaxAttempts
+	attempt.RetryAfter = deliveryBackoff(attempts, s.webhooks.MinBackoff, s.webhooks.MaxBackoff)
+	return attempt
+}
+
+// deliveryBackoff doubles the delay after every failed attempt starting from minBackoff up to maxBackoff
+func deliveryBackoff(attempts int, minBackoff, maxBackoff time.Duration) time.Duration {
+	backoff := minBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// runDeliveryPruning removes old delivered deliveries, dead letters are kept until retried or their webhook is removed
+func (s *Service) runDeliveryPruning() {
+	const op = "banner.runDeliveryPruning"
+	if s.webhooks.Retention <= 0 || s.webhooks.PruneInterval <= 0 {
+		return
+	}
+	log := s.logger.With(utils.Text(op))
+	ticker := time.NewTicker(s.webhooks.PruneInterval)
+	defer ticker.Stop()
+	for {
+		s.pruneDeliveries(log)
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) pruneDeliveries(log *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	removed, err := s.db.PruneDeliveries(ctx, s.webhooks.Retention)
+	if err != nil {
+		log.Warn("failed to prune webhook deliveries", utils.Err(err))
+		return
+	}
+	if removed > 0 {
+		log.Info("webhook deliveries pruned", slog.Int64("removed", removed))
+	}
+}
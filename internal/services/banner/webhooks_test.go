@@ -0,0 +1,141 @@
+package banner
+
+import (
+	"BannerFlow/internal/config"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newWebhooksService(db Database) *Service {
+	return newConfiguredService(db, nil, config.ServiceConfig{
+		Webhooks: config.WebhooksConfig{
+			BatchSize:   10,
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			MinBackoff:  10 * time.Second,
+			MaxBackoff:  time.Minute,
+		},
+	})
+}
+
+func TestCreateWebhook(t *testing.T) {
+	var added *models.Webhook
+	db := newMockDatabase(t)
+	// only the valid webhook is added
+	db.EXPECT().AddWebhook(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, webhook *models.Webhook) (int, error) {
+		added = webhook
+		return 1, nil
+	})
+	db.EXPECT().UpdateWebhook(gomock.Any(), gomock.Any()).Return(nil)
+	service := newWebhooksService(db)
+	webhook := func(url, secret string, events ...models.WebhookEvent) *models.Webhook {
+		return &models.Webhook{Url: url, Secret: secret, Events: events, IsActive: true}
+	}
+
+	id, err := service.CreateWebhook(context.Background(), webhook("https://example.com/hook", "0123456789abcdef", models.WebhookCreated))
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assert.NotNil(t, added)
+
+	_, err = service.CreateWebhook(context.Background(), webhook("ftp://example.com", "short", "published"))
+	var validationErr *e.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []e.FieldViolation{
+		{Field: "url", Message: "is not an http or https url"},
+		{Field: "events[0]", Message: "is unknown"},
+		{Field: "secret", Message: "is shorter than 16 characters"},
+	}, validationErr.Violations)
+
+	_, err = service.CreateWebhook(context.Background(), webhook("http://example.com", "0123456789abcdef"))
+	assert.ErrorAs(t, err, &validationErr, "webhook without events")
+
+	err = service.UpdateWebhook(context.Background(), webhook("http://example.com", "", models.WebhookDeleted))
+	assert.NoError(t, err, "updated webhook keeps its secret")
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	secret := "0123456789abcdef"
+	var mu sync.Mutex
+	received := map[string]http.Header{}
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received[r.Header.Get(deliveryHeader)] = r.Header.Clone()
+		mu.Unlock()
+		timestamp, _ := strconv.ParseInt(r.Header.Get(timestampHeader), 10, 64)
+		if r.Header.Get(signatureHeader) != models.SignWebhook(secret, timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer endpoint.Close()
+	due := func(id int64, path string, attempts int) models.DueDelivery {
+		return models.DueDelivery{
+			WebhookDelivery: models.WebhookDelivery{Id: id, Event: models.WebhookUpdated, Payload: []byte(`{"banner_id":1}`), Attempts: attempts},
+			Url:             endpoint.URL + path,
+			Secret:          secret,
+		}
+	}
+	pending := []models.DueDelivery{due(1, "/", 0), due(2, "/failing", 1), due(3, "/failing", 2), due(4, "/", 0)}
+	pending[3].Secret = "another secret of the webhook"
+	var lease time.Duration
+	attempts := map[int64]models.DeliveryAttempt{}
+	db := newMockDatabase(t)
+	// the due deliveries are handed out once and their attempts are recorded
+	db.EXPECT().ClaimDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, limit int, claimLease time.Duration) ([]models.DueDelivery, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lease = claimLease
+		claimed := pending[:min(limit, len(pending))]
+		pending = pending[len(claimed):]
+		return claimed, nil
+	}).Times(2)
+	db.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *models.DeliveryAttempt) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[attempt.DeliveryId] = *attempt
+		return nil
+	}).Times(4)
+	service := newWebhooksService(db)
+
+	assert.Equal(t, 4, service.deliverWebhooks(service.logger))
+	assert.Greater(t, lease, service.webhooks.Timeout, "lease outlasts the attempt")
+	assert.Equal(t, map[int64]models.DeliveryAttempt{
+		1: {DeliveryId: 1, Delivered: true, ResponseStatus: http.StatusNoContent},
+		2: {DeliveryId: 2, ResponseStatus: http.StatusServiceUnavailable, Error: "unexpected response status 503", RetryAfter: 20 * time.Second},
+		3: {DeliveryId: 3, ResponseStatus: http.StatusServiceUnavailable, Error: "unexpected response status 503", Dead: true, RetryAfter: 40 * time.Second},
+		4: {DeliveryId: 4, ResponseStatus: http.StatusUnauthorized, Error: "unexpected response status 401", RetryAfter: 10 * time.Second},
+	}, attempts)
+	assert.Equal(t, "updated", received["1"].Get(eventHeader))
+	assert.Equal(t, "application/json", received["1"].Get("Content-Type"))
+	assert.Zero(t, service.deliverWebhooks(service.logger))
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:   time.Second,
+		2:   2 * time.Second,
+		4:   8 * time.Second,
+		5:   10 * time.Second,
+		100: 10 * time.Second,
+	} {
+		assert.Equal(t, want, deliveryBackoff(attempts, time.Second, 10*time.Second), attempts)
+	}
+}
@@ -0,0 +1,230 @@
+package validation
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	schemaRefPrefix    = "#/components/schemas/"
+	parameterRefPrefix = "#/components/parameters/"
+	responseRefPrefix  = "#/components/responses/"
+)
+
+// Spec is the subset of OpenAPI 3.0 document needed to validate requests and responses
+type Spec struct {
+	Paths      map[string]map[string]*Operation `yaml:"paths"`
+	Components struct {
+		Schemas    map[string]*Schema    `yaml:"schemas"`
+		Parameters map[string]*Parameter `yaml:"parameters"`
+		Responses  map[string]*Response  `yaml:"responses"`
+	} `yaml:"components"`
+	validator *Validator
+}
+
+type Operation struct {
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Response struct {
+	Ref     string                `yaml:"$ref"`
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+// LoadSpec parses OpenAPI document
+func LoadSpec(raw []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := yaml.Unmarshal(raw, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	spec.validator = NewValidator(func(ref string) (*Schema, bool) {
+		schema, ok := spec.Components.Schemas[strings.TrimPrefix(ref, schemaRefPrefix)]
+		return schema, ok
+	})
+	return spec, nil
+}
+
+// Schema returns component schema by name
+func (s *Spec) Schema(name string) (*Schema, bool) {
+	schema, ok := s.Components.Schemas[name]
+	return schema, ok
+}
+
+// Operation finds operation by method and path in OpenAPI form, e.g. /banner/{id}
+func (s *Spec) Operation(method, path string) (*Operation, bool) {
+	op, ok := s.Paths[path][strings.ToLower(method)]
+	return op, ok
+}
+
+// ValidateRequest validates parameters and body of the request. Body is restored for further reading
+func (s *Spec) ValidateRequest(op *Operation, req *http.Request, pathParams map[string]string) ([]e.FieldViolation, error) {
+	var violations []e.FieldViolation
+	query := req.URL.Query()
+	for _, p := range op.Parameters {
+		param := s.parameter(p)
+		var raw string
+		var present bool
+		switch param.In {
+		case "path":
+			raw, present = pathParams[param.Name]
+		case "query":
+			present = query.Has(param.Name)
+			raw = query.Get(param.Name)
+		case "header":
+			raw = req.Header.Get(param.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+		field := param.In + "." + param.Name
+		if !present {
+			if param.Required {
+				violations = append(violations, e.FieldViolation{Field: field, Message: "is required"})
+			}
+			continue
+		}
+		if param.Schema == nil {
+			continue
+		}
+		var value any
+		var err error
+		if param.In == "query" && param.Schema.Type == "array" && param.Schema.Items != nil {
+			value, err = coerceAll(param.Schema.Items, query[param.Name])
+		} else {
+			value, err = Coerce(param.Schema, raw)
+		}
+		if err != nil {
+			violations = append(violations, e.FieldViolation{Field: field, Message: err.Error()})
+			continue
+		}
+		violations = append(violations, s.validator.Validate(field, param.Schema, value)...)
+	}
+
+	if op.RequestBody == nil {
+		return violations, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if _, ok := op.RequestBody.Content[mediaType]; ok && !IsJSON(mediaType) {
+		// streamed bodies such as NDJSON are read by the handler, only their content type is checked
+		return violations, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return append(violations, s.validateBody(op.RequestBody, req.Header.Get("Content-Type"), body)...), nil
+}
+
+// coerceAll converts values of the repeated query parameter into array items
+func coerceAll(items *Schema, raw []string) (any, error) {
+	values := make([]any, 0, len(raw))
+	for _, item := range raw {
+		value, err := Coerce(items, item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (s *Spec) validateBody(rb *RequestBody, contentType string, body []byte) []e.FieldViolation {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if rb.Required {
+			return []e.FieldViolation{{Field: "body", Message: "is required"}}
+		}
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := rb.Content[mediaType]
+	if !ok {
+		return []e.FieldViolation{{Field: "body", Message: fmt.Sprintf("unsupported content type %q", contentType)}}
+	}
+	return s.validateJSON("body", mediaType, media, body)
+}
+
+// ValidateResponse validates response body against the schema declared for the status code
+func (s *Spec) ValidateResponse(op *Operation, status int, contentType string, body []byte) []e.FieldViolation {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return []e.FieldViolation{{Field: "response", Message: fmt.Sprintf("status %d is not declared", status)}}
+	}
+	resp = s.response(resp)
+	if len(resp.Content) == 0 || len(body) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := resp.Content[mediaType]
+	if !ok {
+		return []e.FieldViolation{{Field: "response", Message: fmt.Sprintf("content type %q is not declared", contentType)}}
+	}
+	return s.validateJSON("response", mediaType, media, body)
+}
+
+// IsJSON reports whether the media type is a single JSON document, application/x-ndjson is not
+func IsJSON(mediaType string) bool {
+	return strings.HasSuffix(mediaType, "/json") || strings.HasSuffix(mediaType, "+json")
+}
+
+func (s *Spec) validateJSON(field, mediaType string, media *MediaType, body []byte) []e.FieldViolation {
+	if !IsJSON(mediaType) || media.Schema == nil {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []e.FieldViolation{{Field: field, Message: "invalid json: " + err.Error()}}
+	}
+	return s.validator.Validate(field, media.Schema, value)
+}
+
+func (s *Spec) parameter(p *Parameter) *Parameter {
+	if p.Ref == "" {
+		return p
+	}
+	if param, ok := s.Components.Parameters[strings.TrimPrefix(p.Ref, parameterRefPrefix)]; ok {
+		return param
+	}
+	return p
+}
+
+func (s *Spec) response(r *Response) *Response {
+	if r.Ref == "" {
+		return r
+	}
+	if resp, ok := s.Components.Responses[strings.TrimPrefix(r.Ref, responseRefPrefix)]; ok {
+		return resp
+	}
+	return r
+}
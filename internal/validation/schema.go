@@ -0,0 +1,383 @@
+package validation
+
+import (
+	e "BannerFlow/internal/domain/errors"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the subset of JSON Schema shared by OpenAPI 3.0 and feature content schemas
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref"`
+	Type                 string             `json:"type,omitempty" yaml:"type"`
+	Format               string             `json:"format,omitempty" yaml:"format"`
+	Nullable             bool               `json:"nullable,omitempty" yaml:"nullable"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties"`
+	Required             []string           `json:"required,omitempty" yaml:"required"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty" yaml:"additionalProperties"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items"`
+	Enum                 []any              `json:"enum,omitempty" yaml:"enum"`
+	Minimum              *float64           `json:"minimum,omitempty" yaml:"minimum"`
+	Maximum              *float64           `json:"maximum,omitempty" yaml:"maximum"`
+	MinLength            *int               `json:"minLength,omitempty" yaml:"minLength"`
+	MaxLength            *int               `json:"maxLength,omitempty" yaml:"maxLength"`
+	Pattern              string             `json:"pattern,omitempty" yaml:"pattern"`
+	MinItems             *int               `json:"minItems,omitempty" yaml:"minItems"`
+	MaxItems             *int               `json:"maxItems,omitempty" yaml:"maxItems"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty" yaml:"definitions"`
+}
+
+// Additional is either a boolean or a schema for additional object properties
+type Additional struct {
+	Forbidden bool
+	Schema    *Schema
+}
+
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	var allowed bool
+	if node.Decode(&allowed) == nil {
+		a.Forbidden = !allowed
+		return nil
+	}
+	a.Schema = &Schema{}
+	return node.Decode(a.Schema)
+}
+
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if json.Unmarshal(data, &allowed) == nil {
+		a.Forbidden = !allowed
+		return nil
+	}
+	a.Schema = &Schema{}
+	return json.Unmarshal(data, a.Schema)
+}
+
+func (a *Additional) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(!a.Forbidden)
+}
+
+// Resolver returns schema referenced by $ref
+type Resolver func(ref string) (*Schema, bool)
+
+// Validator validates decoded json values against schemas
+type Validator struct {
+	resolve Resolver
+}
+
+func NewValidator(resolve Resolver) *Validator {
+	if resolve == nil {
+		resolve = func(string) (*Schema, bool) { return nil, false }
+	}
+	return &Validator{resolve: resolve}
+}
+
+// Validate returns all violations of value, path is used as a prefix of violation fields
+func (v *Validator) Validate(path string, schema *Schema, value any) []e.FieldViolation {
+	var violations []e.FieldViolation
+	v.validate(path, schema, value, &violations, 0)
+	return violations
+}
+
+const maxRefDepth = 32
+
+func (v *Validator) validate(path string, schema *Schema, value any, violations *[]e.FieldViolation, depth int) {
+	if schema == nil {
+		return
+	}
+	add := func(format string, args ...any) {
+		*violations = append(*violations, e.FieldViolation{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if schema.Ref != "" {
+		resolved, ok := v.resolve(schema.Ref)
+		if !ok || depth > maxRefDepth {
+			add("unresolved reference %s", schema.Ref)
+			return
+		}
+		v.validate(path, resolved, value, violations, depth+1)
+		return
+	}
+	if value == nil {
+		if schema.Type != "" && !schema.Nullable && schema.Type != "null" {
+			add("must not be null")
+		}
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		add("must be one of %v", schema.Enum)
+	}
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			add("must be an object")
+			return
+		}
+		v.validateObject(path, schema, obj, violations, depth)
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			add("must be an array")
+			return
+		}
+		if schema.MinItems != nil && len(arr) < *schema.MinItems {
+			add("must contain at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(arr) > *schema.MaxItems {
+			add("must contain at most %d items", *schema.MaxItems)
+		}
+		for i, item := range arr {
+			v.validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item, violations, depth)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			add("must be a string")
+			return
+		}
+		if schema.MinLength != nil && len([]rune(str)) < *schema.MinLength {
+			add("must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && len([]rune(str)) > *schema.MaxLength {
+			add("must be at most %d characters long", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err != nil || !re.MatchString(str) {
+				add("must match pattern %s", schema.Pattern)
+			}
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				add("must be a date-time")
+			}
+		}
+	case "integer", "number":
+		num, ok := value.(float64)
+		if !ok {
+			add("must be a %s", schema.Type)
+			return
+		}
+		if schema.Type == "integer" && num != math.Trunc(num) {
+			add("must be an integer")
+		}
+		if schema.Minimum != nil && num < *schema.Minimum {
+			add("must be greater than or equal to %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && num > *schema.Maximum {
+			add("must be less than or equal to %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			add("must be a boolean")
+		}
+	}
+}
+
+func (v *Validator) validateObject(path string, schema *Schema, obj map[string]any, violations *[]e.FieldViolation, depth int) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			*violations = append(*violations, e.FieldViolation{Field: join(path, name), Message: "is required"})
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if prop, ok := schema.Properties[k]; ok {
+			v.validate(join(path, k), prop, obj[k], violations, depth)
+			continue
+		}
+		switch {
+		case schema.AdditionalProperties == nil:
+		case schema.AdditionalProperties.Forbidden:
+			*violations = append(*violations, e.FieldViolation{Field: join(path, k), Message: "unknown field"})
+		case schema.AdditionalProperties.Schema != nil:
+			v.validate(join(path, k), schema.AdditionalProperties.Schema, obj[k], violations, depth)
+		}
+	}
+}
+
+// Coerce converts string parameter value to the type described by schema
+func Coerce(schema *Schema, raw string) (any, error) {
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return float64(n), nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	}
+	return raw, nil
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, candidate := range enum {
+		if reflect.DeepEqual(normalize(candidate), normalize(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize makes numbers decoded from yaml and json comparable
+func normalize(value any) any {
+	switch n := value.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	}
+	return value
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+var knownTypes = map[string]bool{
+	"": true, "object": true, "array": true, "string": true, "integer": true, "number": true, "boolean": true, "null": true,
+}
+
+const definitionsRefPrefix = "#/definitions/"
+
+// supportedKeywords are the keywords the validator applies, annotations without effect on validation
+// are accepted as well. Any other keyword would be silently ignored, so such schemas are rejected
+var supportedKeywords = map[string]bool{
+	"$ref": true, "type": true, "format": true, "nullable": true, "properties": true, "required": true,
+	"additionalProperties": true, "items": true, "enum": true, "minimum": true, "maximum": true,
+	"minLength": true, "maxLength": true, "pattern": true, "minItems": true, "maxItems": true, "definitions": true,
+	"$schema": true, "$comment": true, "title": true, "description": true, "default": true, "examples": true,
+}
+
+// ParseSchema decodes standalone JSON Schema and checks that it can be used for validation
+func ParseSchema(raw map[string]any) (*Schema, error) {
+	if err := checkKeywords("#", raw); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{}
+	if err = json.Unmarshal(b, schema); err != nil {
+		return nil, err
+	}
+	if err = schema.check("#", schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// NewSchemaValidator creates validator for standalone schema, references are resolved to its definitions
+func NewSchemaValidator(root *Schema) *Validator {
+	return NewValidator(func(ref string) (*Schema, bool) {
+		if ref == "#" {
+			return root, true
+		}
+		def, ok := root.Definitions[strings.TrimPrefix(ref, definitionsRefPrefix)]
+		return def, ok
+	})
+}
+
+func (s *Schema) check(path string, root *Schema) error {
+	if s == nil {
+		return nil
+	}
+	if !knownTypes[s.Type] {
+		return fmt.Errorf("%s: unsupported type %q", path, s.Type)
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+	}
+	if s.Ref != "" && s.Ref != "#" {
+		if _, ok := root.Definitions[strings.TrimPrefix(s.Ref, definitionsRefPrefix)]; !ok {
+			return fmt.Errorf("%s: unresolved reference %s", path, s.Ref)
+		}
+	}
+	for name, prop := range s.Properties {
+		if err := prop.check(path+"/properties/"+name, root); err != nil {
+			return err
+		}
+	}
+	for name, def := range s.Definitions {
+		if err := def.check(path+"/definitions/"+name, root); err != nil {
+			return err
+		}
+	}
+	if s.AdditionalProperties != nil {
+		if err := s.AdditionalProperties.Schema.check(path+"/additionalProperties", root); err != nil {
+			return err
+		}
+	}
+	return s.Items.check(path+"/items", root)
+}
+
+// checkKeywords rejects keywords of raw schema and its subschemas the validator does not support
+func checkKeywords(path string, raw map[string]any) error {
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !supportedKeywords[k] {
+			return fmt.Errorf("%s: unsupported keyword %q", path, k)
+		}
+		switch k {
+		case "items", "additionalProperties":
+			if sub, ok := raw[k].(map[string]any); ok {
+				if err := checkKeywords(path+"/"+k, sub); err != nil {
+					return err
+				}
+			}
+		case "properties", "definitions":
+			subs, _ := raw[k].(map[string]any)
+			names := make([]string, 0, len(subs))
+			for name := range subs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if sub, ok := subs[name].(map[string]any); ok {
+					if err := checkKeywords(path+"/"+k+"/"+name, sub); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
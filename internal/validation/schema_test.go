@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, raw string) map[string]any {
+	var value map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &value))
+	return value
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{name: "valid", schema: `{"type": "object", "properties": {"title": {"type": "string", "pattern": "^[A-Z]"}}}`},
+		{name: "definitions", schema: `{"type": "object", "properties": {"url": {"$ref": "#/definitions/url"}}, "definitions": {"url": {"type": "string"}}}`},
+		{name: "unknown type", schema: `{"type": "object", "properties": {"title": {"type": "text"}}}`, wantErr: true},
+		{name: "invalid pattern", schema: `{"type": "string", "pattern": "("}`, wantErr: true},
+		{name: "unresolved reference", schema: `{"type": "object", "properties": {"url": {"$ref": "#/definitions/url"}}}`, wantErr: true},
+		{name: "annotations", schema: `{"$schema": "http://json-schema.org/draft-07/schema#", "title": "Banner", "type": "object", "properties": {"title": {"type": "string", "description": "Title", "default": "Sale"}}}`},
+		{name: "oneOf", schema: `{"type": "object", "properties": {"size": {"oneOf": [{"type": "integer"}, {"type": "string"}]}}}`, wantErr: true},
+		{name: "const", schema: `{"type": "object", "properties": {"kind": {"type": "string", "const": "promo"}}}`, wantErr: true},
+		{name: "unsupported keyword in items", schema: `{"type": "array", "items": {"type": "number", "exclusiveMinimum": 0}}`, wantErr: true},
+		{name: "unsupported keyword in definitions", schema: `{"definitions": {"url": {"type": "string", "not": {"pattern": "^http:"}}}}`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseSchema(decode(t, test.schema))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSchemaValidator(t *testing.T) {
+	schema, err := ParseSchema(decode(t, `{
+		"type": "object",
+		"required": ["title", "url"],
+		"additionalProperties": false,
+		"properties": {
+			"title": {"type": "string", "maxLength": 5},
+			"url": {"$ref": "#/definitions/url"},
+			"priority": {"type": "integer", "minimum": 1}
+		},
+		"definitions": {"url": {"type": "string", "pattern": "^https://"}}
+	}`))
+	require.NoError(t, err)
+	validator := NewSchemaValidator(schema)
+
+	tests := []struct {
+		name       string
+		content    string
+		wantFields []string
+	}{
+		{name: "valid", content: `{"title": "sale", "url": "https://example.com", "priority": 2}`},
+		{name: "missing required", content: `{"title": "sale"}`, wantFields: []string{"content.url"}},
+		{
+			name:       "invalid values",
+			content:    `{"title": "big sale", "url": "http://example.com", "priority": 0.5, "color": "red"}`,
+			wantFields: []string{"content.title", "content.url", "content.priority", "content.priority", "content.color"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var fields []string
+			for _, violation := range validator.Validate("content", schema, decode(t, test.content)) {
+				fields = append(fields, violation.Field)
+			}
+			assert.ElementsMatch(t, test.wantFields, fields)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.tagIds IS DISTINCT FROM new.tagIds OR old.featureId IS DISTINCT FROM new.featureId THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    NEW.actor = COALESCE(current_setting('bannerflow.actor', true), '');
+    NEW.revision = OLD.revision + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- banners of the trash can't be told from live ones without deleted_at
+DELETE FROM banners WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS banners_deleted_at_idx;
+
+ALTER TABLE banners DROP COLUMN IF EXISTS deleted_at;
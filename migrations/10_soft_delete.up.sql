@@ -0,0 +1,29 @@
+-- deleted banners stay in the trash with their history until they are purged
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS banners_deleted_at_idx ON banners (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- deletion frees keys of the banner and restoration takes them back, neither is a version of the banner
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.deleted_at IS DISTINCT FROM new.deleted_at THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        IF new.deleted_at IS NULL THEN
+            CALL insert_into_feature_tag(NEW);
+        END IF;
+        NEW.revision = OLD.revision + 1;
+        RETURN NEW;
+    END IF;
+    IF old.tagIds IS DISTINCT FROM new.tagIds OR old.featureId IS DISTINCT FROM new.featureId THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    NEW.actor = COALESCE(current_setting('bannerflow.actor', true), '');
+    NEW.revision = OLD.revision + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS banner_reviews;
+
+-- drafts and archived banners stay hidden from users as deactivated ones
+INSERT INTO deactivated (bannerId) SELECT bannerId FROM unpublished ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS unpublished;
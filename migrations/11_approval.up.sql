@@ -0,0 +1,20 @@
+-- banners users don't get: drafts which were never published and archived banners
+CREATE TABLE IF NOT EXISTS unpublished (
+    bannerId  INT PRIMARY KEY REFERENCES banners (id) ON DELETE CASCADE
+);
+
+-- pending revision of the banner, it replaces the banner once it is approved and published
+CREATE TABLE IF NOT EXISTS banner_reviews (
+    bannerId  INT PRIMARY KEY REFERENCES banners (id) ON DELETE CASCADE,
+    state     TEXT NOT NULL CHECK (state IN ('draft', 'in_review', 'approved')),
+    featureId INT NOT NULL,
+    tagIds    INT[] NOT NULL,
+    content   JSONB NOT NULL,
+    is_active BOOLEAN NOT NULL,
+    author    TEXT NOT NULL DEFAULT '',
+    reviewer  TEXT NOT NULL DEFAULT '',
+    comment   TEXT NOT NULL DEFAULT '',
+    updated   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS banner_reviews_state_idx ON banner_reviews (state, updated);
@@ -0,0 +1,35 @@
+-- only the default tenant is kept, names and keys of other tenants would collide with its ones
+DELETE FROM banners WHERE tenant <> '';
+DELETE FROM features WHERE tenant <> '';
+DELETE FROM tags WHERE tenant <> '';
+DELETE FROM feature_schemas WHERE tenant <> '';
+
+CREATE OR REPLACE PROCEDURE insert_into_feature_tag(records banners)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    tag INT;
+BEGIN
+    FOREACH tag IN ARRAY records.tagIds
+        LOOP
+            INSERT INTO feature_tag (bannerId, tagId, featureId)
+            VALUES (records.id, tag, records.featureId);
+        END LOOP;
+END;
+$$;
+
+ALTER TABLE feature_tag DROP CONSTRAINT IF EXISTS feature_tag_pkey;
+ALTER TABLE feature_tag DROP COLUMN IF EXISTS tenant;
+ALTER TABLE feature_tag ADD PRIMARY KEY (tagId, featureId);
+ALTER TABLE feature_schemas DROP CONSTRAINT IF EXISTS feature_schemas_pkey;
+ALTER TABLE feature_schemas DROP COLUMN IF EXISTS tenant;
+ALTER TABLE feature_schemas ADD PRIMARY KEY (featureId);
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_tenant_name_key;
+ALTER TABLE tags DROP COLUMN IF EXISTS tenant;
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);
+ALTER TABLE features DROP CONSTRAINT IF EXISTS features_tenant_name_key;
+ALTER TABLE features DROP COLUMN IF EXISTS tenant;
+ALTER TABLE features ADD CONSTRAINT features_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS banners_tenant_idx;
+ALTER TABLE banners DROP COLUMN IF EXISTS tenant;
@@ -0,0 +1,32 @@
+-- tenant separates banners and registries of product lines, rows added before it belong to the default tenant ''
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE features ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE feature_schemas ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE feature_tag ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS banners_tenant_idx ON banners (tenant, id);
+
+-- names, schemas and keys of banners are unique within the tenant
+ALTER TABLE features DROP CONSTRAINT IF EXISTS features_name_key;
+ALTER TABLE features ADD CONSTRAINT features_tenant_name_key UNIQUE (tenant, name);
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+ALTER TABLE tags ADD CONSTRAINT tags_tenant_name_key UNIQUE (tenant, name);
+ALTER TABLE feature_schemas DROP CONSTRAINT IF EXISTS feature_schemas_pkey;
+ALTER TABLE feature_schemas ADD PRIMARY KEY (tenant, featureId);
+ALTER TABLE feature_tag DROP CONSTRAINT IF EXISTS feature_tag_pkey;
+ALTER TABLE feature_tag ADD PRIMARY KEY (tenant, tagId, featureId);
+
+CREATE OR REPLACE PROCEDURE insert_into_feature_tag(records banners)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    tag INT;
+BEGIN
+    FOREACH tag IN ARRAY records.tagIds
+        LOOP
+            INSERT INTO feature_tag (bannerId, tagId, featureId, tenant)
+            VALUES (records.id, tag, records.featureId, records.tenant);
+        END LOOP;
+END;
+$$;
@@ -0,0 +1,7 @@
+-- targeting of the banner, the banner with the highest priority among the ones matching the user is given to the user
+CREATE TABLE IF NOT EXISTS banner_targeting
+(
+    bannerId INT PRIMARY KEY REFERENCES banners (id) ON DELETE CASCADE,
+    priority INT   NOT NULL DEFAULT 0,
+    rule     JSONB NOT NULL
+);
@@ -0,0 +1,9 @@
+-- content of the banner in other locales, the content of the banner itself is the default one
+CREATE TABLE IF NOT EXISTS banner_translations
+(
+    bannerId INT       NOT NULL REFERENCES banners (id) ON DELETE CASCADE,
+    locale   TEXT      NOT NULL,
+    content  JSONB     NOT NULL,
+    updated  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bannerId, locale)
+);
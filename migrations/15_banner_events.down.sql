@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS trigger_translations_event ON banner_translations;
+DROP TRIGGER IF EXISTS trigger_targeting_event ON banner_targeting;
+DROP TRIGGER IF EXISTS trigger_unpublished_event ON unpublished;
+DROP TRIGGER IF EXISTS trigger_deactivated_event ON deactivated;
+DROP TRIGGER IF EXISTS trigger_banner_event ON banners;
+
+DROP FUNCTION IF EXISTS banner_ref_event_func;
+DROP FUNCTION IF EXISTS banner_event_func;
+DROP PROCEDURE IF EXISTS raise_banner_event;
+
+DROP TABLE IF EXISTS banner_events;
@@ -0,0 +1,85 @@
+-- changes of what users get: content, keys, activity, publication, targeting and translations of banners.
+-- Events are kept for a while, so streams resume after the last event their clients got
+CREATE TABLE IF NOT EXISTS banner_events
+(
+    id        BIGSERIAL PRIMARY KEY,
+    tenant    TEXT      NOT NULL,
+    bannerId  INT       NOT NULL,
+    featureId INT       NOT NULL,
+    tagIds    INT[]     NOT NULL,
+    created   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS banner_events_feature_idx ON banner_events (tenant, featureId, id);
+CREATE INDEX IF NOT EXISTS banner_events_created_idx ON banner_events (created);
+
+-- the notification carries the id only, listeners read events from the table
+CREATE OR REPLACE PROCEDURE raise_banner_event(tenant_name TEXT, banner INT, feature INT, tags INT[])
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    event BIGINT;
+BEGIN
+    INSERT INTO banner_events (tenant, bannerId, featureId, tagIds)
+    VALUES (tenant_name, banner, feature, COALESCE(tags, '{}'))
+    RETURNING id INTO event;
+    PERFORM pg_notify('banner_events', event::TEXT);
+END;
+$$;
+
+-- users of the old keys are notified too, they lose the banner
+CREATE OR REPLACE FUNCTION banner_event_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        CALL raise_banner_event(NEW.tenant, NEW.id, NEW.featureId, NEW.tagIds);
+    ELSIF OLD.featureId IS DISTINCT FROM NEW.featureId THEN
+        CALL raise_banner_event(OLD.tenant, OLD.id, OLD.featureId, OLD.tagIds);
+        CALL raise_banner_event(NEW.tenant, NEW.id, NEW.featureId, NEW.tagIds);
+    ELSIF OLD.content IS DISTINCT FROM NEW.content OR OLD.tagIds IS DISTINCT FROM NEW.tagIds
+        OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at THEN
+        CALL raise_banner_event(NEW.tenant, NEW.id, NEW.featureId, ARRAY(SELECT DISTINCT t FROM unnest(OLD.tagIds || NEW.tagIds) t ORDER BY t));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_banner_event
+    AFTER INSERT OR UPDATE ON banners
+    FOR EACH ROW EXECUTE FUNCTION banner_event_func();
+
+-- rows of tables referring to the banner change what users get as well, purged banners are not reported
+CREATE OR REPLACE FUNCTION banner_ref_event_func()
+    RETURNS TRIGGER AS
+$$
+DECLARE
+    banner banners;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        SELECT * INTO banner FROM banners WHERE id = OLD.bannerId;
+    ELSE
+        SELECT * INTO banner FROM banners WHERE id = NEW.bannerId;
+    END IF;
+    IF FOUND THEN
+        CALL raise_banner_event(banner.tenant, banner.id, banner.featureId, banner.tagIds);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_deactivated_event
+    AFTER INSERT OR DELETE ON deactivated
+    FOR EACH ROW EXECUTE FUNCTION banner_ref_event_func();
+
+CREATE TRIGGER trigger_unpublished_event
+    AFTER INSERT OR DELETE ON unpublished
+    FOR EACH ROW EXECUTE FUNCTION banner_ref_event_func();
+
+CREATE TRIGGER trigger_targeting_event
+    AFTER INSERT OR UPDATE OR DELETE ON banner_targeting
+    FOR EACH ROW EXECUTE FUNCTION banner_ref_event_func();
+
+CREATE TRIGGER trigger_translations_event
+    AFTER INSERT OR UPDATE OR DELETE ON banner_translations
+    FOR EACH ROW EXECUTE FUNCTION banner_ref_event_func();
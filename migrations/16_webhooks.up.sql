@@ -0,0 +1,36 @@
+-- endpoints of the tenant notified about banner events listed in events
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id        SERIAL PRIMARY KEY,
+    tenant    TEXT      NOT NULL DEFAULT '',
+    url       TEXT      NOT NULL,
+    events    TEXT[]    NOT NULL,
+    secret    TEXT      NOT NULL,
+    is_active BOOLEAN   NOT NULL DEFAULT TRUE,
+    created   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_tenant_idx ON webhooks (tenant);
+
+-- deliveries of banner events to webhooks, they are written in the transaction of the change and sent afterwards.
+-- Delivered ones are kept as the delivery log, the ones out of attempts are dead letters
+CREATE TABLE IF NOT EXISTS webhook_outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    webhookId       INT       NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event           TEXT      NOT NULL,
+    bannerId        INT       NOT NULL,
+    payload         JSONB     NOT NULL,
+    status          TEXT      NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts        INT       NOT NULL DEFAULT 0,
+    response_status INT       NOT NULL DEFAULT 0,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    next_attempt    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered       TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_outbox_due_idx ON webhook_outbox (next_attempt) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_outbox_webhook_idx ON webhook_outbox (webhookId, id);
+CREATE INDEX IF NOT EXISTS webhook_outbox_status_idx ON webhook_outbox (status, id);
@@ -0,0 +1,14 @@
+-- an id registered by several tenants is kept by the first of them only
+DELETE FROM features f USING features o WHERE f.id = o.id AND f.tenant > o.tenant;
+CREATE SEQUENCE IF NOT EXISTS features_id_seq OWNED BY features.id;
+SELECT setval('features_id_seq', COALESCE(max(id), 0) + 1, false) FROM features;
+ALTER TABLE features ALTER COLUMN id SET DEFAULT nextval('features_id_seq');
+ALTER TABLE features DROP CONSTRAINT IF EXISTS features_pkey;
+ALTER TABLE features ADD PRIMARY KEY (id);
+
+DELETE FROM tags t USING tags o WHERE t.id = o.id AND t.tenant > o.tenant;
+CREATE SEQUENCE IF NOT EXISTS tags_id_seq OWNED BY tags.id;
+SELECT setval('tags_id_seq', COALESCE(max(id), 0) + 1, false) FROM tags;
+ALTER TABLE tags ALTER COLUMN id SET DEFAULT nextval('tags_id_seq');
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_pkey;
+ALTER TABLE tags ADD PRIMARY KEY (id);
@@ -0,0 +1,11 @@
+-- ids of features and tags are unique within the tenant like their names, so an id registered by another tenant
+-- is as free as an unused one. Ids are generated per tenant by the insert queries instead of the shared sequences
+ALTER TABLE features DROP CONSTRAINT IF EXISTS features_pkey;
+ALTER TABLE features ADD PRIMARY KEY (tenant, id);
+ALTER TABLE features ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS features_id_seq;
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_pkey;
+ALTER TABLE tags ADD PRIMARY KEY (tenant, id);
+ALTER TABLE tags ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS tags_id_seq;
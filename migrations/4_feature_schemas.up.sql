@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS feature_schemas
+(
+    featureId INT PRIMARY KEY,
+    schema    JSONB NOT NULL,
+    created   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS features
+(
+    id          SERIAL PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    owner       TEXT NOT NULL DEFAULT '',
+    created     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tags
+(
+    id          SERIAL PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    owner       TEXT NOT NULL DEFAULT '',
+    created     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ids already used by banners are registered with generated names
+INSERT INTO features (id, name)
+SELECT DISTINCT featureId, 'feature_' || featureId FROM banners WHERE featureId IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO tags (id, name)
+SELECT DISTINCT tag, 'tag_' || tag FROM banners, unnest(tagIds) tag
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('features', 'id'), COALESCE(max(id), 0) + 1, false) FROM features;
+SELECT setval(pg_get_serial_sequence('tags', 'id'), COALESCE(max(id), 0) + 1, false) FROM tags;
@@ -0,0 +1,52 @@
+CREATE OR REPLACE PROCEDURE choose_banner_from_history (bid INT, vn INT)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    history banner_history;
+BEGIN
+    SELECT * INTO history FROM banner_history WHERE bannerid = bid AND version = vn;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No history for ID % and version %.', bid, vn;
+    END IF;
+    DELETE FROM banner_history WHERE bannerid = bid AND version = vn;
+    UPDATE banners SET featureid = history.featureid, tagids = history.tagids, updated = current_timestamp, content = history.content
+    WHERE id = bid;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.tagIds IS NOT DISTINCT FROM new.tagIds THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE PROCEDURE save_banner_to_history (records banners)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    vs INT;
+BEGIN
+    vs := (SELECT COALESCE(max(version), 0) FROM banner_history WHERE bannerId = records.id) + 1;
+    INSERT INTO banner_history (bannerId, content, version, tagIds, featureId)
+    VALUES (records.id, records.content, vs, records.tagids, records.featureid);
+    DELETE FROM banner_history bh WHERE bh.bannerId = records.id AND bh.version
+    NOT IN (SELECT bh2.version FROM banner_history bh2 WHERE bh2.bannerId = records.id
+            ORDER BY bh2.version DESC LIMIT 3);
+END;
+$$;
+
+DROP INDEX IF EXISTS banner_history_version;
+
+ALTER TABLE banner_history DROP COLUMN IF EXISTS is_active;
+ALTER TABLE banner_history DROP COLUMN IF EXISTS created;
+ALTER TABLE banner_history DROP COLUMN IF EXISTS actor;
+
+ALTER TABLE banners DROP COLUMN IF EXISTS actor;
@@ -0,0 +1,53 @@
+-- actor of the last change is taken from the transaction setting written by the service
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT COALESCE(current_setting('bannerflow.actor', true), '');
+
+ALTER TABLE banner_history ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT '';
+ALTER TABLE banner_history ADD COLUMN IF NOT EXISTS created TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE banner_history ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT true;
+
+CREATE UNIQUE INDEX IF NOT EXISTS banner_history_version ON banner_history (bannerId, version);
+
+-- every version is kept, retention is applied by the service
+CREATE OR REPLACE PROCEDURE save_banner_to_history (records banners)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    vs INT;
+BEGIN
+    vs := (SELECT COALESCE(max(version), 0) FROM banner_history WHERE bannerId = records.id) + 1;
+    INSERT INTO banner_history (bannerId, content, version, tagIds, featureId, actor, created, is_active)
+    VALUES (records.id, records.content, vs, records.tagids, records.featureid, records.actor, records.updated,
+            NOT EXISTS (SELECT 1 FROM deactivated d WHERE d.bannerId = records.id));
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.tagIds IS NOT DISTINCT FROM new.tagIds THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    NEW.actor = COALESCE(current_setting('bannerflow.actor', true), '');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- restored version is kept, current state is saved as a new version by the update trigger
+CREATE OR REPLACE PROCEDURE choose_banner_from_history (bid INT, vn INT)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    history banner_history;
+BEGIN
+    SELECT * INTO history FROM banner_history WHERE bannerid = bid AND version = vn;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No history for ID % and version %.', bid, vn;
+    END IF;
+    UPDATE banners SET featureid = history.featureid, tagids = history.tagids, updated = current_timestamp, content = history.content
+    WHERE id = bid;
+END;
+$$;
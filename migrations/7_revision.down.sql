@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.tagIds IS NOT DISTINCT FROM new.tagIds THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    NEW.actor = COALESCE(current_setting('bannerflow.actor', true), '');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE banners DROP COLUMN IF EXISTS revision;
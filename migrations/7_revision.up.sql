@@ -0,0 +1,18 @@
+-- revision is a part of the entity tag, it grows with every change of the banner row
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.tagIds IS NOT DISTINCT FROM new.tagIds THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    NEW.actor = COALESCE(current_setting('bannerflow.actor', true), '');
+    NEW.revision = OLD.revision + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,23 @@
+-- feature_tag is rebuilt whenever tags or feature of the banner change, so the banner is found by its new keys
+CREATE OR REPLACE FUNCTION update_banner_func()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF old.tagIds IS DISTINCT FROM new.tagIds OR old.featureId IS DISTINCT FROM new.featureId THEN
+        DELETE FROM feature_tag WHERE bannerid = old.id;
+        CALL insert_into_feature_tag(NEW);
+    END IF;
+    CALL save_banner_to_history(OLD);
+    NEW.updated = current_timestamp;
+    NEW.actor = COALESCE(current_setting('bannerflow.actor', true), '');
+    NEW.revision = OLD.revision + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- keys left stale by the previous trigger are restored, the oldest banner keeps a contested key
+DELETE FROM feature_tag;
+INSERT INTO feature_tag (bannerId, tagId, featureId)
+SELECT b.id, t.tagId, b.featureId FROM banners b, unnest(b.tagIds) t(tagId)
+ORDER BY b.id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS banners_search_idx;
+
+ALTER TABLE banners DROP COLUMN IF EXISTS search;
@@ -0,0 +1,5 @@
+-- search holds words of every string value of the content. Banners mix languages, so words are matched without stemming
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS search TSVECTOR
+    GENERATED ALWAYS AS (jsonb_to_tsvector('simple', content, '["string"]')) STORED;
+
+CREATE INDEX IF NOT EXISTS banners_search_idx ON banners USING GIN (search);
@@ -0,0 +1,10 @@
+package api
+
+import _ "embed"
+
+//go:generate go run ../../cmd/apigen -spec api.yaml -models models.gen.go -server ../../internal/handlers/server.gen.go
+
+// Spec is the OpenAPI specification of the service, the source of truth for models and routes
+//
+//go:embed api.yaml
+var Spec []byte
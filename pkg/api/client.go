@@ -0,0 +1,643 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TokenHeader  = "token"
+	APIKeyHeader = "X-API-Key"
+)
+
+const (
+	jsonContentType       = "application/json"
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// RetryPolicy describes how requests failed with 5xx or network errors are retried.
+// Backoff grows exponentially from MinBackoff up to MaxBackoff
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 2 * time.Second,
+}
+
+// Client is a typed http client for BannerFlow api
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	apiKey     string
+	retry      RetryPolicy
+	cache      *ttlCache
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient sets custom http client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets token, which is sent in the token header
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithAPIKey sets api key, which is sent in the X-API-Key header
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithRetryPolicy overrides DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithCache enables client side cache of user banners
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = newTTLCache(ttl)
+	}
+}
+
+// RequestOption changes a single request of the methods accepting it
+type RequestOption func(req *http.Request)
+
+// WithIfMatch sends If-Match, so the banner is changed only while its ETag matches, see BannerResponse.Etag.
+// ErrPreconditionFailed is returned otherwise
+func WithIfMatch(etag string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("If-Match", etag)
+	}
+}
+
+// NewClient creates new client for the service located at baseURL
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetToken requests a new user or admin token
+func (c *Client) GetToken(ctx context.Context, admin bool) (string, error) {
+	path := "/get_token/"
+	if admin {
+		path += "admin"
+	}
+	resp := TokenResponse{}
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+// GetUserBanner returns content of the banner matching tags and attributes of the user. Cached content is returned
+// unless UseLastRevision is set
+func (c *Client) GetUserBanner(ctx context.Context, params *UserBannerParams) (map[string]interface{}, error) {
+	query := url.Values{}
+	setInt(query, "tag_id", params.TagId)
+	setInt(query, "feature_id", params.FeatureId)
+	setInts(query, "tag_ids", params.TagIds)
+	setString(query, "locale", params.Locale)
+	setString(query, "platform", params.Platform)
+	setString(query, "country", params.Country)
+	setString(query, "app_version", params.AppVersion)
+	useLastRevision := params.UseLastRevision != nil && *params.UseLastRevision
+	if useLastRevision {
+		query.Set("use_last_revision", "true")
+	}
+	key := query.Encode()
+	if c.cache != nil && !useLastRevision {
+		if content, ok := c.cache.get(key); ok {
+			return content, nil
+		}
+	}
+	var content map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/user_banner", query, nil, &content); err != nil {
+		return nil, err
+	}
+	if c.cache != nil {
+		c.cache.put(key, content)
+	}
+	return content, nil
+}
+
+// GetUserBanners returns banners of several features for the same user at once. Every requested feature
+// is in Banners of the response, Status of the features without a banner for the user is 404
+func (c *Client) GetUserBanners(ctx context.Context, req *UserBannersRequest) (*UserBannersResponse, error) {
+	banners := &UserBannersResponse{}
+	if err := c.do(ctx, http.MethodPost, "/user_banners", nil, req, banners); err != nil {
+		return nil, err
+	}
+	return banners, nil
+}
+
+// ListBanners returns a page of banners filtered by feature and/or tag, given by id or registered name,
+// and by the rest of params. NextCursor of the response is passed as Cursor to get the next page
+func (c *Client) ListBanners(ctx context.Context, params *ListBannerParams) (*BannerListResponse, error) {
+	query := url.Values{}
+	setInt(query, "feature_id", params.FeatureId)
+	setInt(query, "tag_id", params.TagId)
+	setString(query, "feature_name", params.FeatureName)
+	setString(query, "tag_name", params.TagName)
+	setInt(query, "limit", params.Limit)
+	setInt(query, "offset", params.Offset)
+	setString(query, "cursor", params.Cursor)
+	setInts(query, "tag_ids", params.TagIds)
+	setBool(query, "is_active", params.IsActive)
+	setTime(query, "created_after", params.CreatedAfter)
+	setTime(query, "created_before", params.CreatedBefore)
+	setTime(query, "updated_after", params.UpdatedAfter)
+	setTime(query, "updated_before", params.UpdatedBefore)
+	setString(query, "sort", params.Sort)
+	setBool(query, "include_total", params.IncludeTotal)
+	resp := &BannerListResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner", query, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateBanner creates new banner and returns its id
+func (c *Client) CreateBanner(ctx context.Context, req *BannerRequest) (int, error) {
+	resp := BannerIdResponse{}
+	if err := c.do(ctx, http.MethodPost, "/banner", nil, req, &resp); err != nil {
+		return 0, err
+	}
+	if resp.BannerId == nil {
+		return 0, fmt.Errorf("%w: no banner id in response", ErrUnexpected)
+	}
+	return *resp.BannerId, nil
+}
+
+// GetBanner returns the banner by id, its Etag is passed to WithIfMatch to change the banner conditionally
+func (c *Client) GetBanner(ctx context.Context, id int) (*BannerResponse, error) {
+	banner := &BannerResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/"+strconv.Itoa(id), nil, nil, banner); err != nil {
+		return nil, err
+	}
+	return banner, nil
+}
+
+// UpdateBanner updates non nil fields of the banner
+func (c *Client) UpdateBanner(ctx context.Context, id int, req *BannerUpdateRequest, opts ...RequestOption) error {
+	return c.do(ctx, http.MethodPatch, "/banner/"+strconv.Itoa(id), nil, req, nil, opts...)
+}
+
+// MergePatchBanner applies JSON Merge Patch (RFC 7396) to the content of the banner
+func (c *Client) MergePatchBanner(ctx context.Context, id int, patch Content, opts ...RequestOption) error {
+	return c.doAs(ctx, http.MethodPatch, "/banner/"+strconv.Itoa(id), nil, mergePatchContentType, patch, nil, opts...)
+}
+
+// JSONPatchBanner applies JSON Patch (RFC 6902) to the content of the banner, paths are relative to the content
+func (c *Client) JSONPatchBanner(ctx context.Context, id int, operations []JsonPatchOperation, opts ...RequestOption) error {
+	return c.doAs(ctx, http.MethodPatch, "/banner/"+strconv.Itoa(id), nil, jsonPatchContentType, operations, nil, opts...)
+}
+
+// DeleteBanner moves the banner to the trash, see RestoreBanner
+func (c *Client) DeleteBanner(ctx context.Context, id int, opts ...RequestOption) error {
+	return c.do(ctx, http.MethodDelete, "/banner/"+strconv.Itoa(id), nil, nil, nil, opts...)
+}
+
+// DiffBannerVersions returns changes of the banner between the versions, nil To compares with the current banner
+func (c *Client) DiffBannerVersions(ctx context.Context, id int, params *DiffParams) (*BannerDiffResponse, error) {
+	query := url.Values{}
+	query.Set("from", strconv.Itoa(params.From))
+	setInt(query, "to", params.To)
+	diff := &BannerDiffResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/"+strconv.Itoa(id)+"/diff", query, nil, diff); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// SearchBanners returns a page of banners whose content matches the words and conditions of params.
+// NextCursor of the response is passed as Cursor to get the next page
+func (c *Client) SearchBanners(ctx context.Context, params *SearchBannersParams) (*BannerSearchResponse, error) {
+	query := url.Values{}
+	setString(query, "q", params.Q)
+	if params.Where != nil {
+		for _, where := range *params.Where {
+			query.Add("where", where)
+		}
+	}
+	setInt(query, "feature_id", params.FeatureId)
+	setInt(query, "tag_id", params.TagId)
+	setBool(query, "is_active", params.IsActive)
+	setInt(query, "limit", params.Limit)
+	setString(query, "cursor", params.Cursor)
+	setBool(query, "include_total", params.IncludeTotal)
+	resp := &BannerSearchResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/search", query, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchBanners creates, updates and deletes banners at once. Results are in the order of the operations,
+// failed operations have the status of the error
+func (c *Client) BatchBanners(ctx context.Context, req *BatchRequest) (*BatchResponse, error) {
+	resp := &BatchResponse{}
+	if err := c.do(ctx, http.MethodPost, "/banner/batch", nil, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListTrash returns a page of deleted banners, which can still be restored, params may be nil to get the first page
+func (c *Client) ListTrash(ctx context.Context, params *ListTrashParams) (*BannerListResponse, error) {
+	query := url.Values{}
+	if params != nil {
+		setInt(query, "feature_id", params.FeatureId)
+		setInt(query, "tag_id", params.TagId)
+		setInt(query, "limit", params.Limit)
+		setString(query, "cursor", params.Cursor)
+	}
+	resp := &BannerListResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/trash", query, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RestoreBanner returns the deleted banner from the trash. Banners are purged once the trash retention passes,
+// ErrNotFound is returned then
+func (c *Client) RestoreBanner(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPost, "/banner/"+strconv.Itoa(id)+"/restore", nil, nil, nil)
+}
+
+// ListBannerVersions returns previous versions of the banner, params may be nil to get every version
+func (c *Client) ListBannerVersions(ctx context.Context, id int, params *HistoryParams) ([]BannerVersionResponse, error) {
+	query := url.Values{}
+	if params != nil {
+		setInt(query, "limit", params.Limit)
+		setInt(query, "offset", params.Offset)
+	}
+	var versions []BannerVersionResponse
+	if err := c.do(ctx, http.MethodGet, "/banner/versions/"+strconv.Itoa(id), query, nil, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// ActivateBannerVersion restores previous version of the banner
+func (c *Client) ActivateBannerVersion(ctx context.Context, id, version int, opts ...RequestOption) error {
+	query := url.Values{}
+	query.Set("version", strconv.Itoa(version))
+	return c.do(ctx, http.MethodPut, "/banner/versions/"+strconv.Itoa(id)+"/activate", query, nil, nil, opts...)
+}
+
+// GetTenantQuota returns the number of banners of the tenant of the token or api key and its quota
+func (c *Client) GetTenantQuota(ctx context.Context) (*TenantQuotaResponse, error) {
+	quota := &TenantQuotaResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/quota", nil, nil, quota); err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+// GetBannerTargeting returns the targeting of the banner
+func (c *Client) GetBannerTargeting(ctx context.Context, id int) (*TargetingResponse, error) {
+	targeting := &TargetingResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/"+strconv.Itoa(id)+"/targeting", nil, nil, targeting); err != nil {
+		return nil, err
+	}
+	return targeting, nil
+}
+
+// PutBannerTargeting sets or replaces the targeting of the banner
+func (c *Client) PutBannerTargeting(ctx context.Context, id int, req *TargetingRequest) error {
+	return c.do(ctx, http.MethodPut, "/banner/"+strconv.Itoa(id)+"/targeting", nil, req, nil)
+}
+
+// DeleteBannerTargeting removes the targeting, so the banner matches every user
+func (c *Client) DeleteBannerTargeting(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/banner/"+strconv.Itoa(id)+"/targeting", nil, nil, nil)
+}
+
+// EvaluateBanner explains which banner the user described by req would get
+func (c *Client) EvaluateBanner(ctx context.Context, req *EvaluateRequest) (*EvaluateResponse, error) {
+	evaluation := &EvaluateResponse{}
+	if err := c.do(ctx, http.MethodPost, "/banner/evaluate", nil, req, evaluation); err != nil {
+		return nil, err
+	}
+	return evaluation, nil
+}
+
+// ListBannerTranslations returns translations of the content of the banner ordered by locale
+func (c *Client) ListBannerTranslations(ctx context.Context, id int) ([]TranslationResponse, error) {
+	var translations []TranslationResponse
+	if err := c.do(ctx, http.MethodGet, "/banner/"+strconv.Itoa(id)+"/translations", nil, nil, &translations); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// PutBannerTranslation sets or replaces the content of the banner in the locale
+func (c *Client) PutBannerTranslation(ctx context.Context, id int, locale string, content Content) error {
+	return c.do(ctx, http.MethodPut, "/banner/"+strconv.Itoa(id)+"/translations/"+url.PathEscape(locale), nil, content, nil)
+}
+
+// DeleteBannerTranslation removes the translation, so users of the locale get the closest remaining one
+func (c *Client) DeleteBannerTranslation(ctx context.Context, id int, locale string) error {
+	return c.do(ctx, http.MethodDelete, "/banner/"+strconv.Itoa(id)+"/translations/"+url.PathEscape(locale), nil, nil, nil)
+}
+
+// ListMissingTranslations returns banners lacking translations to any of the locales, featureId may be nil
+// to check banners of every feature
+func (c *Client) ListMissingTranslations(ctx context.Context, locales []string, featureId *int) ([]MissingTranslationsResponse, error) {
+	query := url.Values{}
+	for _, locale := range locales {
+		query.Add("locales", locale)
+	}
+	setInt(query, "feature_id", featureId)
+	var missing []MissingTranslationsResponse
+	if err := c.do(ctx, http.MethodGet, "/banner/translations/missing", query, nil, &missing); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// SubmitBanner sends the draft of the banner to review
+func (c *Client) SubmitBanner(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPost, "/banner/"+strconv.Itoa(id)+"/submit", nil, nil, nil)
+}
+
+// ApproveBanner approves changes of the banner, it requires a reviewer token of another user than the author
+func (c *Client) ApproveBanner(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPost, "/banner/"+strconv.Itoa(id)+"/approve", nil, nil, nil)
+}
+
+// RejectBanner returns changes of the banner to the author with the comment
+func (c *Client) RejectBanner(ctx context.Context, id int, comment string) error {
+	return c.do(ctx, http.MethodPost, "/banner/"+strconv.Itoa(id)+"/reject", nil, &ReviewRejectRequest{Comment: &comment}, nil)
+}
+
+// PublishBanner replaces the banner with its approved changes
+func (c *Client) PublishBanner(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPost, "/banner/"+strconv.Itoa(id)+"/publish", nil, nil, nil)
+}
+
+// ArchiveBanner stops giving the published banner to users, changing it afterwards creates a new draft
+func (c *Client) ArchiveBanner(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPost, "/banner/"+strconv.Itoa(id)+"/archive", nil, nil, nil)
+}
+
+// GetBannerRevision returns the changes of the banner pending review or the banner itself when there are none
+func (c *Client) GetBannerRevision(ctx context.Context, id int) (*BannerRevisionResponse, error) {
+	revision := &BannerRevisionResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/"+strconv.Itoa(id)+"/revision", nil, nil, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// ListReviews returns pending changes of banners, params may be nil to get the ones waiting for review
+func (c *Client) ListReviews(ctx context.Context, params *ListReviewsParams) ([]BannerRevisionResponse, error) {
+	query := url.Values{}
+	if params != nil {
+		setString(query, "state", params.State)
+		setInt(query, "limit", params.Limit)
+		setInt(query, "offset", params.Offset)
+	}
+	var revisions []BannerRevisionResponse
+	if err := c.do(ctx, http.MethodGet, "/banner/reviews", query, nil, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// DeleteBanners schedules deletion of banners matching all the params and returns the job. Deleting many banners
+// requires ConfirmToken returned by PreviewDeleteBanners, DryRun of params is ignored
+func (c *Client) DeleteBanners(ctx context.Context, params *DeleteBannerParams) (*DeleteJobResponse, error) {
+	query := deleteBannersQuery(params)
+	job := &DeleteJobResponse{}
+	if err := c.do(ctx, http.MethodDelete, "/banner/banners", query, nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetDeleteJob returns the job scheduled by DeleteBanners, its RemovedIds are known once it is done
+func (c *Client) GetDeleteJob(ctx context.Context, id int) (*DeleteJobResponse, error) {
+	job := &DeleteJobResponse{}
+	if err := c.do(ctx, http.MethodGet, "/banner/banners/jobs/"+strconv.Itoa(id), nil, nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// PreviewDeleteBanners returns banners DeleteBanners would delete with the same params and the token confirming it
+func (c *Client) PreviewDeleteBanners(ctx context.Context, params *DeleteBannerParams) (*DeletePreviewResponse, error) {
+	query := deleteBannersQuery(params)
+	query.Set("dry_run", "true")
+	query.Del("confirm_token")
+	preview := &DeletePreviewResponse{}
+	if err := c.do(ctx, http.MethodDelete, "/banner/banners", query, nil, preview); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+func deleteBannersQuery(params *DeleteBannerParams) url.Values {
+	query := url.Values{}
+	setInt(query, "feature_id", params.FeatureId)
+	setInts(query, "tag_ids", params.TagIds)
+	setBool(query, "inactive_only", params.InactiveOnly)
+	setTime(query, "updated_before", params.UpdatedBefore)
+	setString(query, "confirm_token", params.ConfirmToken)
+	return query
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any, opts ...RequestOption) error {
+	return c.doAs(ctx, method, path, query, jsonContentType, body, out, opts...)
+}
+
+// doAs sends body encoded to JSON with the content type, which is a JSON based one
+func (c *Client) doAs(ctx context.Context, method, path string, query url.Values, contentType string, body, out any, opts ...RequestOption) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	return c.doRaw(ctx, method, path, query, contentType, payload, out, opts...)
+}
+
+// doRaw sends payload as is and retries the request according to the retry policy
+func (c *Client) doRaw(ctx context.Context, method, path string, query url.Values, contentType string, payload []byte, out any, opts ...RequestOption) error {
+	target := c.target(path, query)
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return err
+			}
+		}
+		lastErr = c.send(ctx, method, target, contentType, payload, out, opts...)
+		if !c.shouldRetry(method, lastErr) || attempt >= c.retry.MaxRetries {
+			return lastErr
+		}
+	}
+}
+
+func (c *Client) target(path string, query url.Values) string {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target
+}
+
+// send decodes JSON response to out, out being io.Writer gets the response body as is
+func (c *Client) send(ctx context.Context, method, target, contentType string, payload []byte, out any, opts ...RequestOption) error {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	c.authorize(req)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err = responseError(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if w, ok := out.(io.Writer); ok {
+		_, err = io.Copy(w, resp.Body)
+		return err
+	}
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnexpected, err)
+	}
+	return nil
+}
+
+func (c *Client) authorize(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set(TokenHeader, c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(APIKeyHeader, c.apiKey)
+	}
+}
+
+// responseError returns Error for non-2xx responses
+func responseError(resp *http.Response) error {
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	errResp := &BannerErrorResponse{}
+	if json.NewDecoder(resp.Body).Decode(errResp) != nil {
+		errResp = nil
+	}
+	return newError(resp.StatusCode, errResp)
+}
+
+// shouldRetry allows retries of idempotent requests only
+func (c *Client) shouldRetry(method string, err error) bool {
+	if err == nil || method == http.MethodPost {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	backoff := c.retry.MinBackoff << (attempt - 1)
+	if backoff > c.retry.MaxBackoff || backoff <= 0 {
+		backoff = c.retry.MaxBackoff
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func setInt(query url.Values, key string, value *int) {
+	if value != nil {
+		query.Set(key, strconv.Itoa(*value))
+	}
+}
+
+func setInts(query url.Values, key string, values *[]int) {
+	if values != nil {
+		for _, value := range *values {
+			query.Add(key, strconv.Itoa(value))
+		}
+	}
+}
+
+func setString(query url.Values, key string, value *string) {
+	if value != nil {
+		query.Set(key, *value)
+	}
+}
+
+func setBool(query url.Values, key string, value *bool) {
+	if value != nil {
+		query.Set(key, strconv.FormatBool(*value))
+	}
+}
+
+func setTime(query url.Values, key string, value *time.Time) {
+	if value != nil {
+		query.Set(key, value.Format(time.RFC3339Nano))
+	}
+}
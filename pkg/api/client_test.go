@@ -0,0 +1,605 @@
+package api_test
+
+import (
+	"BannerFlow/internal/auth"
+	e "BannerFlow/internal/domain/errors"
+	"BannerFlow/internal/domain/models"
+	"BannerFlow/internal/handlers"
+	"BannerFlow/pkg/api"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService implements handlers.Service. Methods that are not overridden panic
+type fakeService struct {
+	handlers.Service
+	userCalls   atomic.Int32
+	listCalls   atomic.Int32
+	createCalls atomic.Int32
+	failures    int32
+	deleted     *models.BulkDeleteOptions
+	webhook     *models.Webhook
+	deliveries  *models.DeliveryListOptions
+	ifMatch     string
+	patched     *models.ContentPatch
+	searched    *models.BannerSearchOptions
+	imported    []models.ImportRecord
+	importMode  *models.ImportOptions
+	schema      *models.FeatureSchema
+	registered  map[models.Registry]*models.RegistryEntry
+}
+
+func (f *fakeService) UserGetBanners(_ context.Context, options *models.BannerUserOptions) (*models.UserBanner, error) {
+	f.userCalls.Add(1)
+	if options.FeatureId != 1 || options.TagId != 1 {
+		return nil, e.ErrorNotFound
+	}
+	return &models.UserBanner{Content: map[string]any{"title": "some_title"}}, nil
+}
+
+func (f *fakeService) UserGetFeatureBanners(_ context.Context, options *models.BannersUserOptions) (map[int]*models.UserBanner, error) {
+	banners := make(map[int]*models.UserBanner, len(options.FeatureIds))
+	for _, featureId := range options.FeatureIds {
+		banners[featureId] = nil
+		if featureId == 1 && options.Attributes.Locale == "ru" {
+			banners[featureId] = &models.UserBanner{Content: map[string]any{"title": "some_title"}}
+		}
+	}
+	return banners, nil
+}
+
+// WatchUserBanner ends the new stream after the first change, the resumed one goes on after the last event
+func (f *fakeService) WatchUserBanner(_ context.Context, options *models.BannerWatchOptions) (<-chan models.BannerChange, error) {
+	changes := make(chan models.BannerChange, 1)
+	if options.LastEventId == 0 {
+		changes <- models.BannerChange{EventId: 1, Banner: &models.UserBanner{Content: map[string]any{"title": "some_title"}}}
+		close(changes)
+		return changes, nil
+	}
+	changes <- models.BannerChange{EventId: options.LastEventId + 1}
+	return changes, nil
+}
+
+func (f *fakeService) ListBanners(_ context.Context, _ *models.BannerListOptions) (*models.BannerPage, error) {
+	if f.listCalls.Add(1) <= f.failures {
+		return nil, e.ErrorInternal
+	}
+	return &models.BannerPage{Banners: []models.BannerExt{{BannerId: 1, Banner: models.Banner{IsActive: true}}}}, nil
+}
+
+func (f *fakeService) CreateBanner(_ context.Context, _ *models.Banner) (int, error) {
+	if f.createCalls.Add(1) <= f.failures {
+		return 0, e.ErrorInternal
+	}
+	return 0, e.ErrorConflict
+}
+
+func (f *fakeService) DeleteBannersByTagOrFeature(_ context.Context, options *models.BulkDeleteOptions) (*models.DeletePreview, error) {
+	f.deleted = options
+	preview := &models.DeletePreview{Ids: []int{1, 2}, Token: "token", ConfirmRequired: true}
+	if !options.DryRun {
+		preview.Job = &models.DeleteJob{Id: 1, Filter: options.BulkDeleteFilter, Status: models.DeleteJobPending}
+	}
+	return preview, nil
+}
+
+func (f *fakeService) GetDeleteJob(_ context.Context, id int) (*models.DeleteJob, error) {
+	if id != 1 || f.deleted == nil {
+		return nil, e.ErrorNotFound
+	}
+	return &models.DeleteJob{Id: 1, Filter: f.deleted.BulkDeleteFilter, Status: models.DeleteJobDone, RemovedIds: []int{1, 2}}, nil
+}
+
+// GetBanner returns the banner 1 at revision 2, which is the only one
+func (f *fakeService) GetBanner(_ context.Context, id int) (*models.BannerExt, error) {
+	if id != 1 {
+		return nil, e.ErrorNotFound
+	}
+	return &models.BannerExt{BannerId: 1, Banner: models.Banner{BaseBanner: models.BaseBanner{
+		UserBanner: models.UserBanner{Content: map[string]any{"title": "some_title"}},
+		FeatureId:  1,
+		TagIds:     []int{1},
+	}, IsActive: true}, Revision: 2}, nil
+}
+
+func (f *fakeService) matchBanner(ifMatch string) error {
+	f.ifMatch = ifMatch
+	if ifMatch != "" && ifMatch != models.ETag(2, true) {
+		return e.ErrorPreconditionFailed
+	}
+	return nil
+}
+
+func (f *fakeService) UpdateBanner(_ context.Context, _ int, _ *models.UpdateBanner, ifMatch string) error {
+	return f.matchBanner(ifMatch)
+}
+
+func (f *fakeService) PatchBannerContent(_ context.Context, _ int, contentPatch *models.ContentPatch, ifMatch string) error {
+	f.patched = contentPatch
+	return f.matchBanner(ifMatch)
+}
+
+func (f *fakeService) DeleteBanner(_ context.Context, _ int, ifMatch string) error {
+	return f.matchBanner(ifMatch)
+}
+
+func (f *fakeService) SearchBanners(_ context.Context, options *models.BannerSearchOptions) (*models.SearchPage, error) {
+	f.searched = options
+	return &models.SearchPage{Hits: []models.SearchHit{{BannerExt: models.BannerExt{BannerId: 1}, Rank: 0.5}}, Total: 1}, nil
+}
+
+func (f *fakeService) RestoreBanner(_ context.Context, id int) error {
+	if id != 1 {
+		return e.ErrorQuotaExceeded
+	}
+	return nil
+}
+
+func (f *fakeService) ExportBanners(_ context.Context, _ *models.BannerListOptions, fn func(banner *models.BannerExt) error) error {
+	for id := 1; id <= 2; id++ {
+		banner := &models.BannerExt{BannerId: id, Banner: models.Banner{BaseBanner: models.BaseBanner{
+			UserBanner: models.UserBanner{Content: map[string]any{"title": "some_title"}},
+			FeatureId:  id,
+			TagIds:     []int{1},
+		}, IsActive: true}}
+		if err := fn(banner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeService) ImportBanners(_ context.Context, next func() (*models.ImportRecord, error), options *models.ImportOptions) (*models.ImportReport, error) {
+	f.importMode = options
+	for {
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.imported = append(f.imported, *record)
+	}
+	return &models.ImportReport{Mode: options.Mode, DryRun: options.DryRun, Total: len(f.imported), Created: len(f.imported)}, nil
+}
+
+func (f *fakeService) PutFeatureSchema(_ context.Context, schema *models.FeatureSchema, dryRun bool) (*models.SchemaReport, error) {
+	f.schema = schema
+	return &models.SchemaReport{FeatureId: schema.FeatureId, DryRun: dryRun, Checked: 1}, nil
+}
+
+func (f *fakeService) CreateRegistryEntry(_ context.Context, registry models.Registry, entry *models.RegistryEntry) (int, error) {
+	if f.registered == nil {
+		f.registered = make(map[models.Registry]*models.RegistryEntry)
+	}
+	f.registered[registry] = entry
+	return 3, nil
+}
+
+func (f *fakeService) ListRegistry(_ context.Context, registry models.Registry) ([]models.RegistryEntry, error) {
+	return []models.RegistryEntry{*f.registered[registry]}, nil
+}
+
+func (f *fakeService) DeleteRegistryEntry(_ context.Context, _ models.Registry, _ int) error {
+	return e.ErrorTagInUse
+}
+
+func (f *fakeService) CreateWebhook(_ context.Context, webhook *models.Webhook) (int, error) {
+	f.webhook = webhook
+	return 7, nil
+}
+
+func (f *fakeService) ListDeliveries(_ context.Context, options *models.DeliveryListOptions) ([]models.WebhookDelivery, error) {
+	f.deliveries = options
+	return []models.WebhookDelivery{{
+		Id:             3,
+		WebhookId:      7,
+		Event:          models.WebhookCreated,
+		BannerId:       1,
+		Payload:        []byte(`{"event": "created", "banner_id": 1}`),
+		Status:         models.DeliveryDead,
+		Attempts:       8,
+		ResponseStatus: 500,
+	}}, nil
+}
+
+func (f *fakeService) RetryDelivery(_ context.Context, webhookId int, id int64) error {
+	if webhookId != 7 || id != 3 {
+		return e.ErrorNotFound
+	}
+	return nil
+}
+
+func setup(t *testing.T, srv *fakeService) (*httptest.Server, *api.Client) {
+	gin.SetMode(gin.TestMode)
+	sso := auth.NewAuth()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := httptest.NewServer(handlers.New(srv, sso, sso, logger).GetHandler())
+	t.Cleanup(server.Close)
+	return server, api.NewClient(server.URL)
+}
+
+func newClient(t *testing.T, url string, admin bool, opts ...api.Option) *api.Client {
+	token, err := api.NewClient(url).GetToken(context.Background(), admin)
+	require.NoError(t, err)
+	opts = append(opts, api.WithToken(token), api.WithRetryPolicy(api.RetryPolicy{
+		MaxRetries: 2,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	}))
+	return api.NewClient(url, opts...)
+}
+
+func TestClient_GetUserBanner(t *testing.T) {
+	srv := &fakeService{}
+	server, anonymous := setup(t, srv)
+	ctx := context.Background()
+	one, two := 1, 2
+
+	_, err := anonymous.GetUserBanner(ctx, &api.UserBannerParams{TagId: &one, FeatureId: &one})
+	assert.ErrorIs(t, err, api.ErrUnauthorized)
+
+	client := newClient(t, server.URL, false, api.WithCache(time.Minute))
+	content, err := client.GetUserBanner(ctx, &api.UserBannerParams{TagId: &one, FeatureId: &one})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"title": "some_title"}, content)
+
+	_, err = client.GetUserBanner(ctx, &api.UserBannerParams{TagId: &one, FeatureId: &one})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, srv.userCalls.Load(), "second call should be served from cache")
+
+	useLastRevision := true
+	_, err = client.GetUserBanner(ctx, &api.UserBannerParams{TagId: &one, FeatureId: &one, UseLastRevision: &useLastRevision})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, srv.userCalls.Load(), "use_last_revision should bypass cache")
+
+	_, err = client.GetUserBanner(ctx, &api.UserBannerParams{TagId: &one, FeatureId: &two})
+	assert.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestClient_GetUserBanners(t *testing.T) {
+	server, _ := setup(t, &fakeService{})
+	client := newClient(t, server.URL, false)
+	one, locale := 1, "ru"
+
+	resp, err := client.GetUserBanners(context.Background(), &api.UserBannersRequest{TagId: &one, FeatureIds: &[]int{1, 2}, Locale: &locale})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Banners)
+	banners := *resp.Banners
+	require.Len(t, banners, 2)
+	assert.Equal(t, 200, *banners["1"].Status)
+	assert.Equal(t, api.Content{"title": "some_title"}, *banners["1"].Content)
+	assert.Equal(t, 404, *banners["2"].Status)
+	assert.Nil(t, banners["2"].Content)
+
+	_, err = client.GetUserBanners(context.Background(), &api.UserBannersRequest{TagId: &one, FeatureIds: &[]int{}})
+	assert.ErrorIs(t, err, api.ErrBadRequest)
+}
+
+func TestClient_StreamUserBanner(t *testing.T) {
+	server, anonymous := setup(t, &fakeService{})
+	client := newClient(t, server.URL, false)
+	one := 1
+	params := &api.UserBannerStreamParams{TagId: &one, FeatureId: &one}
+	errStop := errors.New("stop")
+
+	var events []api.BannerStreamEvent
+	err := client.StreamUserBanner(context.Background(), params, func(event *api.BannerStreamEvent) error {
+		events = append(events, *event)
+		if len(events) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []api.BannerStreamEvent{
+		{Id: "1", Content: map[string]interface{}{"title": "some_title"}},
+		{Id: "2"},
+	}, events, "closed stream is resumed after the last event")
+
+	err = anonymous.StreamUserBanner(context.Background(), params, func(*api.BannerStreamEvent) error { return nil })
+	assert.ErrorIs(t, err, api.ErrUnauthorized)
+}
+
+func TestClient_Permissions(t *testing.T) {
+	server, _ := setup(t, &fakeService{})
+	client := newClient(t, server.URL, false)
+
+	_, err := client.ListBanners(context.Background(), &api.ListBannerParams{})
+	assert.ErrorIs(t, err, api.ErrForbidden)
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		wantErr  error
+		calls    int32
+	}{
+		{name: "recovered", failures: 2, calls: 3},
+		{name: "exhausted", failures: 5, wantErr: api.ErrInternal, calls: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := &fakeService{failures: test.failures}
+			server, _ := setup(t, srv)
+			client := newClient(t, server.URL, true)
+
+			page, err := client.ListBanners(context.Background(), &api.ListBannerParams{})
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, *page.Items, 1)
+			}
+			assert.Equal(t, test.calls, srv.listCalls.Load())
+		})
+	}
+}
+
+func TestError_Unwrap(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{status: http.StatusBadRequest, want: api.ErrBadRequest},
+		{status: http.StatusNotFound, want: api.ErrNotFound},
+		{status: http.StatusConflict, want: api.ErrConflict},
+		{status: http.StatusPreconditionFailed, want: api.ErrPreconditionFailed},
+		{status: http.StatusUnprocessableEntity, want: api.ErrUnprocessable},
+		{status: http.StatusTooManyRequests, want: api.ErrTooManyRequests},
+		{status: http.StatusBadGateway, want: api.ErrInternal},
+		{status: http.StatusTeapot, want: api.ErrUnexpected},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			err := error(&api.Error{StatusCode: test.status})
+			assert.ErrorIs(t, err, test.want)
+			if test.want != api.ErrUnexpected {
+				assert.NotErrorIs(t, err, api.ErrUnexpected)
+			}
+		})
+	}
+}
+
+func TestClient_CreateBanner(t *testing.T) {
+	ctx := context.Background()
+	zero, active := 0, true
+	req := &api.BannerRequest{
+		Content:   &map[string]interface{}{"title": "some_title"},
+		FeatureId: &zero,
+		IsActive:  &active,
+		TagIds:    &[]int{1},
+	}
+
+	srv := &fakeService{failures: 1}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+
+	_, err := client.CreateBanner(ctx, req)
+	assert.ErrorIs(t, err, api.ErrInternal)
+	assert.EqualValues(t, 1, srv.createCalls.Load(), "post should not be retried")
+
+	_, err = client.CreateBanner(ctx, req)
+	var apiErr *api.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.ErrorIs(t, err, api.ErrBadRequest)
+	assert.Equal(t, e.ErrorConflict.Error(), apiErr.Message)
+}
+
+func TestClient_DeleteBanners(t *testing.T) {
+	srv := &fakeService{}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+	feature := 10
+
+	params := &api.DeleteBannerParams{FeatureId: &feature}
+	preview, err := client.PreviewDeleteBanners(context.Background(), params)
+	require.NoError(t, err)
+	assert.True(t, srv.deleted.DryRun)
+	assert.Equal(t, 2, *preview.Count)
+	assert.Equal(t, []int{1, 2}, *preview.BannerIds)
+	assert.True(t, *preview.ConfirmRequired)
+
+	params.ConfirmToken = preview.ConfirmToken
+	params.TagIds = &[]int{3, 4}
+	params.InactiveOnly = new(bool)
+	*params.InactiveOnly = true
+	job, err := client.DeleteBanners(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, feature, srv.deleted.FeatureId)
+	assert.Equal(t, []int{3, 4}, srv.deleted.TagIds)
+	assert.True(t, srv.deleted.InactiveOnly)
+	assert.False(t, srv.deleted.DryRun)
+	assert.Equal(t, "token", srv.deleted.ConfirmToken)
+	assert.Equal(t, "pending", *job.Status)
+	assert.Empty(t, *job.RemovedIds)
+
+	job, err = client.GetDeleteJob(context.Background(), *job.JobId)
+	require.NoError(t, err)
+	assert.Equal(t, "done", *job.Status)
+	assert.Equal(t, []int{1, 2}, *job.RemovedIds)
+	assert.Equal(t, 2, *job.RemovedCount)
+	assert.Equal(t, []int{3, 4}, *job.TagIds)
+	_, err = client.GetDeleteJob(context.Background(), 2)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestClient_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	srv := &fakeService{}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+	target, secret := "https://example.com/hook", "0123456789abcdef"
+
+	id, err := client.CreateWebhook(ctx, &api.WebhookRequest{Url: &target, Events: &[]string{"created", "deleted"}, Secret: &secret})
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, &models.Webhook{
+		Url:      target,
+		Events:   []models.WebhookEvent{models.WebhookCreated, models.WebhookDeleted},
+		Secret:   secret,
+		IsActive: true,
+	}, srv.webhook)
+
+	status := "dead"
+	deliveries, err := client.ListWebhookDeliveries(ctx, 7, &api.DeliveriesParams{Status: &status})
+	require.NoError(t, err)
+	assert.Equal(t, &models.DeliveryListOptions{WebhookId: 7, Status: models.DeliveryDead, Limit: models.ZeroValue, Offset: models.ZeroValue}, srv.deliveries)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, map[string]interface{}{"event": "created", "banner_id": float64(1)}, *deliveries[0].Payload)
+	assert.Equal(t, 500, *deliveries[0].ResponseStatus)
+	assert.Nil(t, deliveries[0].LastError)
+
+	_, err = client.ListDeadLetters(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.ZeroValue, srv.deliveries.WebhookId)
+	assert.Equal(t, models.DeliveryDead, srv.deliveries.Status)
+
+	assert.NoError(t, client.RetryWebhookDelivery(ctx, 7, 3))
+	assert.ErrorIs(t, client.RetryWebhookDelivery(ctx, 7, 4), api.ErrNotFound)
+}
+
+func TestClient_ConditionalChanges(t *testing.T) {
+	ctx := context.Background()
+	srv := &fakeService{}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+	active := false
+
+	banner, err := client.GetBanner(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.ETag(2, true), *banner.Etag)
+	_, err = client.GetBanner(ctx, 2)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+
+	require.NoError(t, client.UpdateBanner(ctx, 1, &api.BannerUpdateRequest{IsActive: &active}, api.WithIfMatch(*banner.Etag)))
+	assert.Equal(t, *banner.Etag, srv.ifMatch)
+	require.NoError(t, client.UpdateBanner(ctx, 1, &api.BannerUpdateRequest{IsActive: &active}))
+	assert.Empty(t, srv.ifMatch, "change without If-Match is unconditional")
+
+	require.NoError(t, client.MergePatchBanner(ctx, 1, api.Content{"title": nil}, api.WithIfMatch(*banner.Etag)))
+	assert.Equal(t, map[string]any{"title": nil}, srv.patched.Merge)
+	op, path := "replace", "/title"
+	require.NoError(t, client.JSONPatchBanner(ctx, 1, []api.JsonPatchOperation{{Op: &op, Path: &path, Value: "Sale"}}))
+	require.Len(t, srv.patched.Operations, 1)
+	assert.Nil(t, srv.patched.Merge)
+
+	err = client.DeleteBanner(ctx, 1, api.WithIfMatch(models.ETag(1, true)))
+	assert.ErrorIs(t, err, api.ErrPreconditionFailed)
+}
+
+func TestClient_SearchAndTrash(t *testing.T) {
+	ctx := context.Background()
+	srv := &fakeService{}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+	q, feature := "black friday", 1
+
+	found, err := client.SearchBanners(ctx, &api.SearchBannersParams{Q: &q, Where: &[]string{"cta.url exists"}, FeatureId: &feature})
+	require.NoError(t, err)
+	assert.Equal(t, q, srv.searched.Query)
+	assert.Len(t, srv.searched.Predicates, 1)
+	assert.Equal(t, feature, srv.searched.FeatureId)
+	require.Len(t, *found.Items, 1)
+	assert.Equal(t, 0.5, *(*found.Items)[0].Rank)
+
+	assert.NoError(t, client.RestoreBanner(ctx, 1))
+	assert.ErrorIs(t, client.RestoreBanner(ctx, 2), api.ErrTooManyRequests)
+}
+
+func TestClient_Transfer(t *testing.T) {
+	ctx := context.Background()
+	srv := &fakeService{}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+
+	export := &bytes.Buffer{}
+	require.NoError(t, client.ExportBanners(ctx, nil, export))
+	lines := strings.Split(strings.TrimSpace(export.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"banner_id": 1, "feature_id": 1, "tag_ids": [1], "content": {"title": "some_title"}, "is_active": true}`, lines[0])
+
+	mode, dryRun := "upsert", true
+	report, err := client.ImportBanners(ctx, &api.ImportBannersParams{Mode: &mode, DryRun: &dryRun}, api.FormatNDJSON, export)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ImportOptions{Mode: models.ImportUpsert, DryRun: true, MaxCreated: models.ZeroValue}, srv.importMode)
+	require.Len(t, srv.imported, 2)
+	assert.Equal(t, 2, srv.imported[1].FeatureId)
+	assert.Equal(t, 2, *report.Created)
+
+	_, err = client.ImportBanners(ctx, nil, "xml", strings.NewReader("<banners/>"))
+	assert.Error(t, err)
+}
+
+func TestClient_Registries(t *testing.T) {
+	ctx := context.Background()
+	srv := &fakeService{}
+	server, _ := setup(t, srv)
+	client := newClient(t, server.URL, true)
+	name := "new_users"
+
+	id, err := client.CreateTag(ctx, &api.RegistryRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.Equal(t, name, srv.registered[models.Tags].Name)
+	tags, err := client.ListTags(ctx)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, name, *tags[0].Name)
+	assert.ErrorIs(t, client.DeleteTag(ctx, 3), api.ErrBadRequest)
+
+	report, err := client.PutFeatureSchema(ctx, 1, api.Content{"type": "object"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "object"}, srv.schema.Schema)
+	assert.Equal(t, 1, srv.schema.FeatureId)
+	assert.True(t, *report.DryRun)
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	secret, body := "0123456789abcdef", []byte(`{"event":"created"}`)
+	now := time.Now().Unix()
+	header := http.Header{}
+	header.Set(api.WebhookTimestampHeader, strconv.FormatInt(now, 10))
+	header.Set(api.WebhookSignatureHeader, models.SignWebhook(secret, now, body))
+
+	assert.NoError(t, api.VerifyWebhookSignature(secret, header, body, time.Minute))
+	assert.ErrorIs(t, api.VerifyWebhookSignature("another secret", header, body, time.Minute), api.ErrInvalidSignature)
+	assert.ErrorIs(t, api.VerifyWebhookSignature(secret, header, []byte(`{}`), time.Minute), api.ErrInvalidSignature)
+
+	old := now - 3600
+	header.Set(api.WebhookTimestampHeader, strconv.FormatInt(old, 10))
+	header.Set(api.WebhookSignatureHeader, models.SignWebhook(secret, old, body))
+	assert.ErrorIs(t, api.VerifyWebhookSignature(secret, header, body, time.Minute), api.ErrInvalidSignature, "replayed delivery")
+	assert.NoError(t, api.VerifyWebhookSignature(secret, header, body, 0))
+}
+
+func TestJsonPatchOperationJSON(t *testing.T) {
+	operation := func(op string, value any) api.JsonPatchOperation {
+		path := "/content/title"
+		return api.JsonPatchOperation{Op: &op, Path: &path, Value: value}
+	}
+	raw, err := json.Marshal([]api.JsonPatchOperation{operation("replace", nil), operation("remove", nil), operation("add", "Sale")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/content/title", "value": null},
+		{"op": "remove", "path": "/content/title"},
+		{"op": "add", "path": "/content/title", "value": "Sale"}
+	]`, string(raw))
+}
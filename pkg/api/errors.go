@@ -0,0 +1,71 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	// ErrConflict is returned when the banner was changed concurrently or the request clashes with existing data
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed is returned when If-Match does not match the current ETag
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnprocessable is returned when the request is well-formed, but can not be applied
+	ErrUnprocessable = errors.New("unprocessable entity")
+	// ErrTooManyRequests is returned when a quota or a rate limit is exceeded
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrInternal        = errors.New("internal server error")
+	ErrUnexpected      = errors.New("unexpected response")
+)
+
+// Error is returned by Client for every non-2xx response
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("bannerflow: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("bannerflow: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Unwrap maps status code to one of the sentinel errors, so errors.Is can be used
+func (e *Error) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrUnprocessable
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrTooManyRequests
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrInternal
+	default:
+		return ErrUnexpected
+	}
+}
+
+func newError(statusCode int, body *BannerErrorResponse) *Error {
+	err := &Error{StatusCode: statusCode}
+	if body != nil && body.Error != nil {
+		err.Message = *body.Error
+	}
+	return err
+}
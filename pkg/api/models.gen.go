@@ -0,0 +1,514 @@
+// Code generated by apigen from api.yaml. DO NOT EDIT.
+
+package api
+
+import "time"
+
+// Content JSON-отображение баннера
+type Content = map[string]interface{}
+
+type BannerDiffResponse struct {
+	From    *int                  `json:"from" binding:"required"`
+	Patch   *[]JsonPatchOperation `json:"patch" binding:"required"`
+	Summary *[]string             `json:"summary" binding:"required"`
+	To      *int                  `json:"to,omitempty"`
+}
+
+type BannerErrorResponse struct {
+	Details *[]FieldError `json:"details,omitempty"`
+	Error   *string       `json:"error" binding:"required"`
+}
+
+type BannerIdResponse struct {
+	BannerId *int `json:"banner_id" binding:"required,gte=1"`
+}
+
+type BannerListResponse struct {
+	Items      *[]BannerResponse `json:"items" binding:"required"`
+	NextCursor *string           `json:"next_cursor,omitempty"`
+	Total      *int              `json:"total,omitempty"`
+}
+
+type BannerRecord struct {
+	BannerId  *int     `json:"banner_id" binding:"omitempty,gte=1"`
+	Content   *Content `json:"content" binding:"required"`
+	FeatureId *int     `json:"feature_id" binding:"required,gte=0"`
+	IsActive  *bool    `json:"is_active" binding:"required"`
+	TagIds    *[]int   `json:"tag_ids" binding:"required,gte=1,dive,gte=0"`
+}
+
+type BannerRequest struct {
+	Content   *Content `json:"content" binding:"required"`
+	FeatureId *int     `json:"feature_id" binding:"required,gte=0"`
+	IsActive  *bool    `json:"is_active" binding:"required"`
+	TagIds    *[]int   `json:"tag_ids" binding:"required,gte=1,dive,gte=0"`
+}
+
+type BannerResponse struct {
+	BannerId  *int       `json:"banner_id" binding:"required,gte=1"`
+	Content   *Content   `json:"content" binding:"required"`
+	CreatedAt *time.Time `json:"created_at" binding:"required"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Etag      *string    `json:"etag" binding:"required"`
+	FeatureId *int       `json:"feature_id" binding:"required,gte=0"`
+	IsActive  *bool      `json:"is_active" binding:"required"`
+	Live      *bool      `json:"live,omitempty"`
+	Revision  *int       `json:"revision" binding:"required,gte=1"`
+	State     *string    `json:"state,omitempty"`
+	TagIds    *[]int     `json:"tag_ids" binding:"required,gte=1,dive,gte=0"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type BannerRevisionResponse struct {
+	Author    *string    `json:"author" binding:"required"`
+	BannerId  *int       `json:"banner_id" binding:"required,gte=1"`
+	Comment   *string    `json:"comment,omitempty"`
+	Content   *Content   `json:"content" binding:"required"`
+	FeatureId *int       `json:"feature_id" binding:"required,gte=0"`
+	IsActive  *bool      `json:"is_active" binding:"required"`
+	Reviewer  *string    `json:"reviewer,omitempty"`
+	State     *string    `json:"state" binding:"required"`
+	TagIds    *[]int     `json:"tag_ids" binding:"required,dive,gte=0"`
+	UpdatedAt *time.Time `json:"updated_at" binding:"required"`
+}
+
+type BannerSearchHit struct {
+	Banner *BannerResponse `json:"banner" binding:"required"`
+	Rank   *float64        `json:"rank" binding:"required"`
+}
+
+type BannerSearchResponse struct {
+	Items      *[]BannerSearchHit `json:"items" binding:"required"`
+	NextCursor *string            `json:"next_cursor,omitempty"`
+	Total      *int               `json:"total,omitempty"`
+}
+
+type BannerUpdateRequest struct {
+	Content   *Content `json:"content"`
+	FeatureId *int     `json:"feature_id" binding:"omitempty,gte=0"`
+	IsActive  *bool    `json:"is_active"`
+	TagIds    *[]int   `json:"tag_ids" binding:"omitempty,gte=1,dive,gte=0"`
+}
+
+type BannerVersionResponse struct {
+	Actor     *string    `json:"actor"`
+	Content   *Content   `json:"content"`
+	CreatedAt *time.Time `json:"created_at"`
+	FeatureId *int       `json:"feature_id"`
+	IsActive  *bool      `json:"is_active"`
+	TagIds    *[]int     `json:"tag_ids"`
+	Version   *int       `json:"version"`
+}
+
+type BatchItemResponse struct {
+	BannerId *int          `json:"banner_id,omitempty"`
+	Details  *[]FieldError `json:"details,omitempty"`
+	Error    *string       `json:"error,omitempty"`
+	Index    *int          `json:"index" binding:"required"`
+	Status   *int          `json:"status" binding:"required"`
+}
+
+type BatchOperation struct {
+	Banner  *BannerUpdateRequest `json:"banner"`
+	Id      *int                 `json:"id" binding:"omitempty,gte=1"`
+	IfMatch *string              `json:"if_match"`
+	Op      *string              `json:"op" binding:"required"`
+}
+
+type BatchRequest struct {
+	Mode       *string           `json:"mode"`
+	Operations *[]BatchOperation `json:"operations" binding:"required,gte=1"`
+}
+
+type BatchResponse struct {
+	Results *[]BatchItemResponse `json:"results" binding:"required"`
+}
+
+type DeleteJobResponse struct {
+	CreatedAt     *time.Time `json:"created_at" binding:"required"`
+	FeatureId     *int       `json:"feature_id,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	InactiveOnly  *bool      `json:"inactive_only" binding:"required"`
+	JobId         *int       `json:"job_id" binding:"required"`
+	RemovedCount  *int       `json:"removed_count" binding:"required"`
+	RemovedIds    *[]int     `json:"removed_ids" binding:"required"`
+	Status        *string    `json:"status" binding:"required"`
+	TagIds        *[]int     `json:"tag_ids,omitempty"`
+	UpdatedBefore *time.Time `json:"updated_before,omitempty"`
+}
+
+type DeletePreviewResponse struct {
+	BannerIds       *[]int  `json:"banner_ids" binding:"required"`
+	ConfirmRequired *bool   `json:"confirm_required" binding:"required"`
+	ConfirmToken    *string `json:"confirm_token" binding:"required"`
+	Count           *int    `json:"count" binding:"required"`
+}
+
+type EvaluateRequest struct {
+	AppVersion *string `json:"app_version"`
+	Country    *string `json:"country"`
+	FeatureId  *int    `json:"feature_id" binding:"required,gte=0"`
+	Locale     *string `json:"locale"`
+	Platform   *string `json:"platform"`
+	TagIds     *[]int  `json:"tag_ids" binding:"required,gte=1,dive,gte=0"`
+}
+
+type EvaluateResponse struct {
+	BannerId   *int                  `json:"banner_id,omitempty"`
+	Candidates *[]EvaluatedCandidate `json:"candidates" binding:"required"`
+	Content    *Content              `json:"content,omitempty"`
+	Locale     *string               `json:"locale,omitempty"`
+}
+
+type EvaluatedCandidate struct {
+	BannerId  *int      `json:"banner_id" binding:"required"`
+	IsActive  *bool     `json:"is_active" binding:"required"`
+	Matched   *bool     `json:"matched" binding:"required"`
+	Priority  *int      `json:"priority" binding:"required"`
+	Published *bool     `json:"published" binding:"required"`
+	Reasons   *[]string `json:"reasons" binding:"required"`
+	Served    *bool     `json:"served" binding:"required"`
+}
+
+type FeatureSchemaResponse struct {
+	CreatedAt *time.Time `json:"created_at"`
+	FeatureId *int       `json:"feature_id" binding:"required"`
+	Schema    *Content   `json:"schema" binding:"required"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type FieldError struct {
+	Field   *string `json:"field" binding:"required"`
+	Message *string `json:"message" binding:"required"`
+}
+
+type ImportRecordError struct {
+	Details *[]FieldError `json:"details" binding:"required"`
+	Line    *int          `json:"line" binding:"required"`
+}
+
+type ImportReportResponse struct {
+	Applied *bool                `json:"applied" binding:"required"`
+	Created *int                 `json:"created" binding:"required"`
+	DryRun  *bool                `json:"dry_run" binding:"required"`
+	Errors  *[]ImportRecordError `json:"errors" binding:"required"`
+	Failed  *int                 `json:"failed" binding:"required"`
+	Mode    *string              `json:"mode" binding:"required"`
+	Skipped *int                 `json:"skipped" binding:"required"`
+	Total   *int                 `json:"total" binding:"required"`
+	Updated *int                 `json:"updated" binding:"required"`
+}
+
+type InvalidBanner struct {
+	BannerId *int          `json:"banner_id" binding:"required"`
+	Details  *[]FieldError `json:"details" binding:"required"`
+}
+
+type JsonPatchOperation struct {
+	From  *string     `json:"from,omitempty"`
+	Op    *string     `json:"op" binding:"required"`
+	Path  *string     `json:"path" binding:"required"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type MissingTranslationsResponse struct {
+	BannerId  *int      `json:"banner_id" binding:"required"`
+	FeatureId *int      `json:"feature_id" binding:"required"`
+	Locales   *[]string `json:"locales" binding:"required"`
+}
+
+type RegistryIdResponse struct {
+	Id *int `json:"id" binding:"required"`
+}
+
+type RegistryRequest struct {
+	Description *string `json:"description"`
+	Id          *int    `json:"id" binding:"omitempty,gte=0"`
+	Name        *string `json:"name" binding:"required,min=1,max=128"`
+	Owner       *string `json:"owner"`
+}
+
+type RegistryResponse struct {
+	CreatedAt   *time.Time `json:"created_at"`
+	Description *string    `json:"description"`
+	Id          *int       `json:"id" binding:"required"`
+	Name        *string    `json:"name" binding:"required"`
+	Owner       *string    `json:"owner"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+type ReviewRejectRequest struct {
+	Comment *string `json:"comment" binding:"required,min=1"`
+}
+
+type SchemaReportResponse struct {
+	Checked   *int             `json:"checked" binding:"required"`
+	DryRun    *bool            `json:"dry_run" binding:"required"`
+	FeatureId *int             `json:"feature_id" binding:"required"`
+	Invalid   *[]InvalidBanner `json:"invalid" binding:"required"`
+}
+
+type TargetingRequest struct {
+	Priority *int           `json:"priority"`
+	Rule     *TargetingRule `json:"rule" binding:"required"`
+}
+
+type TargetingResponse struct {
+	BannerId *int           `json:"banner_id" binding:"required,gte=1"`
+	Priority *int           `json:"priority" binding:"required"`
+	Rule     *TargetingRule `json:"rule" binding:"required"`
+}
+
+// TargetingRule Все заданные условия должны выполняться, списки выполняются, если содержат атрибут пользователя
+type TargetingRule struct {
+	Countries  *[]string `json:"countries,omitempty"`
+	Locales    *[]string `json:"locales,omitempty"`
+	MaxVersion *string   `json:"max_version,omitempty"`
+	MinVersion *string   `json:"min_version,omitempty"`
+	Platforms  *[]string `json:"platforms,omitempty"`
+	TagIds     *[]int    `json:"tag_ids,omitempty" binding:"omitempty,dive,gte=0"`
+}
+
+type TenantQuotaResponse struct {
+	Banners    *int    `json:"banners" binding:"required"`
+	MaxBanners *int    `json:"max_banners" binding:"required"`
+	Tenant     *string `json:"tenant" binding:"required"`
+}
+
+type TokenResponse struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type TranslationResponse struct {
+	Content   *Content   `json:"content" binding:"required"`
+	Locale    *string    `json:"locale" binding:"required"`
+	UpdatedAt *time.Time `json:"updated_at" binding:"required"`
+}
+
+type UserBannerItem struct {
+	Content *Content `json:"content,omitempty"`
+	Status  *int     `json:"status" binding:"required"`
+}
+
+type UserBannersRequest struct {
+	AppVersion      *string `json:"app_version"`
+	Country         *string `json:"country"`
+	FeatureIds      *[]int  `json:"feature_ids" binding:"required,gte=1,dive,gte=0"`
+	Locale          *string `json:"locale"`
+	Platform        *string `json:"platform"`
+	TagId           *int    `json:"tag_id" binding:"required,gte=0"`
+	TagIds          *[]int  `json:"tag_ids" binding:"omitempty,dive,gte=0"`
+	UseLastRevision *bool   `json:"use_last_revision"`
+}
+
+type UserBannersResponse struct {
+	Banners *map[string]UserBannerItem `json:"banners" binding:"required"`
+}
+
+type WebhookDeliveryResponse struct {
+	Attempts       *int                    `json:"attempts" binding:"required"`
+	BannerId       *int                    `json:"banner_id" binding:"required"`
+	CreatedAt      *time.Time              `json:"created_at" binding:"required"`
+	DeliveredAt    *time.Time              `json:"delivered_at,omitempty"`
+	Event          *string                 `json:"event" binding:"required"`
+	Id             *int                    `json:"id" binding:"required"`
+	LastError      *string                 `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time              `json:"next_attempt_at" binding:"required"`
+	Payload        *map[string]interface{} `json:"payload" binding:"required"`
+	ResponseStatus *int                    `json:"response_status,omitempty"`
+	Status         *string                 `json:"status" binding:"required"`
+	WebhookId      *int                    `json:"webhook_id" binding:"required"`
+}
+
+type WebhookIdResponse struct {
+	Id *int `json:"id" binding:"required"`
+}
+
+type WebhookRequest struct {
+	Events   *[]string `json:"events" binding:"required,gte=1"`
+	IsActive *bool     `json:"is_active"`
+	Secret   *string   `json:"secret"`
+	Url      *string   `json:"url" binding:"required,min=1"`
+}
+
+type WebhookResponse struct {
+	CreatedAt *time.Time `json:"created_at" binding:"required"`
+	Events    *[]string  `json:"events" binding:"required"`
+	Id        *int       `json:"id" binding:"required"`
+	IsActive  *bool      `json:"is_active" binding:"required"`
+	UpdatedAt *time.Time `json:"updated_at" binding:"required"`
+	Url       *string    `json:"url" binding:"required"`
+}
+
+type AcceptLanguageParams struct {
+	AcceptLanguage *string `header:"Accept-Language"`
+}
+
+type AdminParam struct {
+	Admin string `uri:"admin" binding:"required"`
+}
+
+type DeadLettersParams struct {
+	Limit  *int `form:"limit" binding:"omitempty,gte=1"`
+	Offset *int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type DeleteBannerParams struct {
+	FeatureId     *int       `form:"feature_id" binding:"required_without=TagIds,omitempty,gte=0"`
+	TagIds        *[]int     `form:"tag_ids" binding:"required_without=FeatureId,omitempty,dive,gte=0"`
+	InactiveOnly  *bool      `form:"inactive_only"`
+	UpdatedBefore *time.Time `form:"updated_before"`
+	DryRun        *bool      `form:"dry_run"`
+	ConfirmToken  *string    `form:"confirm_token"`
+}
+
+type DeliveriesParams struct {
+	Status *string `form:"status"`
+	Limit  *int    `form:"limit" binding:"omitempty,gte=1"`
+	Offset *int    `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type DeliveryParams struct {
+	WebhookId  int `uri:"webhook_id" binding:"required,gte=1"`
+	DeliveryId int `uri:"delivery_id" binding:"required,gte=1"`
+}
+
+type DiffParams struct {
+	From int  `form:"from" binding:"required,gte=1"`
+	To   *int `form:"to" binding:"omitempty,gte=1"`
+}
+
+type DryRunParams struct {
+	DryRun *bool `form:"dry_run"`
+}
+
+type ExportBannersParams struct {
+	Format    *string `form:"format"`
+	FeatureId *int    `form:"feature_id" binding:"omitempty,gte=0"`
+	TagId     *int    `form:"tag_id" binding:"omitempty,gte=0"`
+}
+
+type FeatureIdParams struct {
+	FeatureId *int `uri:"feature_id" binding:"required,gte=0"`
+}
+
+type HistoryParams struct {
+	Limit  *int `form:"limit" binding:"omitempty,gte=1"`
+	Offset *int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type IdParams struct {
+	Id int `uri:"id" binding:"required,gte=1"`
+}
+
+type IfMatchParams struct {
+	IfMatch *string `header:"If-Match"`
+}
+
+type ImportBannersParams struct {
+	Mode   *string `form:"mode"`
+	DryRun *bool   `form:"dry_run"`
+}
+
+type JobIdParams struct {
+	JobId int `uri:"job_id" binding:"required,gte=1"`
+}
+
+type ListBannerParams struct {
+	FeatureId     *int       `form:"feature_id" binding:"omitempty,gte=0"`
+	TagId         *int       `form:"tag_id" binding:"omitempty,gte=0"`
+	FeatureName   *string    `form:"feature_name"`
+	TagName       *string    `form:"tag_name"`
+	Limit         *int       `form:"limit" binding:"omitempty,gte=1"`
+	Offset        *int       `form:"offset" binding:"omitempty,gte=0"`
+	Cursor        *string    `form:"cursor"`
+	TagIds        *[]int     `form:"tag_ids" binding:"omitempty,dive,gte=0"`
+	IsActive      *bool      `form:"is_active"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	UpdatedAfter  *time.Time `form:"updated_after"`
+	UpdatedBefore *time.Time `form:"updated_before"`
+	Sort          *string    `form:"sort"`
+	IncludeTotal  *bool      `form:"include_total"`
+}
+
+type ListReviewsParams struct {
+	State  *string `form:"state"`
+	Limit  *int    `form:"limit" binding:"omitempty,gte=1"`
+	Offset *int    `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type ListTrashParams struct {
+	FeatureId *int    `form:"feature_id" binding:"omitempty,gte=0"`
+	TagId     *int    `form:"tag_id" binding:"omitempty,gte=0"`
+	Limit     *int    `form:"limit" binding:"omitempty,gte=1"`
+	Cursor    *string `form:"cursor"`
+}
+
+type MissingTranslationsParams struct {
+	Locales   *[]string `form:"locales" binding:"required,gte=1"`
+	FeatureId *int      `form:"feature_id" binding:"omitempty,gte=0"`
+}
+
+type SearchBannersParams struct {
+	Q            *string   `form:"q"`
+	Where        *[]string `form:"where"`
+	FeatureId    *int      `form:"feature_id" binding:"omitempty,gte=0"`
+	TagId        *int      `form:"tag_id" binding:"omitempty,gte=0"`
+	IsActive     *bool     `form:"is_active"`
+	Limit        *int      `form:"limit" binding:"omitempty,gte=1"`
+	Cursor       *string   `form:"cursor"`
+	IncludeTotal *bool     `form:"include_total"`
+}
+
+type SelectBannersParams struct {
+	Version int `form:"version" binding:"required,gte=1"`
+}
+
+type TagIdParams struct {
+	TagId *int `uri:"tag_id" binding:"required,gte=0"`
+}
+
+type TranslationParams struct {
+	Id     int    `uri:"id" binding:"required,gte=1"`
+	Locale string `uri:"locale" binding:"required"`
+}
+
+type UserBannerHeaderParams struct {
+	IfNoneMatch    *string `header:"If-None-Match"`
+	AcceptLanguage *string `header:"Accept-Language"`
+}
+
+type UserBannerParams struct {
+	TagId           *int    `form:"tag_id" binding:"required,gte=0"`
+	FeatureId       *int    `form:"feature_id" binding:"required,gte=0"`
+	UseLastRevision *bool   `form:"use_last_revision"`
+	TagIds          *[]int  `form:"tag_ids" binding:"omitempty,dive,gte=0"`
+	Locale          *string `form:"locale"`
+	Platform        *string `form:"platform"`
+	Country         *string `form:"country"`
+	AppVersion      *string `form:"app_version"`
+}
+
+type UserBannerStreamHeaderParams struct {
+	AcceptLanguage *string `header:"Accept-Language"`
+	LastEventId    *string `header:"Last-Event-ID"`
+}
+
+type UserBannerStreamParams struct {
+	TagId      *int    `form:"tag_id" binding:"required,gte=0"`
+	FeatureId  *int    `form:"feature_id" binding:"required,gte=0"`
+	TagIds     *[]int  `form:"tag_ids" binding:"omitempty,dive,gte=0"`
+	Locale     *string `form:"locale"`
+	Platform   *string `form:"platform"`
+	Country    *string `form:"country"`
+	AppVersion *string `form:"app_version"`
+}
+
+type WebhookIdParams struct {
+	WebhookId int `uri:"webhook_id" binding:"required,gte=1"`
+}
+
+type TokenParam struct {
+	Token string `header:"token" binding:"required"`
+}
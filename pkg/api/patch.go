@@ -0,0 +1,20 @@
+package api
+
+import "encoding/json"
+
+// MarshalJSON writes value of add, replace and test operations even when it is null, a null value is still
+// the value of them, and never writes it for remove, move and copy
+func (o JsonPatchOperation) MarshalJSON() ([]byte, error) {
+	type operation JsonPatchOperation
+	if o.Op != nil {
+		switch *o.Op {
+		case "add", "replace", "test":
+			return json.Marshal(struct {
+				operation
+				Value any `json:"value"`
+			}{operation: operation(o), Value: o.Value})
+		}
+	}
+	o.Value = nil
+	return json.Marshal(operation(o))
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListFeatures returns features registered by the tenant
+func (c *Client) ListFeatures(ctx context.Context) ([]RegistryResponse, error) {
+	return c.listRegistry(ctx, "/features")
+}
+
+// CreateFeature registers the feature and returns its id, nil Id of req takes the next free one
+func (c *Client) CreateFeature(ctx context.Context, req *RegistryRequest) (int, error) {
+	return c.createRegistryEntry(ctx, "/features", req)
+}
+
+// GetFeature returns the registered feature
+func (c *Client) GetFeature(ctx context.Context, id int) (*RegistryResponse, error) {
+	return c.getRegistryEntry(ctx, "/features/"+strconv.Itoa(id))
+}
+
+// UpdateFeature changes the name, the description and the owner of the feature
+func (c *Client) UpdateFeature(ctx context.Context, id int, req *RegistryRequest) error {
+	return c.do(ctx, http.MethodPut, "/features/"+strconv.Itoa(id), nil, req, nil)
+}
+
+// DeleteFeature removes the feature, its banners are deleted later
+func (c *Client) DeleteFeature(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/features/"+strconv.Itoa(id), nil, nil, nil)
+}
+
+// ListTags returns tags registered by the tenant
+func (c *Client) ListTags(ctx context.Context) ([]RegistryResponse, error) {
+	return c.listRegistry(ctx, "/tags")
+}
+
+// CreateTag registers the tag and returns its id, nil Id of req takes the next free one
+func (c *Client) CreateTag(ctx context.Context, req *RegistryRequest) (int, error) {
+	return c.createRegistryEntry(ctx, "/tags", req)
+}
+
+// GetTag returns the registered tag
+func (c *Client) GetTag(ctx context.Context, id int) (*RegistryResponse, error) {
+	return c.getRegistryEntry(ctx, "/tags/"+strconv.Itoa(id))
+}
+
+// UpdateTag changes the name, the description and the owner of the tag
+func (c *Client) UpdateTag(ctx context.Context, id int, req *RegistryRequest) error {
+	return c.do(ctx, http.MethodPut, "/tags/"+strconv.Itoa(id), nil, req, nil)
+}
+
+// DeleteTag removes the tag, ErrBadRequest is returned while banners have it
+func (c *Client) DeleteTag(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/tags/"+strconv.Itoa(id), nil, nil, nil)
+}
+
+// ListFeatureSchemas returns JSON Schemas of banner content of every feature having one
+func (c *Client) ListFeatureSchemas(ctx context.Context) ([]FeatureSchemaResponse, error) {
+	var schemas []FeatureSchemaResponse
+	if err := c.do(ctx, http.MethodGet, "/feature_schemas", nil, nil, &schemas); err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// GetFeatureSchema returns JSON Schema of banner content of the feature
+func (c *Client) GetFeatureSchema(ctx context.Context, featureId int) (*FeatureSchemaResponse, error) {
+	schema := &FeatureSchemaResponse{}
+	if err := c.do(ctx, http.MethodGet, "/feature_schemas/"+strconv.Itoa(featureId), nil, nil, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// PutFeatureSchema sets or replaces JSON Schema of the feature and reports existing banners violating it.
+// With dryRun the schema is only checked against the banners
+func (c *Client) PutFeatureSchema(ctx context.Context, featureId int, schema Content, dryRun bool) (*SchemaReportResponse, error) {
+	query := url.Values{}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+	report := &SchemaReportResponse{}
+	if err := c.do(ctx, http.MethodPut, "/feature_schemas/"+strconv.Itoa(featureId), query, schema, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// DeleteFeatureSchema removes JSON Schema of the feature, so content of its banners is not checked
+func (c *Client) DeleteFeatureSchema(ctx context.Context, featureId int) error {
+	return c.do(ctx, http.MethodDelete, "/feature_schemas/"+strconv.Itoa(featureId), nil, nil, nil)
+}
+
+func (c *Client) listRegistry(ctx context.Context, path string) ([]RegistryResponse, error) {
+	var entries []RegistryResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (c *Client) createRegistryEntry(ctx context.Context, path string, req *RegistryRequest) (int, error) {
+	resp := RegistryIdResponse{}
+	if err := c.do(ctx, http.MethodPost, path, nil, req, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Id == nil {
+		return 0, fmt.Errorf("%w: no id in response", ErrUnexpected)
+	}
+	return *resp.Id, nil
+}
+
+func (c *Client) getRegistryEntry(ctx context.Context, path string) (*RegistryResponse, error) {
+	entry := &RegistryResponse{}
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	bannerStreamEvent   = "banner"
+	notFoundStreamEvent = "not_found"
+	// maxStreamLine is the size of the longest line of the stream, which is the content of the banner
+	maxStreamLine = 4 << 20
+)
+
+// BannerStreamEvent is the banner the user gets after the change with the id, nil Content means there is none
+type BannerStreamEvent struct {
+	Id      string
+	Content map[string]interface{}
+}
+
+// StreamUserBanner calls fn with the banner of the user, see GetUserBanner, and then with every change of it until
+// ctx is done or fn fails. Lost connections are restored with backoff of the retry policy and the stream is resumed
+// after the last received event
+func (c *Client) StreamUserBanner(ctx context.Context, params *UserBannerStreamParams, fn func(event *BannerStreamEvent) error) error {
+	query := url.Values{}
+	setInt(query, "tag_id", params.TagId)
+	setInt(query, "feature_id", params.FeatureId)
+	setInts(query, "tag_ids", params.TagIds)
+	setString(query, "locale", params.Locale)
+	setString(query, "platform", params.Platform)
+	setString(query, "country", params.Country)
+	setString(query, "app_version", params.AppVersion)
+	target := c.baseURL + "/user_banner/stream?" + query.Encode()
+
+	lastEventId := ""
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return err
+			}
+		}
+		before := lastEventId
+		retry, err := c.readStream(ctx, target, &lastEventId, fn)
+		if lastEventId != before {
+			attempt = 0
+		}
+		if !retry || attempt >= c.retry.MaxRetries {
+			return err
+		}
+	}
+}
+
+// readStream passes events of the stream to fn and reports whether the stream may be read again after the error
+func (c *Client) readStream(ctx context.Context, target string, lastEventId *string, fn func(event *BannerStreamEvent) error) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastEventId != "" {
+		req.Header.Set("Last-Event-ID", *lastEventId)
+	}
+	c.authorize(req)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	if err = responseError(resp); err != nil {
+		return c.shouldRetry(http.MethodGet, err), err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, maxStreamLine)
+	var id, event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			// lines starting with a colon are comments
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "id":
+				id = value
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+			continue
+		}
+		ev := &BannerStreamEvent{Id: id}
+		switch event {
+		case bannerStreamEvent:
+			if err = json.Unmarshal([]byte(strings.Join(data, "\n")), &ev.Content); err != nil {
+				return false, fmt.Errorf("%w: %w", ErrUnexpected, err)
+			}
+		case notFoundStreamEvent:
+		default:
+			id, event, data = "", "", nil
+			continue
+		}
+		if id != "" {
+			*lastEventId = id
+		}
+		if err = fn(ev); err != nil {
+			return false, err
+		}
+		id, event, data = "", "", nil
+	}
+	err = scanner.Err()
+	switch {
+	case ctx.Err() != nil:
+		return false, ctx.Err()
+	case errors.Is(err, bufio.ErrTooLong):
+		return false, fmt.Errorf("%w: %w", ErrUnexpected, err)
+	case err == nil:
+		// the server closed the stream, it is resumed
+		return true, io.ErrUnexpectedEOF
+	default:
+		return true, err
+	}
+}
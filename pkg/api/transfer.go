@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Formats of ExportBanners and ImportBanners
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+var formatContentTypes = map[string]string{
+	FormatNDJSON: "application/x-ndjson",
+	FormatCSV:    "text/csv",
+}
+
+// ExportBanners writes banners matching params to w in the format of params, NDJSON by default, one BannerRecord
+// a line. The export is not retried, since some banners may be written already, an interrupted one fails
+func (c *Client) ExportBanners(ctx context.Context, params *ExportBannersParams, w io.Writer) error {
+	query := url.Values{}
+	if params != nil {
+		setString(query, "format", params.Format)
+		setInt(query, "feature_id", params.FeatureId)
+		setInt(query, "tag_id", params.TagId)
+	}
+	return c.send(ctx, http.MethodGet, c.target("/banner/export", query), "", nil, w)
+}
+
+// ImportBanners loads records of the export in the format. Nothing is saved if any record is rejected,
+// the report lists errors by line then. Params may be nil to fail on existing banners
+func (c *Client) ImportBanners(ctx context.Context, params *ImportBannersParams, format string, records io.Reader) (*ImportReportResponse, error) {
+	contentType, ok := formatContentTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	payload, err := io.ReadAll(records)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if params != nil {
+		setString(query, "mode", params.Mode)
+		setBool(query, "dry_run", params.DryRun)
+	}
+	report := &ImportReportResponse{}
+	if err = c.doRaw(ctx, http.MethodPost, "/banner/import", query, contentType, payload, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Headers of deliveries sent to webhooks
+const (
+	WebhookEventHeader     = "X-BannerFlow-Event"
+	WebhookDeliveryHeader  = "X-BannerFlow-Delivery"
+	WebhookTimestampHeader = "X-BannerFlow-Timestamp"
+	WebhookSignatureHeader = "X-BannerFlow-Signature"
+)
+
+// ErrInvalidSignature is returned by VerifyWebhookSignature for deliveries not sent by the service
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// VerifyWebhookSignature checks the delivery received by the webhook with the secret it was registered with.
+// Deliveries sent earlier than tolerance ago are rejected, so captured ones can't be replayed, zero tolerance
+// accepts any time
+func VerifyWebhookSignature(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrInvalidSignature)
+	}
+	if tolerance > 0 && time.Since(time.Unix(timestamp, 0)).Abs() > tolerance {
+		return fmt.Errorf("%w: timestamp is out of tolerance", ErrInvalidSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get(WebhookSignatureHeader))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ListWebhooks returns webhooks of the tenant, their secrets are never returned
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookResponse, error) {
+	var webhooks []WebhookResponse
+	if err := c.do(ctx, http.MethodGet, "/webhooks", nil, nil, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook registers the webhook and returns its id
+func (c *Client) CreateWebhook(ctx context.Context, req *WebhookRequest) (int, error) {
+	resp := WebhookIdResponse{}
+	if err := c.do(ctx, http.MethodPost, "/webhooks", nil, req, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Id == nil {
+		return 0, fmt.Errorf("%w: no webhook id in response", ErrUnexpected)
+	}
+	return *resp.Id, nil
+}
+
+// GetWebhook returns the webhook
+func (c *Client) GetWebhook(ctx context.Context, id int) (*WebhookResponse, error) {
+	webhook := &WebhookResponse{}
+	if err := c.do(ctx, http.MethodGet, "/webhooks/"+strconv.Itoa(id), nil, nil, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook replaces the webhook, nil Secret keeps the current one
+func (c *Client) UpdateWebhook(ctx context.Context, id int, req *WebhookRequest) error {
+	return c.do(ctx, http.MethodPut, "/webhooks/"+strconv.Itoa(id), nil, req, nil)
+}
+
+// DeleteWebhook removes the webhook with its deliveries
+func (c *Client) DeleteWebhook(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/webhooks/"+strconv.Itoa(id), nil, nil, nil)
+}
+
+// ListWebhookDeliveries returns deliveries of the webhook, the latest first. Params may be nil to get every one
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int, params *DeliveriesParams) ([]WebhookDeliveryResponse, error) {
+	query := url.Values{}
+	if params != nil {
+		setString(query, "status", params.Status)
+		setInt(query, "limit", params.Limit)
+		setInt(query, "offset", params.Offset)
+	}
+	var deliveries []WebhookDeliveryResponse
+	if err := c.do(ctx, http.MethodGet, "/webhooks/"+strconv.Itoa(id)+"/deliveries", query, nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ListDeadLetters returns deliveries of every webhook which ran out of attempts, params may be nil
+func (c *Client) ListDeadLetters(ctx context.Context, params *DeadLettersParams) ([]WebhookDeliveryResponse, error) {
+	query := url.Values{}
+	if params != nil {
+		setInt(query, "limit", params.Limit)
+		setInt(query, "offset", params.Offset)
+	}
+	var deliveries []WebhookDeliveryResponse
+	if err := c.do(ctx, http.MethodGet, "/webhooks/dead_letters", query, nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RetryWebhookDelivery sends the dead letter again
+func (c *Client) RetryWebhookDelivery(ctx context.Context, webhookId, deliveryId int) error {
+	path := "/webhooks/" + strconv.Itoa(webhookId) + "/deliveries/" + strconv.Itoa(deliveryId) + "/retry"
+	return c.do(ctx, http.MethodPost, path, nil, nil, nil)
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"BannerFlow/pkg/api"
 	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/suite"
 	"io"
 	"net/http"
@@ -31,6 +32,7 @@ func (t *E2ETest) SetupTest() {
 	t.client = &http.Client{}
 	t.getUserToken()
 	t.getAdminToken()
+	t.registerDefaults()
 	t.setDefaultBanners()
 }
 
@@ -88,6 +90,20 @@ func (t *E2ETest) getServerAddress() {
 	t.address = os.Getenv(envName)
 }
 
+// registerDefaults registers features and tags used by default banners, they may already exist from previous tests
+func (t *E2ETest) registerDefaults() {
+	for _, registry := range []string{"features", "tags"} {
+		for _, id := range []int{100, 200} {
+			body := fmt.Sprintf(`{"id": %d, "name": "%s_%d"}`, id, registry, id)
+			req := PrepareRequest(http.MethodPost, "http://"+t.address+"/"+registry, "application/json", t.adminToken, strings.NewReader(body))
+			r, err := t.client.Do(req)
+			if t.NoError(err, "Error sending request to server") {
+				r.Body.Close()
+			}
+		}
+	}
+}
+
 func (t *E2ETest) setDefaultBanners() {
 	Tests := []Test{
 		{